@@ -2,6 +2,7 @@ package curator
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -465,4 +466,58 @@ func TestCLI_ApplyBugFix(t *testing.T) {
 	if !strings.Contains(output3, "EXECUTION REPORT") {
 		t.Errorf("Status command should work without panic, got output: %s", output3)
 	}
-}
\ No newline at end of file
+}
+// TestCLI_MachineReadableOutput tests that --output=json prints only the
+// result document on stdout
+func TestCLI_MachineReadableOutput(t *testing.T) {
+	binaryPath := buildCLIBinary(t)
+	storeDir := t.TempDir()
+
+	run := func(args ...string) (string, string) {
+		cmd := exec.Command(binaryPath, args...)
+		cmd.Dir = storeDir
+		cmd.Env = append(os.Environ(),
+			"CURATOR_FILESYSTEM_TYPE=memory",
+			"CURATOR_AI_PROVIDER=mock",
+			"CURATOR_STORE_DIR="+storeDir,
+		)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("%v failed: %v\nStderr: %s", args, err, stderr.String())
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	// Verbose debug output must not end up in the document either
+	stdout, stderr := run("reorganize", "--output=json", "--verbose")
+
+	var envelope struct {
+		APIVersion string
+		Kind       string
+		Data       struct {
+			ID string `json:"id"`
+		}
+	}
+	if err := json.Unmarshal([]byte(stdout), &envelope); err != nil {
+		t.Fatalf("Expected stdout to be a JSON document: %v\n%s", err, stdout)
+	}
+	if envelope.APIVersion != OutputAPIVersion || envelope.Kind != string(KindReorganizationPlan) || envelope.Data.ID == "" {
+		t.Errorf("Unexpected envelope: %+v", envelope)
+	}
+	if !strings.Contains(stderr, "Plan saved with ID: "+envelope.Data.ID) || !strings.Contains(stderr, "DEBUG") {
+		t.Errorf("Expected progress and debug messages on stderr, got: %s", stderr)
+	}
+
+	stdout, _ = run("export", envelope.Data.ID)
+	if !json.Valid([]byte(stdout)) {
+		t.Errorf("Expected the export to be the only thing on stdout:\n%s", stdout)
+	}
+
+	stdout, _ = run("list-plans", "--output=yaml")
+	if !strings.HasPrefix(stdout, "apiVersion: curator/v1\nkind: PlanSummaryList\n") || !strings.Contains(stdout, "id: "+envelope.Data.ID) {
+		t.Errorf("Unexpected YAML output:\n%s", stdout)
+	}
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/dackerman/curator"
 	"github.com/spf13/cobra"
@@ -11,12 +17,49 @@ import (
 // Global configuration
 var config curator.Configuration
 
+// outputFormat is set from --output before any command runs
+var outputFormat = curator.OutputText
+
+// errInterrupted is the cancellation cause when the user interrupts curator
+var errInterrupted = errors.New("interrupted")
+
+// cancelTimeout releases the --timeout context once the command is done
+var cancelTimeout context.CancelFunc = func() {}
+
+// closers are closed once the command is done, e.g. to save the Drive ID cache
+// or checkpoint and release the SQLite store
+var closers []io.Closer
+
 var rootCmd = &cobra.Command{
 	Use:   "curator",
 	Short: "AI-powered file system organizer",
 	Long: `Curator uses AI to intelligently reorganize file systems by analyzing
 file structures, proposing reorganization plans with explanations,
 and executing approved changes while maintaining a complete audit trail.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		value, _ := cmd.Flags().GetString("output")
+		format, err := curator.ParseOutputFormat(value)
+		if err != nil {
+			return err
+		}
+		outputFormat = format
+
+		mode, _ := cmd.Flags().GetString("progress")
+		renderer, err := newProgressRenderer(mode, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		if renderer != nil {
+			progress, progressOutput = renderer, renderer
+		}
+
+		if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+		return nil
+	},
 }
 
 var reorganizeCmd = &cobra.Command{
@@ -26,6 +69,8 @@ var reorganizeCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		exclude, _ := cmd.Flags().GetString("exclude")
+		strict, _ := cmd.Flags().GetBool("strict")
+		tree, _ := cmd.Flags().GetBool("tree")
 		
 		// Apply command-line flag overrides to configuration
 		aiProvider, _ := cmd.Flags().GetString("ai-provider")
@@ -37,45 +82,105 @@ var reorganizeCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Close analyzer if it supports it (for Gemini)
 		if closer, ok := opts.Analyzer.(interface{ Close() error }); ok {
 			defer closer.Close()
 		}
 		
-		fmt.Printf("Scanning filesystem at /...\n")
-		fmt.Printf("Using %s filesystem...\n", finalConfig.FileSystem.Type)
+		fmt.Fprintf(messages(cmd), "Scanning filesystem at /...\n")
+		fmt.Fprintf(messages(cmd), "Using %s filesystem...\n", finalConfig.FileSystem.Type)
 		
-		fmt.Printf("Using %s AI provider...\n", finalConfig.AI.Provider)
+		fmt.Fprintf(messages(cmd), "Using %s AI provider...\n", finalConfig.AI.Provider)
 		
 		// Execute reorganize command
 		reorganizeOpts := curator.ReorganizeOptions{
 			DryRun:  dryRun,
 			Exclude: exclude,
+			Strict:  strict,
 		}
 		
-		plan, err := curator.ExecuteReorganize(opts, reorganizeOpts)
+		plan, err := curator.ExecuteReorganize(cmd.Context(), opts, reorganizeOpts)
 		if err != nil {
+			printPlanValidation(cmd, opts.Reporter, err)
 			return err
 		}
 		
 		// Display the plan
-		fmt.Println()
-		fmt.Print(opts.Reporter.FormatReorganizationPlan(plan))
+		if err := renderReorganizationPlan(cmd, opts, plan, tree); err != nil {
+			return err
+		}
 		
 		if !dryRun {
-			fmt.Printf("\nPlan saved with ID: %s\n", plan.ID)
+			fmt.Fprintf(messages(cmd), "\nPlan saved with ID: %s\n", plan.ID)
 		}
 		
 		return nil
 	},
 }
 
+// render prints a command result to stdout in the format chosen with
+// --output. text builds the human-readable version.
+func render(cmd *cobra.Command, kind curator.OutputKind, data interface{}, text func() string) error {
+	if outputFormat == curator.OutputText {
+		fmt.Fprint(cmd.OutOrStdout(), text())
+		return nil
+	}
+	return curator.WriteOutput(cmd.OutOrStdout(), outputFormat, kind, data)
+}
+
+// messages returns where status messages go: stdout next to text results, and
+// stderr with --output=json or yaml, so stdout carries only the document
+func messages(cmd *cobra.Command) io.Writer {
+	if outputFormat == curator.OutputText {
+		return cmd.OutOrStdout()
+	}
+	return cmd.ErrOrStderr()
+}
+
+// interruptedError reports an execution that stopped early, so curator exits
+// with an error after the log has been shown
+func interruptedError(ctx context.Context, execLog *curator.ExecutionLog) error {
+	if execLog.Status != curator.StatusInterrupted {
+		return nil
+	}
+	return fmt.Errorf("execution stopped before every operation ran: %w", context.Cause(ctx))
+}
+
+// printPlanValidation lists every issue when err rejected an invalid plan
+func printPlanValidation(cmd *cobra.Command, reporter *curator.Reporter, err error) {
+	var validationErr *curator.PlanValidationError
+	if errors.As(err, &validationErr) {
+		render(cmd, curator.KindPlanValidation, validationErr.Validation, func() string {
+			return "\n" + reporter.FormatPlanValidation(validationErr.Validation) + "\n"
+		})
+	}
+}
+
+// renderReorganizationPlan prints plan, and with tree also the folder trees
+// before and after it, simulated against the current filesystem
+func renderReorganizationPlan(cmd *cobra.Command, opts curator.CommandOptions, plan *curator.ReorganizationPlan, tree bool) error {
+	if !tree {
+		return render(cmd, curator.KindReorganizationPlan, plan, func() string {
+			return "\n" + opts.Reporter.FormatReorganizationPlan(plan)
+		})
+	}
+
+	simulation, err := curator.ExecuteSimulatePlan(cmd.Context(), opts, plan)
+	if err != nil {
+		return err
+	}
+	preview := &curator.PlanPreview{Plan: plan, Simulation: simulation}
+	return render(cmd, curator.KindPlanPreview, preview, func() string {
+		return "\n" + opts.Reporter.FormatReorganizationPlan(plan) + "\n" + opts.Reporter.FormatPlanSimulation(simulation)
+	})
+}
 
 var listPlansCmd = &cobra.Command{
 	Use:   "list-plans",
@@ -91,20 +196,22 @@ var listPlansCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Execute list-plans command
-		summaries, err := curator.ExecuteListPlans(opts)
+		summaries, err := curator.ExecuteListPlans(cmd.Context(), opts)
 		if err != nil {
 			return err
 		}
 		
-		fmt.Print(opts.Reporter.FormatPlanSummaries(summaries))
-		return nil
+		return render(cmd, curator.KindPlanSummaryList, summaries, func() string {
+			return opts.Reporter.FormatPlanSummaries(summaries)
+		})
 	},
 }
 
@@ -125,32 +232,215 @@ var showPlanCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Execute show-plan command
-		plan, err := curator.ExecuteShowPlan(opts, planID)
+		planType, _ := curator.FindPlanType(cmd.Context(), opts.Store, planID)
+		tree, _ := cmd.Flags().GetBool("tree")
+		return showPlan(cmd, opts, planType, planID, tree)
+	},
+}
+
+// showPlan prints whichever kind of plan has planID
+func showPlan(cmd *cobra.Command, opts curator.CommandOptions, planType curator.PlanType, planID string, tree bool) error {
+	ctx := cmd.Context()
+	switch planType {
+	case curator.PlanTypeCleanup:
+		plan, err := curator.ExecuteShowCleanupPlan(ctx, opts, planID)
+		if err != nil {
+			return err
+		}
+		return render(cmd, curator.KindCleanupPlan, plan, func() string {
+			return opts.Reporter.FormatCleanupPlan(plan)
+		})
+
+	case curator.PlanTypeDeduplication:
+		plan, err := curator.ExecuteShowDeduplicationPlan(ctx, opts, planID)
+		if err != nil {
+			return err
+		}
+		return render(cmd, curator.KindDeduplicationPlan, plan, func() string {
+			return opts.Reporter.FormatDeduplicationPlan(plan)
+		})
+
+	case curator.PlanTypeRenaming:
+		plan, err := curator.ExecuteShowRenamingPlan(ctx, opts, planID)
+		if err != nil {
+			return err
+		}
+		return render(cmd, curator.KindRenamingPlan, plan, func() string {
+			return opts.Reporter.FormatRenamingPlan(plan)
+		})
+
+	default:
+		plan, err := curator.ExecuteShowPlan(ctx, opts, planID)
+		if err != nil {
+			return err
+		}
+		return renderReorganizationPlan(cmd, opts, plan, tree)
+	}
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [plan-id]",
+	Short: "Approve, reject or edit the moves of a reorganization plan",
+	Long: `Steps through a reorganization plan one destination folder at a time. Each
+folder, or each move in it, can be accepted, rejected or sent somewhere else.
+The decisions are saved as a new plan; applying it runs only the approved
+moves. The original plan is left unchanged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		planID := args[0]
+		
+		// Apply command-line flag overrides to configuration
+		aiProvider, _ := cmd.Flags().GetString("ai-provider")
+		filesystem, _ := cmd.Flags().GetString("filesystem")
+		root, _ := cmd.Flags().GetString("root")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		
+		finalConfig := curator.OverrideConfiguration(config, aiProvider, filesystem, root)
+		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
+		
+		// Create command options
+		opts, err := createCommandOptions(cmd, finalConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create command options: %w", err)
+		}
+		opts.Verbose = verbose
+		opts.Progress = progress
+		
+		// Execute review command; prompts go to stderr with --output=json or yaml
+		plan, err := curator.ExecuteReview(cmd.Context(), opts, planID, cmd.InOrStdin(), messages(cmd))
 		if err != nil {
+			printPlanValidation(cmd, opts.Reporter, err)
+			return err
+		}
+		
+		if err := renderReorganizationPlan(cmd, opts, plan, false); err != nil {
 			return err
 		}
 		
-		fmt.Print(opts.Reporter.FormatReorganizationPlan(plan))
+		fmt.Fprintf(messages(cmd), "\nReviewed plan saved with ID: %s\n", plan.ID)
+		return nil
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [plan-id]",
+	Short: "Write a saved plan to stdout as JSON or CSV",
+	Long: `Writes a saved plan to stdout. JSON exports can be reviewed, edited and
+loaded into another store with 'curator import'; CSV exports list one
+operation per row for review only.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		planID := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		
+		
+		// Apply command-line flag overrides to configuration
+		aiProvider, _ := cmd.Flags().GetString("ai-provider")
+		filesystem, _ := cmd.Flags().GetString("filesystem")
+		root, _ := cmd.Flags().GetString("root")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		
+		finalConfig := curator.OverrideConfiguration(config, aiProvider, filesystem, root)
+		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
+		
+		// Create command options
+		opts, err := createCommandOptions(cmd, finalConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create command options: %w", err)
+		}
+		opts.Verbose = verbose
+		opts.Progress = progress
+		
+		// Execute export command
+		return curator.ExecuteExport(cmd.Context(), opts, planID, curator.ExportFormat(format), cmd.OutOrStdout())
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Validate and save a plan exported with 'curator export'",
+	Long: `Reads a plan exported as JSON ('-' reads stdin), validates it against the
+current filesystem and saves it so it can be applied. The plan keeps its ID
+unless --new-id is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newID, _ := cmd.Flags().GetBool("new-id")
+		
+		input := cmd.InOrStdin()
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open plan file: %w", err)
+			}
+			defer file.Close()
+			input = file
+		}
+		
+		// Apply command-line flag overrides to configuration
+		aiProvider, _ := cmd.Flags().GetString("ai-provider")
+		filesystem, _ := cmd.Flags().GetString("filesystem")
+		root, _ := cmd.Flags().GetString("root")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		
+		finalConfig := curator.OverrideConfiguration(config, aiProvider, filesystem, root)
+		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
+		
+		// Create command options
+		opts, err := createCommandOptions(cmd, finalConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create command options: %w", err)
+		}
+		opts.Verbose = verbose
+		opts.Progress = progress
+		
+		// Execute import command
+		summary, err := curator.ExecuteImport(cmd.Context(), opts, input, curator.ImportOptions{NewID: newID})
+		if err != nil {
+			printPlanValidation(cmd, opts.Reporter, err)
+			return err
+		}
+		
+		if err := showPlan(cmd, opts, summary.Type, summary.ID, false); err != nil {
+			return err
+		}
+		
+		fmt.Fprintf(messages(cmd), "\nPlan imported with ID: %s\n", summary.ID)
 		return nil
 	},
 }
 
 var applyCmd = &cobra.Command{
 	Use:   "apply [plan-id]",
-	Short: "Execute a reorganization plan",
+	Short: "Execute a saved reorganization, cleanup, renaming or deduplication plan",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		planID := args[0]
 		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		resume, _ := cmd.Flags().GetBool("resume")
+		retrySkipped, _ := cmd.Flags().GetBool("retry-skipped")
+		only, _ := cmd.Flags().GetStringSlice("only")
+		skipDestination, _ := cmd.Flags().GetStringSlice("skip-destination")
+		onlyType, _ := cmd.Flags().GetStringSlice("only-type")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
 		
-		fmt.Printf("Executing plan %s...\n", planID)
+		filter, err := curator.NewMoveFilter(only, skipDestination, onlyType)
+		if err != nil {
+			return err
+		}
+		
+		if resume {
+			fmt.Fprintf(messages(cmd), "Resuming plan %s...\n", planID)
+		} else {
+			fmt.Fprintf(messages(cmd), "Executing plan %s...\n", planID)
+		}
 		
 		// Apply command-line flag overrides to configuration
 		aiProvider, _ := cmd.Flags().GetString("ai-provider")
@@ -162,26 +452,35 @@ var applyCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Execute apply command
 		applyOpts := curator.ApplyOptions{
-			FailFast: failFast,
+			FailFast:     failFast,
+			Resume:       resume,
+			RetrySkipped: retrySkipped,
+			Filter:       filter,
+			Concurrency:  concurrency,
 		}
 		
-		execLog, err := curator.ExecuteApply(opts, planID, applyOpts)
+		execLog, err := curator.ExecuteApply(cmd.Context(), opts, planID, applyOpts)
 		if err != nil {
+			printPlanValidation(cmd, opts.Reporter, err)
 			return err
 		}
 		
 		// Display execution results
-		fmt.Println()
-		fmt.Print(opts.Reporter.FormatExecutionLog(execLog))
-		return nil
+		if err := render(cmd, curator.KindExecutionLog, execLog, func() string {
+			return "\n" + opts.Reporter.FormatExecutionLog(execLog)
+		}); err != nil {
+			return err
+		}
+		return interruptedError(cmd.Context(), execLog)
 	},
 }
 
@@ -202,26 +501,44 @@ var statusCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
+		// With --all, show every attempt instead of the latest one
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			attempts, err := curator.ExecutePlanHistory(cmd.Context(), opts, planID)
+			if err != nil {
+				return err
+			}
+			return render(cmd, curator.KindExecutionLogList, attempts, func() string {
+				var text strings.Builder
+				for _, attempt := range attempts {
+					text.WriteString(opts.Reporter.FormatExecutionLog(attempt) + "\n")
+				}
+				return text.String()
+			})
+		}
+
 		// Execute status command
-		execLog, err := curator.ExecuteStatus(opts, planID)
+		execLog, err := curator.ExecuteStatus(cmd.Context(), opts, planID)
 		if err != nil {
 			return err
 		}
 		
-		fmt.Print(opts.Reporter.FormatExecutionLog(execLog))
-		return nil
+		return render(cmd, curator.KindExecutionLog, execLog, func() string {
+			return opts.Reporter.FormatExecutionLog(execLog)
+		})
 	},
 }
 
 var historyCmd = &cobra.Command{
-	Use:   "history",
-	Short: "Show execution history",
+	Use:   "history [plan-id]",
+	Short: "Show execution history, optionally only the attempts for one plan",
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Apply command-line flag overrides to configuration
 		aiProvider, _ := cmd.Flags().GetString("ai-provider")
@@ -233,32 +550,186 @@ var historyCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Execute history command
-		logs, err := curator.ExecuteHistory(opts)
+		var logs []*curator.ExecutionLog
+		if len(args) == 1 {
+			logs, err = curator.ExecutePlanHistory(cmd.Context(), opts, args[0])
+		} else {
+			logs, err = curator.ExecuteHistory(cmd.Context(), opts)
+		}
 		if err != nil {
 			return err
 		}
 		
-		fmt.Print(opts.Reporter.FormatExecutionHistory(logs))
+		return render(cmd, curator.KindExecutionLogList, logs, func() string {
+			return opts.Reporter.FormatExecutionHistory(logs)
+		})
+	},
+}
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Finish or record operations left pending by an interrupted apply",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Apply command-line flag overrides to configuration
+		aiProvider, _ := cmd.Flags().GetString("ai-provider")
+		filesystem, _ := cmd.Flags().GetString("filesystem")
+		root, _ := cmd.Flags().GetString("root")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		
+		finalConfig := curator.OverrideConfiguration(config, aiProvider, filesystem, root)
+		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
+		
+		// Create command options
+		opts, err := createCommandOptions(cmd, finalConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create command options: %w", err)
+		}
+		opts.Verbose = verbose
+		opts.Progress = progress
+		
+		report, err := curator.ExecuteRecover(cmd.Context(), opts)
+		if err != nil {
+			return err
+		}
+		
+		if err := render(cmd, curator.KindRecoveryReport, report, func() string {
+			return opts.Reporter.FormatRecoveryReport(report)
+		}); err != nil {
+			return err
+		}
+		for _, op := range report.Operations {
+			if op.Outcome == curator.RecoveryFailed {
+				return fmt.Errorf("some operations could not be recovered")
+			}
+		}
 		return nil
 	},
 }
 
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Maintain the operation store",
+}
+
+var storeVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the operation store for corrupt records",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := storeCommandOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		report, err := curator.ExecuteStoreVerify(opts)
+		if err != nil {
+			return err
+		}
+
+		if err := render(cmd, curator.KindStoreReport, report, func() string {
+			return opts.Reporter.FormatStoreReport(report)
+		}); err != nil {
+			return err
+		}
+		if len(report.Issues) > 0 {
+			return fmt.Errorf("found %d corrupt records", len(report.Issues))
+		}
+		return nil
+	},
+}
+
+var storeRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Quarantine corrupt records in the operation store",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := storeCommandOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		report, err := curator.ExecuteStoreRepair(opts)
+		if err != nil {
+			return err
+		}
+
+		return render(cmd, curator.KindStoreReport, report, func() string {
+			return opts.Reporter.FormatStoreReport(report)
+		})
+	},
+}
+
+// storeCommandOptions builds command options for the store subcommands
+func storeCommandOptions(cmd *cobra.Command) (curator.CommandOptions, error) {
+	// Apply command-line flag overrides to configuration
+	aiProvider, _ := cmd.Flags().GetString("ai-provider")
+	filesystem, _ := cmd.Flags().GetString("filesystem")
+	root, _ := cmd.Flags().GetString("root")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	finalConfig := curator.OverrideConfiguration(config, aiProvider, filesystem, root)
+	finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
+
+	opts, err := createCommandOptions(cmd, finalConfig)
+	if err != nil {
+		return curator.CommandOptions{}, fmt.Errorf("failed to create command options: %w", err)
+	}
+	opts.Verbose = verbose
+	opts.Progress = progress
+
+	return opts, nil
+}
+
 var rollbackCmd = &cobra.Command{
 	Use:   "rollback [plan-id]",
 	Short: "Rollback a previously executed plan",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		planID := args[0]
-		fmt.Printf("Rollback functionality not yet implemented for plan %s\n", planID)
-		fmt.Println("This will be implemented in a future phase.")
-		return nil
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		
+		fmt.Fprintf(messages(cmd), "Rolling back plan %s...\n", planID)
+		
+		// Apply command-line flag overrides to configuration
+		aiProvider, _ := cmd.Flags().GetString("ai-provider")
+		filesystem, _ := cmd.Flags().GetString("filesystem")
+		root, _ := cmd.Flags().GetString("root")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		
+		finalConfig := curator.OverrideConfiguration(config, aiProvider, filesystem, root)
+		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
+		
+		// Create command options
+		opts, err := createCommandOptions(cmd, finalConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create command options: %w", err)
+		}
+		opts.Verbose = verbose
+		opts.Progress = progress
+		
+		// Execute rollback command
+		rollbackOpts := curator.RollbackOptions{
+			FailFast: failFast,
+		}
+		
+		execLog, err := curator.ExecuteRollback(cmd.Context(), opts, planID, rollbackOpts)
+		if err != nil {
+			return err
+		}
+		
+		// Display rollback results
+		if err := render(cmd, curator.KindExecutionLog, execLog, func() string {
+			return "\n" + opts.Reporter.FormatExecutionLog(execLog)
+		}); err != nil {
+			return err
+		}
+		return interruptedError(cmd.Context(), execLog)
 	},
 }
 
@@ -266,9 +737,15 @@ var deduplicateCmd = &cobra.Command{
 	Use:   "deduplicate",
 	Short: "Find and remove duplicate files",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		_, _ = cmd.Flags().GetBool("dry-run") // dry-run not yet implemented for duplicates
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		exclude, _ := cmd.Flags().GetString("exclude")
+		keep, _ := cmd.Flags().GetString("keep")
+		prefer, _ := cmd.Flags().GetStringSlice("prefer")
+		action, _ := cmd.Flags().GetString("action")
+		quarantineDir, _ := cmd.Flags().GetString("quarantine-dir")
+		verify, _ := cmd.Flags().GetBool("verify")
 		
-		fmt.Println("Scanning for duplicate files...")
+		fmt.Fprintln(messages(cmd), "Scanning for duplicate files...")
 		
 		// Apply command-line flag overrides to configuration
 		aiProvider, _ := cmd.Flags().GetString("ai-provider")
@@ -280,27 +757,47 @@ var deduplicateCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Close analyzer if it supports it (for Gemini)
 		if closer, ok := opts.Analyzer.(interface{ Close() error }); ok {
 			defer closer.Close()
 		}
 		
-		fmt.Printf("Using %s filesystem...\n", finalConfig.FileSystem.Type)
-		fmt.Printf("Using %s AI provider...\n", finalConfig.AI.Provider)
+		fmt.Fprintf(messages(cmd), "Using %s filesystem...\n", finalConfig.FileSystem.Type)
+		fmt.Fprintf(messages(cmd), "Using %s AI provider...\n", finalConfig.AI.Provider)
 		
 		// Execute deduplicate command
-		report, err := curator.ExecuteDeduplicate(opts)
+		dedupOpts := curator.DeduplicateOptions{
+			DryRun:         dryRun,
+			Exclude:        exclude,
+			Keep:           curator.KeeperPolicy(keep),
+			PreferredRoots: prefer,
+			Action:         curator.DuplicateAction(action),
+			QuarantineDir:  quarantineDir,
+			VerifyBytes:    verify,
+		}
+		
+		plan, err := curator.ExecuteDeduplicate(cmd.Context(), opts, dedupOpts)
 		if err != nil {
 			return err
 		}
 		
-		fmt.Print(opts.Reporter.FormatDuplicationReport(report))
+		if err := render(cmd, curator.KindDeduplicationPlan, plan, func() string {
+			return opts.Reporter.FormatDeduplicationPlan(plan)
+		}); err != nil {
+			return err
+		}
+
+		if !dryRun {
+			fmt.Fprintf(messages(cmd), "\nPlan saved with ID: %s\n", plan.ID)
+		}
+
 		return nil
 	},
 }
@@ -309,9 +806,10 @@ var cleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Clean up junk and unnecessary files",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		_, _ = cmd.Flags().GetBool("dry-run") // dry-run not yet implemented for cleanup
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		exclude, _ := cmd.Flags().GetString("exclude")
 		
-		fmt.Println("Scanning for junk files...")
+		fmt.Fprintln(messages(cmd), "Scanning for junk files...")
 		
 		// Apply command-line flag overrides to configuration
 		aiProvider, _ := cmd.Flags().GetString("ai-provider")
@@ -323,27 +821,42 @@ var cleanupCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Close analyzer if it supports it (for Gemini)
 		if closer, ok := opts.Analyzer.(interface{ Close() error }); ok {
 			defer closer.Close()
 		}
 		
-		fmt.Printf("Using %s filesystem...\n", finalConfig.FileSystem.Type)
-		fmt.Printf("Using %s AI provider...\n", finalConfig.AI.Provider)
+		fmt.Fprintf(messages(cmd), "Using %s filesystem...\n", finalConfig.FileSystem.Type)
+		fmt.Fprintf(messages(cmd), "Using %s AI provider...\n", finalConfig.AI.Provider)
 		
 		// Execute cleanup command
-		plan, err := curator.ExecuteCleanup(opts)
+		cleanupOpts := curator.CleanupOptions{
+			DryRun:  dryRun,
+			Exclude: exclude,
+		}
+		
+		plan, err := curator.ExecuteCleanup(cmd.Context(), opts, cleanupOpts)
 		if err != nil {
 			return err
 		}
 		
-		fmt.Print(opts.Reporter.FormatCleanupPlan(plan))
+		if err := render(cmd, curator.KindCleanupPlan, plan, func() string {
+			return opts.Reporter.FormatCleanupPlan(plan)
+		}); err != nil {
+			return err
+		}
+
+		if !dryRun {
+			fmt.Fprintf(messages(cmd), "\nPlan saved with ID: %s\n", plan.ID)
+		}
+
 		return nil
 	},
 }
@@ -352,10 +865,11 @@ var renameCmd = &cobra.Command{
 	Use:   "rename",
 	Short: "Standardize file naming conventions",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		_, _ = cmd.Flags().GetBool("dry-run") // dry-run not yet implemented for rename
-		_, _ = cmd.Flags().GetString("pattern") // pattern not yet implemented
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		pattern, _ := cmd.Flags().GetString("pattern")
+		exclude, _ := cmd.Flags().GetString("exclude")
 		
-		fmt.Println("Scanning for files to rename...")
+		fmt.Fprintln(messages(cmd), "Scanning for files to rename...")
 		
 		// Apply command-line flag overrides to configuration
 		aiProvider, _ := cmd.Flags().GetString("ai-provider")
@@ -367,45 +881,41 @@ var renameCmd = &cobra.Command{
 		finalConfig = curator.PopulateConfigurationFromEnvironment(finalConfig)
 		
 		// Create command options
-		opts, err := curator.CreateCommandOptions(finalConfig)
+		opts, err := createCommandOptions(cmd, finalConfig)
 		if err != nil {
 			return fmt.Errorf("failed to create command options: %w", err)
 		}
 		opts.Verbose = verbose
+		opts.Progress = progress
 		
 		// Close analyzer if it supports it (for Gemini)
 		if closer, ok := opts.Analyzer.(interface{ Close() error }); ok {
 			defer closer.Close()
 		}
 		
-		fmt.Printf("Using %s filesystem...\n", finalConfig.FileSystem.Type)
-		fmt.Printf("Using %s AI provider...\n", finalConfig.AI.Provider)
+		fmt.Fprintf(messages(cmd), "Using %s filesystem...\n", finalConfig.FileSystem.Type)
+		fmt.Fprintf(messages(cmd), "Using %s AI provider...\n", finalConfig.AI.Provider)
 		
 		// Execute rename command
-		plan, err := curator.ExecuteRename(opts)
+		renameOpts := curator.RenameOptions{
+			DryRun:  dryRun,
+			Pattern: pattern,
+			Exclude: exclude,
+		}
+		
+		plan, err := curator.ExecuteRename(cmd.Context(), opts, renameOpts)
 		if err != nil {
 			return err
 		}
 		
-		// Simple text output for renaming plan
-		fmt.Printf("\nRENAMING PLAN\n")
-		fmt.Printf("=============\n")
-		fmt.Printf("Plan ID: %s\n", plan.ID)
-		fmt.Printf("Generated: %s\n\n", plan.Timestamp.Format("2006-01-02 15:04:05"))
-		
-		if len(plan.Renames) == 0 {
-			fmt.Println("🎉 All files already follow consistent naming conventions!")
-		} else {
-			fmt.Printf("Files to rename: %d\n", len(plan.Renames))
-			fmt.Printf("Pattern: %s\n\n", plan.Summary.Pattern)
-			
-			for i, rename := range plan.Renames {
-				if i >= 10 {
-					fmt.Printf("\n[... %d more files to rename ...]\n", len(plan.Renames)-10)
-					break
-				}
-				fmt.Printf("• %s → %s\n", rename.OldName, rename.NewName)
-			}
+		if err := render(cmd, curator.KindRenamingPlan, plan, func() string {
+			return "\n" + opts.Reporter.FormatRenamingPlan(plan)
+		}); err != nil {
+			return err
+		}
+
+		if !dryRun {
+			fmt.Fprintf(messages(cmd), "\nPlan saved with ID: %s\n", plan.ID)
 		}
 		
 		return nil
@@ -420,35 +930,118 @@ func init() {
 	rootCmd.PersistentFlags().String("ai-provider", "", "AI provider to use (mock, gemini) - overrides CURATOR_AI_PROVIDER")
 	rootCmd.PersistentFlags().String("filesystem", "", "Filesystem type to use (memory, local, googledrive) - overrides CURATOR_FILESYSTEM_TYPE")
 	rootCmd.PersistentFlags().String("root", "", "Root path for local filesystem - overrides CURATOR_FILESYSTEM_ROOT")
+	rootCmd.PersistentFlags().String("output", string(curator.OutputText), "Output format: text, json or yaml. json and yaml print one versioned document to stdout; progress messages go to stderr")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Stop after this long (e.g. 30m); apply finishes the operation in progress first. 0 means no limit")
+	rootCmd.PersistentFlags().String("progress", "auto", "Progress display on stderr: auto (a bar on a terminal, lines otherwise), bar, lines or off")
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable debug logging (shows files found, AI prompts/responses, planned actions)")
 	
 	// Global flags
 	reorganizeCmd.Flags().Bool("dry-run", false, "Generate plan without executing")
-	reorganizeCmd.Flags().String("exclude", "", "Comma-separated list of patterns to exclude")
+	reorganizeCmd.Flags().Bool("tree", false, "Also show the folder trees before and after the plan")
+	reorganizeCmd.Flags().Bool("strict", false, "Reject a plan with invalid moves instead of repairing it")
+	reorganizeCmd.Flags().String("exclude", "", "Comma-separated glob patterns to exclude from the scan (e.g. '/Private/*,**/node_modules')")
 	
 	applyCmd.Flags().Bool("fail-fast", false, "Stop on first error")
+	applyCmd.Flags().Bool("resume", false, "Continue the plan's last execution: skip completed operations and retry failed ones")
+	applyCmd.Flags().Bool("retry-skipped", false, "With --resume, also retry operations the last execution skipped")
+	applyCmd.Flags().StringSlice("only", nil, "Only run these moves of a reorganization plan: IDs or ranges like 'move-1..move-40'")
+	applyCmd.Flags().StringSlice("skip-destination", nil, "Skip moves whose destination matches these glob patterns; ones with a '/' start at the root (e.g. 'Other/**')")
+	applyCmd.Flags().StringSlice("only-type", nil, "Only run moves of these types: CREATE_FOLDER, FILE_MOVE, FOLDER_MOVE, REMOVE_FOLDER")
+	applyCmd.Flags().Int("concurrency", 1, "How many operations to run at once; operations on overlapping paths still run in plan order (try 8 for Google Drive)")
+	statusCmd.Flags().Bool("all", false, "Show every execution attempt for the plan, newest first")
+	rollbackCmd.Flags().Bool("fail-fast", false, "Stop on first error")
 	
-	deduplicateCmd.Flags().Bool("dry-run", false, "Show duplicates without removing")
-	cleanupCmd.Flags().Bool("dry-run", false, "Show cleanup plan without executing")
-	renameCmd.Flags().Bool("dry-run", false, "Show rename plan without executing")
-	renameCmd.Flags().String("pattern", "consistent-naming", "Naming pattern to use")
+	deduplicateCmd.Flags().Bool("dry-run", false, "Show duplicates without saving a plan")
+	deduplicateCmd.Flags().String("keep", string(curator.KeepOldest), "Which copy to keep: oldest, newest, shortest-path or preferred")
+	deduplicateCmd.Flags().StringSlice("prefer", nil, "Comma-separated preferred roots for --keep=preferred, most preferred first")
+	deduplicateCmd.Flags().String("action", string(curator.DuplicateQuarantine), "What to do with redundant copies: quarantine or delete")
+	deduplicateCmd.Flags().String("quarantine-dir", curator.DefaultQuarantineDir, "Folder that quarantined copies are moved into")
+	deduplicateCmd.Flags().Bool("verify", false, "Confirm duplicates by comparing file contents byte by byte")
+	showPlanCmd.Flags().Bool("tree", false, "Also show the folder trees before and after a reorganization plan, simulated against the current filesystem")
+	exportCmd.Flags().String("format", string(curator.ExportJSON), "Export format: json (can be imported) or csv (one row per operation, for review)")
+	importCmd.Flags().Bool("new-id", false, "Save the plan under a new ID instead of the one in the file")
+	deduplicateCmd.Flags().String("exclude", "", "Comma-separated glob patterns to exclude from the scan")
+	cleanupCmd.Flags().Bool("dry-run", false, "Show cleanup plan without saving it")
+	cleanupCmd.Flags().String("exclude", "", "Comma-separated glob patterns to exclude from the scan")
+	renameCmd.Flags().Bool("dry-run", false, "Show rename plan without saving it")
+	renameCmd.Flags().String("exclude", "", "Comma-separated glob patterns to exclude from the scan")
+	renameCmd.Flags().String("pattern", curator.PatternConsistentNaming, "Naming pattern to use: "+strings.Join(curator.NamingPatterns(), ", "))
 	
 	// Add commands to root
+	storeCmd.AddCommand(storeVerifyCmd)
+	storeCmd.AddCommand(storeRepairCmd)
+
 	rootCmd.AddCommand(reorganizeCmd)
 	rootCmd.AddCommand(listPlansCmd)
 	rootCmd.AddCommand(showPlanCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(recoverCmd)
+	rootCmd.AddCommand(storeCmd)
 	rootCmd.AddCommand(rollbackCmd)
 	rootCmd.AddCommand(deduplicateCmd)
 	rootCmd.AddCommand(cleanupCmd)
 	rootCmd.AddCommand(renameCmd)
 }
 
+// interruptibleContext returns a context that is cancelled by the first
+// SIGINT or SIGTERM, letting a running apply finish the operation in progress
+// and save its log. A second signal exits immediately.
+func interruptibleContext() context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Fprintln(os.Stderr, "\nInterrupted: finishing the current operation, press Ctrl-C again to quit immediately")
+		cancel(errInterrupted)
+
+		<-signals
+		os.Exit(130)
+	}()
+
+	return ctx
+}
+
+// createCommandOptions creates the options for a command and remembers what
+// must be closed when it is done
+func createCommandOptions(cmd *cobra.Command, config curator.Configuration) (curator.CommandOptions, error) {
+	opts, err := curator.CreateCommandOptions(config)
+	if err != nil {
+		return opts, err
+	}
+	opts.Messages = messages(cmd)
+	if closer, ok := opts.FileSystem.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+	if closer, ok := opts.Store.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+	return opts, nil
+}
+
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx := interruptibleContext()
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	for _, closer := range closers {
+		if closeErr := closer.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", closeErr)
+		}
+	}
+	if progressOutput != nil {
+		progressOutput.finish()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if errors.Is(context.Cause(ctx), errInterrupted) {
+			os.Exit(130)
+		}
 		os.Exit(1)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dackerman/curator"
+)
+
+// progress receives the progress of scans and executions; nil with --progress=off
+var progress curator.ProgressReporter
+
+// progressOutput is the renderer behind progress, if any
+var progressOutput *progressRenderer
+
+// progressRenderer writes progress events to a terminal as a redrawn bar, or
+// elsewhere as plain lines that are easy to follow in a log
+type progressRenderer struct {
+	w        io.Writer
+	reporter *curator.Reporter
+	bar      bool          // Redraw one line instead of printing a line per update
+	interval time.Duration // Minimum time between updates within a phase
+	last     time.Time
+	phase    curator.ProgressPhase
+	open     bool // A bar is drawn and its line not ended yet
+}
+
+// newProgressRenderer returns the renderer for a --progress mode: auto, bar,
+// lines or off. Auto draws a bar when w is a terminal and prints lines
+// otherwise. It returns nil for off.
+func newProgressRenderer(mode string, w io.Writer) (*progressRenderer, error) {
+	switch mode {
+	case "auto":
+		if isTerminal(w) {
+			mode = "bar"
+		} else {
+			mode = "lines"
+		}
+	case "bar", "lines":
+	case "off":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown progress mode %q: use auto, bar, lines or off", mode)
+	}
+
+	if mode == "bar" {
+		return &progressRenderer{w: w, reporter: curator.NewReporter(), bar: true, interval: 100 * time.Millisecond}, nil
+	}
+	return &progressRenderer{w: w, reporter: curator.NewReporter(), interval: 5 * time.Second}, nil
+}
+
+// isTerminal reports whether w is a terminal rather than a file or a pipe
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Progress implements curator.ProgressReporter
+func (p *progressRenderer) Progress(event curator.ProgressEvent) {
+	now := time.Now()
+	if !event.Done && event.Phase == p.phase && now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last = now
+	p.phase = event.Phase
+
+	line := p.reporter.FormatProgress(event)
+	if !p.bar {
+		fmt.Fprintln(p.w, line)
+		return
+	}
+
+	if fraction, ok := event.Fraction(); ok {
+		line = progressBar(fraction) + " " + line
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s", line)
+	p.open = !event.Done
+	if event.Done {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// finish ends a bar left by a phase that stopped early, so later output
+// starts on its own line
+func (p *progressRenderer) finish() {
+	if p.open {
+		fmt.Fprintln(p.w)
+		p.open = false
+	}
+}
+
+// progressBar draws fraction as a fixed-width bar with a percentage
+func progressBar(fraction float64) string {
+	const width = 24
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction*width + 0.5)
+	return fmt.Sprintf("[%s%s] %3.0f%%", strings.Repeat("#", filled), strings.Repeat("-", width-filled), fraction*100)
+}
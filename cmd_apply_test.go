@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"strings"
 	"testing"
 )
@@ -19,7 +20,7 @@ func TestApplyCommand_NilExecutionEngine(t *testing.T) {
 		Rationale: "Test plan",
 	}
 	
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save test plan: %v", err)
 	}
@@ -46,7 +47,7 @@ func TestApplyCommand_NilExecutionEngine(t *testing.T) {
 	}()
 	
 	// This line should panic with nil pointer dereference
-	_ = engine.ResumePendingOperations()
+	_ = engine.ResumePendingOperations(context.Background())
 }
 
 // TestApplyCommand_ProperInitialization tests that ExecutionEngine works correctly
@@ -60,7 +61,7 @@ func TestApplyCommand_ProperInitialization(t *testing.T) {
 	engine := NewExecutionEngine(fs, store)
 	
 	// This should NOT panic
-	err := engine.ResumePendingOperations()
+	err := engine.ResumePendingOperations(context.Background())
 	if err != nil {
 		t.Errorf("Expected no error from ResumePendingOperations, got: %v", err)
 	}
@@ -73,13 +74,13 @@ func TestApplyCommand_ProperInitialization(t *testing.T) {
 		Rationale: "Test plan",
 	}
 	
-	err = store.SavePlan(plan)
+	err = store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save test plan: %v", err)
 	}
 	
 	// This should also work without panic
-	execLog, err := engine.ExecutePlan("test-plan-456", false)
+	execLog, err := engine.ExecutePlan(context.Background(), "test-plan-456", false)
 	if err != nil {
 		t.Errorf("Expected no error from ExecutePlan, got: %v", err)
 	}
@@ -102,7 +103,7 @@ func TestApplyCommand_WorkflowIntegration(t *testing.T) {
 	
 	// Add sample files
 	fs.AddFile("/test.txt", []byte("test content"), "text/plain")
-	fs.CreateFolder("/Documents")
+	fs.CreateFolder(context.Background(), "/Documents")
 	
 	// Create a plan with actual moves
 	plan := &ReorganizationPlan{
@@ -120,7 +121,7 @@ func TestApplyCommand_WorkflowIntegration(t *testing.T) {
 		Rationale: "Test workflow",
 	}
 	
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save test plan: %v", err)
 	}
@@ -129,7 +130,7 @@ func TestApplyCommand_WorkflowIntegration(t *testing.T) {
 	engine := NewExecutionEngine(fs, store)
 	
 	// Execute the plan
-	execLog, err := engine.ExecutePlan("workflow-test-789", false)
+	execLog, err := engine.ExecutePlan(context.Background(), "workflow-test-789", false)
 	if err != nil {
 		t.Fatalf("Failed to execute plan: %v", err)
 	}
@@ -148,7 +149,7 @@ func TestApplyCommand_WorkflowIntegration(t *testing.T) {
 	}
 	
 	// Verify the file was actually moved
-	exists, err := fs.Exists("/test.txt")
+	exists, err := fs.Exists(context.Background(), "/test.txt")
 	if err != nil {
 		t.Fatalf("Error checking original file existence: %v", err)
 	}
@@ -156,7 +157,7 @@ func TestApplyCommand_WorkflowIntegration(t *testing.T) {
 		t.Error("Original file should no longer exist after move")
 	}
 	
-	exists, err = fs.Exists("/Documents/test.txt")
+	exists, err = fs.Exists(context.Background(), "/Documents/test.txt")
 	if err != nil {
 		t.Fatalf("Error checking moved file existence: %v", err)
 	}
@@ -165,7 +166,7 @@ func TestApplyCommand_WorkflowIntegration(t *testing.T) {
 	}
 	
 	// Test status retrieval (like the fixed status command does)
-	statusLog, err := engine.GetExecutionStatus("workflow-test-789")
+	statusLog, err := engine.GetExecutionStatus(context.Background(), "workflow-test-789")
 	if err != nil {
 		t.Fatalf("Failed to get execution status: %v", err)
 	}
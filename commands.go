@@ -1,8 +1,15 @@
 package curator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // CommandOptions holds common options for all commands
@@ -12,69 +19,137 @@ type CommandOptions struct {
 	Analyzer   AIAnalyzer
 	Reporter   *Reporter
 	Verbose    bool
+	Progress   ProgressReporter // Receives scan, hash and apply progress; may be nil
+	Messages   io.Writer        // Receives verbose debug output; nil means stderr
+}
+
+// messages returns where verbose debug output goes
+func (o CommandOptions) messages() io.Writer {
+	if o.Messages != nil {
+		return o.Messages
+	}
+	return os.Stderr
 }
 
 // ReorganizeOptions holds options specific to the reorganize command
 type ReorganizeOptions struct {
 	DryRun  bool
 	Exclude string
+	Strict  bool // Reject a plan with invalid moves instead of repairing it
+}
+
+// DeduplicateOptions holds options specific to the deduplicate command
+type DeduplicateOptions struct {
+	DryRun         bool
+	Exclude        string
+	Keep           KeeperPolicy    // Defaults to KeepOldest
+	PreferredRoots []string        // Used by KeepPreferred, most preferred first
+	Action         DuplicateAction // Defaults to DuplicateQuarantine
+	QuarantineDir  string          // Defaults to DefaultQuarantineDir
+	VerifyBytes    bool            // Compare duplicate contents byte by byte, not just by hash
+}
+
+// CleanupOptions holds options specific to the cleanup command
+type CleanupOptions struct {
+	DryRun  bool
+	Exclude string
+}
+
+// RenameOptions holds options specific to the rename command
+type RenameOptions struct {
+	DryRun  bool
+	Pattern string // One of NamingPatterns(); empty means PatternConsistentNaming
+	Exclude string
 }
 
 // ApplyOptions holds options specific to the apply command
 type ApplyOptions struct {
+	FailFast     bool
+	Resume       bool        // Continue the plan's last execution instead of starting over
+	RetrySkipped bool        // With Resume, also retry steps the last execution skipped
+	Filter       *MoveFilter // Only run the moves of a reorganization plan it selects
+	Concurrency  int         // How many operations may run at once; below 1 means one
+}
+
+// RollbackOptions holds options specific to the rollback command
+type RollbackOptions struct {
 	FailFast bool
 }
 
 // ExecuteReorganize performs the reorganize operation with the given dependencies
-func ExecuteReorganize(opts CommandOptions, reorganizeOpts ReorganizeOptions) (*ReorganizationPlan, error) {
+func ExecuteReorganize(ctx context.Context, opts CommandOptions, reorganizeOpts ReorganizeOptions) (*ReorganizationPlan, error) {
+	exclude, err := NewExcludeFilter(reorganizeOpts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all files recursively from the filesystem
-	allFiles, err := getAllFilesRecursively(opts.FileSystem, "/")
+	allFiles, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", exclude, opts.Progress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all files: %w", err)
 	}
 
 	if opts.Verbose {
-		fmt.Printf("\n🔍 DEBUG: Found %d files:\n", len(allFiles))
+		fmt.Fprintf(opts.messages(), "\n🔍 DEBUG: Found %d files:\n", len(allFiles))
 		for i, file := range allFiles {
 			if i >= 20 {
-				fmt.Printf("   ... and %d more files\n", len(allFiles)-20)
+				fmt.Fprintf(opts.messages(), "   ... and %d more files\n", len(allFiles)-20)
 				break
 			}
-			fmt.Printf("   %s (size: %d bytes, type: %s)\n", file.Path(), file.Size(), file.MimeType())
+			fmt.Fprintf(opts.messages(), "   %s (size: %d bytes, type: %s)\n", file.Path(), file.Size(), file.MimeType())
 		}
-		fmt.Println()
+		fmt.Fprintln(opts.messages())
 	}
 
 	// Generate reorganization plan using the analyzer
 	if opts.Verbose {
-		fmt.Println("🤖 DEBUG: Sending files to AI analyzer...")
-		SetDebugMode(true) // Enable debug mode for AI operations
+		fmt.Fprintln(opts.messages(), "🤖 DEBUG: Sending files to AI analyzer...")
+		SetDebugOutput(opts.messages()) // Enable debug mode for AI operations
 	}
 	
-	plan, err := opts.Analyzer.AnalyzeForReorganization(allFiles)
+	plan, err := opts.Analyzer.AnalyzeForReorganization(ctx, allFiles)
 	
 	if opts.Verbose {
-		SetDebugMode(false) // Disable debug mode after operation
+		SetDebugOutput(nil) // Disable debug mode after operation
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze files: %w", err)
 	}
 
 	if opts.Verbose {
-		fmt.Printf("📋 DEBUG: AI generated plan with %d moves:\n", len(plan.Moves))
+		fmt.Fprintf(opts.messages(), "📋 DEBUG: AI generated plan with %d moves:\n", len(plan.Moves))
 		for i, move := range plan.Moves {
 			if i >= 10 {
-				fmt.Printf("   ... and %d more moves\n", len(plan.Moves)-10)
+				fmt.Fprintf(opts.messages(), "   ... and %d more moves\n", len(plan.Moves)-10)
 				break
 			}
-			fmt.Printf("   MOVE: %s → %s (reason: %s)\n", move.Source, move.Destination, move.Reason)
+			fmt.Fprintf(opts.messages(), "   MOVE: %s → %s (reason: %s)\n", move.Source, move.Destination, move.Reason)
 		}
-		fmt.Println()
+		fmt.Fprintln(opts.messages())
+	}
+
+	// The ID is used as a file name by the store, so it is never taken from the analyzer
+	plan.ID, err = newPlanID(ctx, opts.Store, PlanTypeReorganization)
+	if err != nil {
+		return nil, err
+	}
+
+	// Analyzer output is not trusted: drop or reorder the moves that would
+	// fail, unless the caller wants invalid plans rejected outright
+	repaired, validation := RepairPlan(plan, allFiles)
+	if reorganizeOpts.Strict && validation.HasErrors() {
+		return nil, &PlanValidationError{Validation: validation}
+	}
+	plan = repaired
+	plan.ValidationIssues = validation.Issues
+
+	if opts.Verbose && len(validation.Issues) > 0 {
+		fmt.Fprintf(opts.messages(), "🩺 DEBUG: Repaired %d issues in the generated plan\n", len(validation.Issues))
 	}
 
 	// Save the plan if not dry run
 	if !reorganizeOpts.DryRun {
-		if err := opts.Store.SavePlan(plan); err != nil {
+		if err := opts.Store.SavePlan(ctx, plan); err != nil {
 			return nil, fmt.Errorf("failed to save plan: %w", err)
 		}
 	}
@@ -83,8 +158,8 @@ func ExecuteReorganize(opts CommandOptions, reorganizeOpts ReorganizeOptions) (*
 }
 
 // ExecuteListPlans lists all saved reorganization plans
-func ExecuteListPlans(opts CommandOptions) ([]*PlanSummary, error) {
-	summaries, err := opts.Store.ListPlans()
+func ExecuteListPlans(ctx context.Context, opts CommandOptions) ([]*PlanSummary, error) {
+	summaries, err := opts.Store.ListPlans(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list plans: %w", err)
 	}
@@ -93,8 +168,8 @@ func ExecuteListPlans(opts CommandOptions) ([]*PlanSummary, error) {
 }
 
 // ExecuteShowPlan shows details of a specific plan
-func ExecuteShowPlan(opts CommandOptions, planID string) (*ReorganizationPlan, error) {
-	plan, err := opts.Store.GetPlan(planID)
+func ExecuteShowPlan(ctx context.Context, opts CommandOptions, planID string) (*ReorganizationPlan, error) {
+	plan, err := opts.Store.GetPlan(ctx, planID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get plan: %w", err)
 	}
@@ -102,50 +177,282 @@ func ExecuteShowPlan(opts CommandOptions, planID string) (*ReorganizationPlan, e
 	return plan, nil
 }
 
-// ExecuteApply executes a reorganization plan
-func ExecuteApply(opts CommandOptions, planID string, applyOpts ApplyOptions) (*ExecutionLog, error) {
+// ExecuteSimulatePlan applies a reorganization plan to a virtual copy of the
+// current filesystem, leaving the real one untouched
+func ExecuteSimulatePlan(ctx context.Context, opts CommandOptions, plan *ReorganizationPlan) (*PlanSimulation, error) {
+	allFiles, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", nil, opts.Progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all files: %w", err)
+	}
+
+	simulation, err := SimulatePlan(ctx, plan, allFiles)
+	if err != nil {
+		return nil, err
+	}
+	return simulation, nil
+}
+
+// FindPlanType reports which kind of plan is saved under planID. The error
+// wraps ErrPlanNotFound only when no plan has the ID; a plan that exists but
+// cannot be read is reported as such.
+func FindPlanType(ctx context.Context, store OperationStore, planID string) (PlanType, error) {
+	lookups := []struct {
+		planType PlanType
+		get      func() error
+	}{
+		{PlanTypeReorganization, func() error { _, err := store.GetPlan(ctx, planID); return err }},
+		{PlanTypeCleanup, func() error { _, err := store.GetCleanupPlan(ctx, planID); return err }},
+		{PlanTypeRenaming, func() error { _, err := store.GetRenamingPlan(ctx, planID); return err }},
+		{PlanTypeDeduplication, func() error { _, err := store.GetDeduplicationPlan(ctx, planID); return err }},
+	}
+	for _, lookup := range lookups {
+		err := lookup.get()
+		if err == nil {
+			return lookup.planType, nil
+		}
+		if !errors.Is(err, ErrPlanNotFound) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrPlanNotFound, planID)
+}
+
+// ExecuteShowRenamingPlan shows details of a saved renaming plan
+func ExecuteShowRenamingPlan(ctx context.Context, opts CommandOptions, planID string) (*RenamingPlan, error) {
+	plan, err := opts.Store.GetRenamingPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renaming plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// ExecuteShowDeduplicationPlan shows details of a saved deduplication plan
+func ExecuteShowDeduplicationPlan(ctx context.Context, opts CommandOptions, planID string) (*DeduplicationPlan, error) {
+	plan, err := opts.Store.GetDeduplicationPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deduplication plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// ExecuteShowCleanupPlan shows details of a saved cleanup plan
+func ExecuteShowCleanupPlan(ctx context.Context, opts CommandOptions, planID string) (*CleanupPlan, error) {
+	plan, err := opts.Store.GetCleanupPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleanup plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// ExecuteApply executes a saved plan of any type
+func ExecuteApply(ctx context.Context, opts CommandOptions, planID string, applyOpts ApplyOptions) (*ExecutionLog, error) {
 	if opts.Verbose {
-		fmt.Printf("🔧 DEBUG: Executing plan %s with fail-fast=%v\n", planID, applyOpts.FailFast)
+		fmt.Fprintf(opts.messages(), "🔧 DEBUG: Executing plan %s with fail-fast=%v\n", planID, applyOpts.FailFast)
 	}
 	
 	// Create execution engine
 	engine := NewExecutionEngine(opts.FileSystem, opts.Store)
+	engine.SetProgress(opts.Progress)
 	
-	// Resume any pending operations first
-	if err := engine.ResumePendingOperations(); err != nil {
-		// This is just a warning, don't fail the whole operation
-		if opts.Verbose {
-			fmt.Printf("⚠️  DEBUG: Failed to resume pending operations: %v\n", err)
-		} else {
-			fmt.Printf("Warning: failed to resume pending operations: %v\n", err)
-		}
+	if err := checkNoPendingOperations(ctx, opts.Store); err != nil {
+		return nil, err
 	}
 	
 	if opts.Verbose {
-		fmt.Println("⚡ DEBUG: Starting plan execution...")
+		fmt.Fprintln(opts.messages(), "⚡ DEBUG: Starting plan execution...")
 	}
 	
-	// Execute the plan
-	execLog, err := engine.ExecutePlan(planID, applyOpts.FailFast)
+	if applyOpts.RetrySkipped && !applyOpts.Resume {
+		return nil, fmt.Errorf("retrying skipped operations requires resuming")
+	}
+
+	if err := validatePlanForApply(ctx, opts, engine, planID, applyOpts); err != nil {
+		return nil, err
+	}
+
+	execLog, err := engine.ExecutePlanWithOptions(ctx, planID, ExecuteOptions{
+		FailFast:     applyOpts.FailFast,
+		Resume:       applyOpts.Resume,
+		RetrySkipped: applyOpts.RetrySkipped,
+		Filter:       applyOpts.Filter,
+		Concurrency:  applyOpts.Concurrency,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute plan: %w", err)
 	}
 	
 	if opts.Verbose {
 		totalOps := len(execLog.Completed) + len(execLog.Failed) + len(execLog.Skipped)
-		fmt.Printf("✅ DEBUG: Plan execution completed - %d completed, %d failed, %d skipped (total: %d)\n", 
+		fmt.Fprintf(opts.messages(), "✅ DEBUG: Plan execution completed - %d completed, %d failed, %d skipped (total: %d)\n", 
 			len(execLog.Completed), len(execLog.Failed), len(execLog.Skipped), totalOps)
 	}
 	
 	return execLog, nil
 }
 
+// validatePlanForApply refuses a reorganization plan whose moves no longer
+// validate against the filesystem. Moves the filter leaves out and, when
+// resuming, the moves the last execution already settled are not checked,
+// since they will not run.
+func validatePlanForApply(ctx context.Context, opts CommandOptions, engine *ExecutionEngine, planID string, applyOpts ApplyOptions) error {
+	plan, err := opts.Store.GetPlan(ctx, planID)
+	if err != nil {
+		// Not a reorganization plan; the engine reports missing plans
+		return nil
+	}
+
+	settled := make(map[string]bool)
+	if applyOpts.Resume {
+		if previous, err := engine.GetExecutionStatus(ctx, planID); err == nil {
+			for _, completed := range previous.Completed {
+				settled[completed.MoveID] = true
+			}
+			if !applyOpts.RetrySkipped {
+				for _, skipped := range previous.Skipped {
+					if skipped.Reason != SkipReasonFiltered {
+						settled[skipped.MoveID] = true
+					}
+				}
+			}
+		}
+	}
+
+	remaining := *plan
+	remaining.Moves = make([]Move, 0, len(plan.Moves))
+	for _, move := range plan.Moves {
+		if !settled[move.ID] && applyOpts.Filter.Selects(move) {
+			remaining.Moves = append(remaining.Moves, move)
+		}
+	}
+
+	files, err := statPlanPaths(ctx, opts.FileSystem, &remaining)
+	if err != nil {
+		return fmt.Errorf("failed to check plan paths: %w", err)
+	}
+
+	validation := ValidatePlan(&remaining, files)
+	if validation.HasErrors() {
+		return &PlanValidationError{Validation: validation}
+	}
+	return nil
+}
+
+// statPlanPaths looks up just the paths plan touches, which is all ValidatePlan
+// needs, instead of scanning the whole filesystem. A path inside the
+// destination of an earlier move is also looked up where it is before the plan
+// runs.
+func statPlanPaths(ctx context.Context, fs FileSystem, plan *ReorganizationPlan) ([]FileInfo, error) {
+	paths := make(map[string]bool)
+	for i, move := range plan.Moves {
+		if !plan.runsMove(move) || hasTraversal(move.Source) || hasTraversal(move.Destination) {
+			continue
+		}
+
+		touched := make([]string, 0, 4)
+		if move.Source != "" {
+			touched = append(touched, normalizePlanPath(move.Source))
+		}
+		if move.Destination != "" {
+			for dir := normalizePlanPath(move.Destination); dir != "/"; dir = path.Dir(dir) {
+				touched = append(touched, dir)
+			}
+		}
+
+		for _, p := range touched {
+			paths[p] = true
+			paths[pathBeforeMoves(plan.Moves[:i], p)] = true
+		}
+	}
+
+	files := make([]FileInfo, 0, len(paths))
+	for p := range paths {
+		file, err := statPath(ctx, fs, p)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// pathBeforeMoves returns where p was before moves ran
+func pathBeforeMoves(moves []Move, p string) string {
+	for i := len(moves) - 1; i >= 0; i-- {
+		move := moves[i]
+		if (move.Type != FileMove && move.Type != FolderMove) || move.Approval == ApprovalRejected || move.Source == "" {
+			continue
+		}
+		dest := normalizePlanPath(move.Destination)
+		if isWithin(p, dest) && dest != "/" {
+			p = normalizePlanPath(move.Source) + strings.TrimPrefix(p, dest)
+		}
+	}
+	return p
+}
+
+// ExecuteRollback undoes the completed moves of a previously executed plan
+func ExecuteRollback(ctx context.Context, opts CommandOptions, planID string, rollbackOpts RollbackOptions) (*ExecutionLog, error) {
+	if opts.Verbose {
+		fmt.Fprintf(opts.messages(), "🔧 DEBUG: Rolling back plan %s with fail-fast=%v\n", planID, rollbackOpts.FailFast)
+	}
+
+	engine := NewExecutionEngine(opts.FileSystem, opts.Store)
+	engine.SetProgress(opts.Progress)
+
+	if err := checkNoPendingOperations(ctx, opts.Store); err != nil {
+		return nil, err
+	}
+
+	execLog, err := engine.RollbackPlan(ctx, planID, rollbackOpts.FailFast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back plan: %w", err)
+	}
+
+	if opts.Verbose {
+		fmt.Fprintf(opts.messages(), "✅ DEBUG: Rollback completed - %d completed, %d failed, %d skipped\n",
+			len(execLog.Completed), len(execLog.Failed), len(execLog.Skipped))
+	}
+
+	return execLog, nil
+}
+
+// ExecuteRecover finishes the operations an interrupted execution left in the
+// write-ahead log and records their outcomes
+func ExecuteRecover(ctx context.Context, opts CommandOptions) (*RecoveryReport, error) {
+	engine := NewExecutionEngine(opts.FileSystem, opts.Store)
+
+	report, err := engine.Recover(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover pending operations: %w", err)
+	}
+
+	return report, nil
+}
+
+// checkNoPendingOperations refuses to start an execution while an interrupted
+// one still has operations in the write-ahead log
+func checkNoPendingOperations(ctx context.Context, store OperationStore) error {
+	pending, err := store.GetPendingOperations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check pending operations: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d operations from an interrupted execution are pending; run 'curator recover' first", len(pending))
+	}
+	return nil
+}
+
 // ExecuteStatus checks the status of a plan execution
-func ExecuteStatus(opts CommandOptions, planID string) (*ExecutionLog, error) {
+func ExecuteStatus(ctx context.Context, opts CommandOptions, planID string) (*ExecutionLog, error) {
 	// Create execution engine
 	engine := NewExecutionEngine(opts.FileSystem, opts.Store)
 	
-	execLog, err := engine.GetExecutionStatus(planID)
+	execLog, err := engine.GetExecutionStatus(ctx, planID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get execution status: %w", err)
 	}
@@ -154,8 +461,8 @@ func ExecuteStatus(opts CommandOptions, planID string) (*ExecutionLog, error) {
 }
 
 // ExecuteHistory shows execution history
-func ExecuteHistory(opts CommandOptions) ([]*ExecutionLog, error) {
-	logs, err := opts.Store.GetExecutionHistory()
+func ExecuteHistory(ctx context.Context, opts CommandOptions) ([]*ExecutionLog, error) {
+	logs, err := opts.Store.GetExecutionHistory(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get execution history: %w", err)
 	}
@@ -163,93 +470,301 @@ func ExecuteHistory(opts CommandOptions) ([]*ExecutionLog, error) {
 	return logs, nil
 }
 
-// ExecuteDeduplicate finds duplicate files
-func ExecuteDeduplicate(opts CommandOptions) (*DuplicationReport, error) {
+// ExecuteStoreVerify checks every record in the operation store for corruption
+func ExecuteStoreVerify(opts CommandOptions) (*StoreReport, error) {
+	store, ok := opts.Store.(VerifiableStore)
+	if !ok {
+		return nil, fmt.Errorf("operation store %T does not support verification", opts.Store)
+	}
+
+	report, err := store.Verify()
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify store: %w", err)
+	}
+
+	return report, nil
+}
+
+// ExecuteStoreRepair quarantines corrupt records in the operation store
+func ExecuteStoreRepair(opts CommandOptions) (*StoreReport, error) {
+	store, ok := opts.Store.(VerifiableStore)
+	if !ok {
+		return nil, fmt.Errorf("operation store %T does not support repair", opts.Store)
+	}
+
+	report, err := store.Repair()
+	if err != nil {
+		return nil, fmt.Errorf("failed to repair store: %w", err)
+	}
+
+	return report, nil
+}
+
+// ExecutePlanHistory returns every execution of a plan, newest first
+func ExecutePlanHistory(ctx context.Context, opts CommandOptions, planID string) ([]*ExecutionLog, error) {
+	engine := NewExecutionEngine(opts.FileSystem, opts.Store)
+
+	attempts, err := engine.GetExecutionAttempts(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution history: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// ExecuteDeduplicate finds duplicate files and plans the removal of redundant copies
+func ExecuteDeduplicate(ctx context.Context, opts CommandOptions, dedupOpts DeduplicateOptions) (*DeduplicationPlan, error) {
+	if _, _, err := validateDeduplicateOptions(dedupOpts); err != nil {
+		return nil, err
+	}
+
+	// Quarantined copies are never treated as candidates again
+	quarantineDir := dedupOpts.QuarantineDir
+	if quarantineDir == "" {
+		quarantineDir = DefaultQuarantineDir
+	}
+	exclude, err := NewExcludeFilter(dedupOpts.Exclude + "," + quarantineDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all files recursively
-	allFiles, err := getAllFilesRecursively(opts.FileSystem, "/")
+	allFiles, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", exclude, opts.Progress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all files: %w", err)
 	}
 	
 	if opts.Verbose {
-		fmt.Printf("\n🔍 DEBUG: Analyzing %d files for duplicates\n", len(allFiles))
-		SetDebugMode(true)
+		fmt.Fprintf(opts.messages(), "\n🔍 DEBUG: Analyzing %d files for duplicates\n", len(allFiles))
+		SetDebugOutput(opts.messages())
 	}
 	
-	// Analyze for duplicates
-	report, err := opts.Analyzer.AnalyzeForDuplicates(allFiles)
+	// Analyze for duplicates; hashing the candidates is what takes time
+	report, err := opts.Analyzer.AnalyzeForDuplicates(ctx, trackHashing(allFiles, opts.Progress))
 	
 	if opts.Verbose {
-		SetDebugMode(false)
+		SetDebugOutput(nil)
 		if err == nil {
-			fmt.Printf("📋 DEBUG: Found %d duplicate groups\n\n", len(report.Duplicates))
+			fmt.Fprintf(opts.messages(), "📋 DEBUG: Found %d duplicate groups\n\n", len(report.Duplicates))
 		}
 	}
 	
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze duplicates: %w", err)
 	}
+
+	// Optionally rule out hash collisions by comparing contents
+	if dedupOpts.VerifyBytes {
+		report, err = ConfirmDuplicates(ctx, opts.FileSystem, report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify duplicates: %w", err)
+		}
+	}
+
+	plan, err := buildDeduplicationPlan(report, allFiles, dedupOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the plan if not dry run so it can be applied later
+	if !dedupOpts.DryRun {
+		if err := opts.Store.SaveDeduplicationPlan(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to save deduplication plan: %w", err)
+		}
+	}
 	
-	return report, nil
+	return plan, nil
 }
 
 // ExecuteCleanup identifies junk files for cleanup
-func ExecuteCleanup(opts CommandOptions) (*CleanupPlan, error) {
+func ExecuteCleanup(ctx context.Context, opts CommandOptions, cleanupOpts CleanupOptions) (*CleanupPlan, error) {
+	exclude, err := NewExcludeFilter(cleanupOpts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all files recursively
-	allFiles, err := getAllFilesRecursively(opts.FileSystem, "/")
+	allFiles, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", exclude, opts.Progress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all files: %w", err)
 	}
 	
 	// Analyze for cleanup
-	plan, err := opts.Analyzer.AnalyzeForCleanup(allFiles)
+	plan, err := opts.Analyzer.AnalyzeForCleanup(ctx, allFiles)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze cleanup: %w", err)
 	}
+
+	recordDeletionFingerprints(plan, allFiles)
+
+	// The ID is used as a file name by the store, so it is never taken from the analyzer
+	plan.ID, err = newPlanID(ctx, opts.Store, PlanTypeCleanup)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the plan if not dry run so it can be applied later
+	if !cleanupOpts.DryRun {
+		if err := opts.Store.SaveCleanupPlan(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to save cleanup plan: %w", err)
+		}
+	}
 	
 	return plan, nil
 }
 
+// recordDeletionFingerprints fills in the size and hash of each deletion from the
+// scanned files, so apply can detect files that changed after analysis. Deletions
+// for paths that were not scanned, or that are folders, are dropped from the plan.
+func recordDeletionFingerprints(plan *CleanupPlan, files []FileInfo) {
+	byPath := make(map[string]FileInfo, len(files))
+	for _, file := range files {
+		byPath[file.Path()] = file
+	}
+
+	deletions := make([]Deletion, 0, len(plan.Deletions))
+	var spaceFreed int64
+	for _, deletion := range plan.Deletions {
+		file, ok := byPath[deletion.Path]
+		if !ok || file.IsDir() {
+			continue
+		}
+
+		deletion.Size = file.Size()
+		deletion.Hash = file.Hash()
+		deletions = append(deletions, deletion)
+		spaceFreed += deletion.Size
+	}
+
+	plan.Deletions = deletions
+	plan.Summary.FilesDeleted = len(deletions)
+	plan.Summary.SpaceFreed = spaceFreed
+}
+
 // ExecuteRename standardizes file naming conventions
-func ExecuteRename(opts CommandOptions) (*RenamingPlan, error) {
+func ExecuteRename(ctx context.Context, opts CommandOptions, renameOpts RenameOptions) (*RenamingPlan, error) {
+	exclude, err := NewExcludeFilter(renameOpts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all files recursively
-	allFiles, err := getAllFilesRecursively(opts.FileSystem, "/")
+	allFiles, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", exclude, opts.Progress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all files: %w", err)
 	}
 	
-	// Analyze for renaming
-	plan, err := opts.Analyzer.AnalyzeForRenaming(allFiles)
-	if err != nil {
-		return nil, fmt.Errorf("failed to analyze renaming: %w", err)
+	pattern := renameOpts.Pattern
+	if pattern == "" {
+		pattern = PatternConsistentNaming
+	}
+
+	// The consistent-naming pattern asks the analyzer; the others are applied locally
+	var plan *RenamingPlan
+	separator := "_"
+	if pattern == PatternConsistentNaming {
+		plan, err = opts.Analyzer.AnalyzeForRenaming(ctx, allFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze renaming: %w", err)
+		}
+	} else {
+		plan, err = buildLocalRenamingPlan(allFiles, pattern)
+		if err != nil {
+			return nil, err
+		}
+		separator = namingStrategies[pattern].separator
+	}
+
+	if err := finalizeRenamingPlan(ctx, opts.Store, plan, allFiles, separator); err != nil {
+		return nil, err
+	}
+
+	// Save the plan if not dry run so it can be applied later
+	if !renameOpts.DryRun {
+		if err := opts.Store.SaveRenamingPlan(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to save renaming plan: %w", err)
+		}
 	}
 	
 	return plan, nil
 }
 
-// Helper function to get all files recursively (moved from main.go)
-func getAllFilesRecursively(fs FileSystem, root string) ([]FileInfo, error) {
+// Helper function to get all files recursively (moved from main.go).
+// Excluded paths are pruned during traversal, so nothing below them is listed.
+// Every listed folder is reported to progress.
+func getAllFilesRecursively(ctx context.Context, fs FileSystem, root string, exclude *ExcludeFilter, progress ProgressReporter) ([]FileInfo, error) {
 	var allFiles []FileInfo
+	start := time.Now()
+	event := ProgressEvent{Phase: PhaseScan}
+	
+	// Filesystems that can list the whole tree at once skip the walk
+	if lister, ok := fs.(TreeLister); ok {
+		files, listed, err := lister.ListTree(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if listed {
+			excluded := make(map[string]bool) // Excluded folders, whose contents are left out too
+			for _, file := range files {
+				if excluded[path.Dir(file.Path())] || exclude.Matches(file.Path()) {
+					if file.IsDir() {
+						excluded[file.Path()] = true
+					}
+					continue
+				}
+				allFiles = append(allFiles, file)
+				if file.IsDir() {
+					event.FoldersScanned++
+				} else {
+					event.FilesScanned++
+				}
+			}
+			event.FoldersScanned++ // The root
+			event.Elapsed = time.Since(start)
+			event.Done = true
+			reportProgress(progress, event)
+			return allFiles, nil
+		}
+	}
 	
 	var traverse func(string) error
 	traverse = func(path string) error {
-		files, err := fs.List(path)
+		// Stop a long scan as soon as it is cancelled
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("scan stopped: %w", err)
+		}
+		files, err := fs.List(ctx, path)
 		if err != nil {
 			return err
 		}
+		event.FoldersScanned++
 		
 		for _, file := range files {
+			if exclude.Matches(file.Path()) {
+				continue
+			}
 			allFiles = append(allFiles, file)
+			if !file.IsDir() {
+				event.FilesScanned++
+			}
 			if file.IsDir() {
 				if err := traverse(file.Path()); err != nil {
 					return err
 				}
 			}
 		}
+		event.Elapsed = time.Since(start)
+		reportProgress(progress, event)
 		return nil
 	}
 	
-	return allFiles, traverse(root)
+	if err := traverse(root); err != nil {
+		return allFiles, err
+	}
+	event.Elapsed = time.Since(start)
+	event.Done = true
+	reportProgress(progress, event)
+	return allFiles, nil
 }
 
 // Configuration represents all the configuration needed for commands
@@ -257,6 +772,7 @@ type Configuration struct {
 	AI         AIConfig
 	FileSystem FileSystemConfig
 	StoreDir   string
+	StoreType  string // "file" (default) or "sqlite"
 }
 
 // CreateCommandOptions creates CommandOptions from Configuration
@@ -289,7 +805,15 @@ func CreateCommandOptions(config Configuration) (CommandOptions, error) {
 	}
 	
 	// Create store
-	store, err := NewFileOperationStore(config.StoreDir)
+	var store OperationStore
+	switch config.StoreType {
+	case "", StoreTypeFile:
+		store, err = NewFileOperationStore(config.StoreDir)
+	case StoreTypeSQLite:
+		store, err = NewSQLiteOperationStore(filepath.Join(config.StoreDir, SQLiteDatabaseName))
+	default:
+		return CommandOptions{}, fmt.Errorf("unknown store type: %s (valid options: file, sqlite)", config.StoreType)
+	}
 	if err != nil {
 		return CommandOptions{}, fmt.Errorf("failed to create operation store: %w", err)
 	}
@@ -346,6 +870,7 @@ func LoadConfigurationFromEnvironment() Configuration {
 		AI:         config.AI,
 		FileSystem: config.FileSystem,
 		StoreDir:   GetDefaultStoreDir(),
+		StoreType:  GetDefaultStoreType(),
 	}
 }
 
@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -37,7 +38,7 @@ func TestCommands_FullWorkflow(t *testing.T) {
 			Exclude: "",
 		}
 		
-		plan, err := ExecuteReorganize(opts, reorganizeOpts)
+		plan, err := ExecuteReorganize(context.Background(), opts, reorganizeOpts)
 		if err != nil {
 			t.Fatalf("ExecuteReorganize failed: %v", err)
 		}
@@ -58,7 +59,7 @@ func TestCommands_FullWorkflow(t *testing.T) {
 		
 		// Test list-plans command
 		t.Run("ExecuteListPlans", func(t *testing.T) {
-			summaries, err := ExecuteListPlans(opts)
+			summaries, err := ExecuteListPlans(context.Background(), opts)
 			if err != nil {
 				t.Fatalf("ExecuteListPlans failed: %v", err)
 			}
@@ -74,7 +75,7 @@ func TestCommands_FullWorkflow(t *testing.T) {
 		
 		// Test show-plan command
 		t.Run("ExecuteShowPlan", func(t *testing.T) {
-			retrievedPlan, err := ExecuteShowPlan(opts, planID)
+			retrievedPlan, err := ExecuteShowPlan(context.Background(), opts, planID)
 			if err != nil {
 				t.Fatalf("ExecuteShowPlan failed: %v", err)
 			}
@@ -94,7 +95,7 @@ func TestCommands_FullWorkflow(t *testing.T) {
 				FailFast: false,
 			}
 			
-			execLog, err := ExecuteApply(opts, planID, applyOpts)
+			execLog, err := ExecuteApply(context.Background(), opts, planID, applyOpts)
 			if err != nil {
 				t.Fatalf("ExecuteApply failed: %v", err)
 			}
@@ -117,7 +118,7 @@ func TestCommands_FullWorkflow(t *testing.T) {
 		
 		// Test status command
 		t.Run("ExecuteStatus", func(t *testing.T) {
-			statusLog, err := ExecuteStatus(opts, planID)
+			statusLog, err := ExecuteStatus(context.Background(), opts, planID)
 			if err != nil {
 				t.Fatalf("ExecuteStatus failed: %v", err)
 			}
@@ -129,7 +130,7 @@ func TestCommands_FullWorkflow(t *testing.T) {
 		
 		// Test history command
 		t.Run("ExecuteHistory", func(t *testing.T) {
-			logs, err := ExecuteHistory(opts)
+			logs, err := ExecuteHistory(context.Background(), opts)
 			if err != nil {
 				t.Fatalf("ExecuteHistory failed: %v", err)
 			}
@@ -176,7 +177,7 @@ func TestCommands_ErrorHandling(t *testing.T) {
 	}
 	
 	t.Run("show plan with invalid ID", func(t *testing.T) {
-		_, err := ExecuteShowPlan(opts, "nonexistent-plan")
+		_, err := ExecuteShowPlan(context.Background(), opts, "nonexistent-plan")
 		if err == nil {
 			t.Error("Expected error for nonexistent plan")
 		}
@@ -188,7 +189,7 @@ func TestCommands_ErrorHandling(t *testing.T) {
 	
 	t.Run("apply plan with invalid ID", func(t *testing.T) {
 		applyOpts := ApplyOptions{FailFast: false}
-		_, err := ExecuteApply(opts, "nonexistent-plan", applyOpts)
+		_, err := ExecuteApply(context.Background(), opts, "nonexistent-plan", applyOpts)
 		if err == nil {
 			t.Error("Expected error for nonexistent plan")
 		}
@@ -199,7 +200,7 @@ func TestCommands_ErrorHandling(t *testing.T) {
 	})
 	
 	t.Run("status for nonexistent plan", func(t *testing.T) {
-		_, err := ExecuteStatus(opts, "nonexistent-plan")
+		_, err := ExecuteStatus(context.Background(), opts, "nonexistent-plan")
 		if err == nil {
 			t.Error("Expected error for nonexistent plan")
 		}
@@ -233,7 +234,7 @@ func TestCommands_OtherOperations(t *testing.T) {
 	}
 	
 	t.Run("ExecuteDeduplicate", func(t *testing.T) {
-		report, err := ExecuteDeduplicate(opts)
+		report, err := ExecuteDeduplicate(context.Background(), opts, DeduplicateOptions{})
 		if err != nil {
 			t.Fatalf("ExecuteDeduplicate failed: %v", err)
 		}
@@ -249,7 +250,7 @@ func TestCommands_OtherOperations(t *testing.T) {
 	})
 	
 	t.Run("ExecuteCleanup", func(t *testing.T) {
-		plan, err := ExecuteCleanup(opts)
+		plan, err := ExecuteCleanup(context.Background(), opts, CleanupOptions{})
 		if err != nil {
 			t.Fatalf("ExecuteCleanup failed: %v", err)
 		}
@@ -265,7 +266,7 @@ func TestCommands_OtherOperations(t *testing.T) {
 	})
 	
 	t.Run("ExecuteRename", func(t *testing.T) {
-		plan, err := ExecuteRename(opts)
+		plan, err := ExecuteRename(context.Background(), opts, RenameOptions{})
 		if err != nil {
 			t.Fatalf("ExecuteRename failed: %v", err)
 		}
@@ -376,7 +377,7 @@ func TestCommands_DryRun(t *testing.T) {
 		Exclude: "",
 	}
 	
-	plan, err := ExecuteReorganize(opts, reorganizeOpts)
+	plan, err := ExecuteReorganize(context.Background(), opts, reorganizeOpts)
 	if err != nil {
 		t.Fatalf("ExecuteReorganize with dry-run failed: %v", err)
 	}
@@ -386,7 +387,7 @@ func TestCommands_DryRun(t *testing.T) {
 	}
 	
 	// Plan should not be saved in dry-run mode
-	summaries, err := ExecuteListPlans(opts)
+	summaries, err := ExecuteListPlans(context.Background(), opts)
 	if err != nil {
 		t.Fatalf("ExecuteListPlans failed: %v", err)
 	}
@@ -452,7 +453,7 @@ func TestCommands_PersistentStore(t *testing.T) {
 	
 	// Create and save a plan
 	reorganizeOpts := ReorganizeOptions{DryRun: false}
-	plan, err := ExecuteReorganize(opts1, reorganizeOpts)
+	plan, err := ExecuteReorganize(context.Background(), opts1, reorganizeOpts)
 	if err != nil {
 		t.Fatalf("ExecuteReorganize failed: %v", err)
 	}
@@ -471,7 +472,7 @@ func TestCommands_PersistentStore(t *testing.T) {
 	}()
 	
 	// Plan should persist across different command option instances
-	retrievedPlan, err := ExecuteShowPlan(opts2, planID)
+	retrievedPlan, err := ExecuteShowPlan(context.Background(), opts2, planID)
 	if err != nil {
 		t.Fatalf("ExecuteShowPlan failed with second options: %v", err)
 	}
@@ -494,4 +495,221 @@ func TestCommands_PersistentStore(t *testing.T) {
 			// the store was created successfully, which means the directories exist
 		}
 	}
-}
\ No newline at end of file
+}
+func TestCommands_CleanupApply(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/notes.txt", []byte("notes"), "text/plain")
+	fs.AddFile("/scratch.tmp", []byte("scratch"), "text/plain")
+	fs.AddFile("/Logs/debug.log", []byte("debug"), "text/plain")
+
+	opts := CommandOptions{
+		FileSystem: fs,
+		Store:      NewMemoryOperationStore(),
+		Analyzer:   NewMockAIAnalyzer(),
+		Reporter:   NewReporter(),
+	}
+
+	// A dry run must not save anything
+	if _, err := ExecuteCleanup(context.Background(), opts, CleanupOptions{DryRun: true}); err != nil {
+		t.Fatalf("ExecuteCleanup dry run failed: %v", err)
+	}
+	if summaries, _ := ExecuteListPlans(context.Background(), opts); len(summaries) != 0 {
+		t.Fatalf("Expected no saved plans after dry run, got %d", len(summaries))
+	}
+
+	plan, err := ExecuteCleanup(context.Background(), opts, CleanupOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteCleanup failed: %v", err)
+	}
+	if len(plan.Deletions) != 2 {
+		t.Fatalf("Expected 2 deletions, got %d", len(plan.Deletions))
+	}
+	for _, deletion := range plan.Deletions {
+		if deletion.Hash == "" {
+			t.Errorf("Expected deletion %s to record the file hash", deletion.Path)
+		}
+	}
+
+	saved, err := ExecuteShowCleanupPlan(context.Background(), opts, plan.ID)
+	if err != nil {
+		t.Fatalf("ExecuteShowCleanupPlan failed: %v", err)
+	}
+	if len(saved.Deletions) != len(plan.Deletions) {
+		t.Errorf("Expected saved plan to have %d deletions, got %d", len(plan.Deletions), len(saved.Deletions))
+	}
+
+	execLog, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteApply failed: %v", err)
+	}
+	if execLog.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, execLog.Status)
+	}
+
+	for _, path := range []string{"/scratch.tmp", "/Logs/debug.log"} {
+		if exists, _ := fs.Exists(context.Background(), path); exists {
+			t.Errorf("Expected %s to be deleted", path)
+		}
+	}
+	if exists, _ := fs.Exists(context.Background(), "/notes.txt"); !exists {
+		t.Error("Expected /notes.txt to be kept")
+	}
+}
+
+func TestCommands_RenameApply(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/Photos/Summer Trip.jpg", []byte("a"), "image/jpeg")
+	fs.AddFile("/Photos/summer-trip.jpg", []byte("b"), "image/jpeg")
+
+	opts := CommandOptions{
+		FileSystem: fs,
+		Store:      NewMemoryOperationStore(),
+		Analyzer:   NewMockAIAnalyzer(),
+		Reporter:   NewReporter(),
+	}
+
+	if _, err := ExecuteRename(context.Background(), opts, RenameOptions{Pattern: "Title Case"}); err == nil {
+		t.Error("Expected error for unknown pattern")
+	}
+
+	plan, err := ExecuteRename(context.Background(), opts, RenameOptions{Pattern: PatternKebabCase})
+	if err != nil {
+		t.Fatalf("ExecuteRename failed: %v", err)
+	}
+	if plan.Summary.Pattern != PatternKebabCase {
+		t.Errorf("Expected pattern %s, got %s", PatternKebabCase, plan.Summary.Pattern)
+	}
+	if len(plan.Renames) != 1 || plan.Renames[0].NewPath != "/Photos/summer-trip-2.jpg" {
+		t.Fatalf("Expected a suffixed rename to avoid the existing file, got %+v", plan.Renames)
+	}
+
+	if planType, err := FindPlanType(context.Background(), opts.Store, plan.ID); err != nil || planType != PlanTypeRenaming {
+		t.Fatalf("Expected saved renaming plan, got %q (%v)", planType, err)
+	}
+
+	execLog, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteApply failed: %v", err)
+	}
+	if execLog.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, execLog.Status)
+	}
+
+	for _, path := range []string{"/Photos/summer-trip.jpg", "/Photos/summer-trip-2.jpg"} {
+		if exists, _ := fs.Exists(context.Background(), path); !exists {
+			t.Errorf("Expected %s to exist", path)
+		}
+	}
+}
+
+func TestCommands_DeduplicateApply(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/a/report.pdf", []byte("same"), "application/pdf")
+	fs.AddFile("/b/c/report.pdf", []byte("same"), "application/pdf")
+
+	opts := CommandOptions{
+		FileSystem: fs,
+		Store:      NewMemoryOperationStore(),
+		Analyzer:   NewMockAIAnalyzer(),
+		Reporter:   NewReporter(),
+	}
+
+	plan, err := ExecuteDeduplicate(context.Background(), opts, DeduplicateOptions{Keep: KeepShortestPath})
+	if err != nil {
+		t.Fatalf("ExecuteDeduplicate failed: %v", err)
+	}
+	if len(plan.Removals) != 1 || plan.Removals[0].Path != "/b/c/report.pdf" {
+		t.Fatalf("Expected the deeper copy to be removed, got %+v", plan.Removals)
+	}
+
+	if _, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{}); err != nil {
+		t.Fatalf("ExecuteApply failed: %v", err)
+	}
+	if exists, _ := fs.Exists(context.Background(), plan.Removals[0].Destination); !exists {
+		t.Fatalf("Expected copy to be quarantined at %s", plan.Removals[0].Destination)
+	}
+
+	// Quarantined copies are not reported again
+	plan, err = ExecuteDeduplicate(context.Background(), opts, DeduplicateOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ExecuteDeduplicate failed: %v", err)
+	}
+	if len(plan.Removals) != 0 {
+		t.Errorf("Expected no duplicates after quarantine, got %+v", plan.Removals)
+	}
+}
+
+// fixedIDAnalyzer returns the mock analyzer's plans under the same ID every time
+type fixedIDAnalyzer struct {
+	*MockAIAnalyzer
+	id string
+}
+
+func (a *fixedIDAnalyzer) AnalyzeForCleanup(ctx context.Context, files []FileInfo) (*CleanupPlan, error) {
+	plan, err := a.MockAIAnalyzer.AnalyzeForCleanup(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	plan.ID = a.id
+	return plan, nil
+}
+
+func (a *fixedIDAnalyzer) AnalyzeForRenaming(ctx context.Context, files []FileInfo) (*RenamingPlan, error) {
+	plan, err := a.MockAIAnalyzer.AnalyzeForRenaming(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	plan.ID = a.id
+	return plan, nil
+}
+
+func TestCommands_AssignPlanIDs(t *testing.T) {
+	ctx := context.Background()
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/scratch.tmp", []byte("scratch"), "text/plain")
+	fs.AddFile("/My Notes.txt", []byte("notes"), "text/plain")
+
+	store, err := NewFileOperationStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	opts := CommandOptions{
+		FileSystem: fs,
+		Store:      store,
+		Analyzer:   &fixedIDAnalyzer{MockAIAnalyzer: NewMockAIAnalyzer(), id: "../escape"},
+		Reporter:   NewReporter(),
+	}
+
+	// The analyzer's ID is ignored, and a second plan never replaces the first
+	first, err := ExecuteCleanup(ctx, opts, CleanupOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteCleanup failed: %v", err)
+	}
+	second, err := ExecuteCleanup(ctx, opts, CleanupOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteCleanup failed: %v", err)
+	}
+	if first.ID == second.ID || !strings.HasPrefix(first.ID, "cleanup-") {
+		t.Errorf("Expected two distinct cleanup IDs, got %q and %q", first.ID, second.ID)
+	}
+
+	firstRename, err := ExecuteRename(ctx, opts, RenameOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteRename failed: %v", err)
+	}
+	secondRename, err := ExecuteRename(ctx, opts, RenameOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteRename failed: %v", err)
+	}
+	if firstRename.ID == secondRename.ID || !strings.HasPrefix(firstRename.ID, "rename-") {
+		t.Errorf("Expected two distinct renaming IDs, got %q and %q", firstRename.ID, secondRename.ID)
+	}
+
+	// The stores refuse IDs that are not safe file names
+	stores := map[string]OperationStore{"file": store, "memory": NewMemoryOperationStore(), "sqlite": newTestSQLiteStore(t)}
+	for name, store := range stores {
+		if err := store.SaveCleanupPlan(ctx, &CleanupPlan{ID: "../escape"}); err == nil {
+			t.Errorf("Expected the %s store to reject an unsafe plan ID", name)
+		}
+	}
+}
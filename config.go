@@ -112,6 +112,71 @@ func loadGoogleDriveConfig() *GoogleDriveConfig {
 		config.ApplicationName = appName
 	}
 	
+	// Load listing page size and bulk traversal from environment
+	if pageSizeStr := os.Getenv("GOOGLE_DRIVE_PAGE_SIZE"); pageSizeStr != "" {
+		pageSize, err := strconv.ParseInt(pageSizeStr, 10, 64)
+		if err == nil && (pageSize < 1 || pageSize > DefaultDrivePageSize) {
+			err = fmt.Errorf("must be between 1 and %d", DefaultDrivePageSize)
+		}
+		if err == nil {
+			config.PageSize = pageSize
+		} else {
+			log.Printf("Warning: invalid GOOGLE_DRIVE_PAGE_SIZE value '%s', using default: %v", pageSizeStr, err)
+		}
+	}
+	if bulk := os.Getenv("GOOGLE_DRIVE_BULK_TRAVERSAL"); bulk != "" {
+		if enabled, err := strconv.ParseBool(bulk); err == nil {
+			config.BulkTraversal = enabled
+		} else {
+			log.Printf("Warning: invalid GOOGLE_DRIVE_BULK_TRAVERSAL value '%s', using default: %v", bulk, err)
+		}
+	}
+	
+	// Load path to ID cache file from environment (optional)
+	if cacheFile := os.Getenv("GOOGLE_DRIVE_ID_CACHE_FILE"); cacheFile != "" {
+		config.IDCacheFile = cacheFile
+	}
+	
+	// Load shared drive ID or name from environment (optional)
+	if sharedDrive := os.Getenv("GOOGLE_DRIVE_SHARED_DRIVE"); sharedDrive != "" {
+		config.SharedDrive = sharedDrive
+	}
+	
+	// Load request rate limit and retries from environment
+	if rateLimitStr := os.Getenv("GOOGLE_DRIVE_RATE_LIMIT"); rateLimitStr != "" {
+		rateLimit, err := strconv.ParseFloat(rateLimitStr, 64)
+		if err == nil && rateLimit < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		if err == nil {
+			config.RateLimit = rateLimit
+		} else {
+			log.Printf("Warning: invalid GOOGLE_DRIVE_RATE_LIMIT value '%s', using default: %v", rateLimitStr, err)
+		}
+	}
+	if maxRetriesStr := os.Getenv("GOOGLE_DRIVE_MAX_RETRIES"); maxRetriesStr != "" {
+		maxRetries, err := strconv.Atoi(maxRetriesStr)
+		if err == nil && maxRetries < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		if err == nil {
+			config.MaxRetries = maxRetries
+		} else {
+			log.Printf("Warning: invalid GOOGLE_DRIVE_MAX_RETRIES value '%s', using default: %v", maxRetriesStr, err)
+		}
+	}
+	if retryDelayStr := os.Getenv("GOOGLE_DRIVE_RETRY_DELAY"); retryDelayStr != "" {
+		retryDelay, err := time.ParseDuration(retryDelayStr)
+		if err == nil && retryDelay < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		if err == nil {
+			config.RetryDelay = retryDelay
+		} else {
+			log.Printf("Warning: invalid GOOGLE_DRIVE_RETRY_DELAY value '%s', using default: %v", retryDelayStr, err)
+		}
+	}
+	
 	return config
 }
 
@@ -203,6 +268,17 @@ func GetDefaultStoreDir() string {
 	return filepath.Join(homeDir, ".curator")
 }
 
+// Operation store backends
+const (
+	StoreTypeFile   = "file"
+	StoreTypeSQLite = "sqlite"
+)
+
+// GetDefaultStoreType returns the operation store backend to use
+func GetDefaultStoreType() string {
+	return getEnvOrDefault("CURATOR_STORE_TYPE", StoreTypeFile)
+}
+
 // getEnvOrDefault returns environment variable value or default if not set
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
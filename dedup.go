@@ -0,0 +1,164 @@
+package curator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultQuarantineDir is where quarantined duplicates are moved unless configured otherwise
+const DefaultQuarantineDir = "/.curator-quarantine"
+
+// buildDeduplicationPlan picks a keeper for every duplicate group in the report
+// and turns the other copies into removals. Paths in the report that were not
+// scanned, or that are folders, are ignored; groups with fewer than two
+// remaining copies are left alone.
+func buildDeduplicationPlan(report *DuplicationReport, files []FileInfo, dedupOpts DeduplicateOptions) (*DeduplicationPlan, error) {
+	policy, action, err := validateDeduplicateOptions(dedupOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantineDir := dedupOpts.QuarantineDir
+	if quarantineDir == "" {
+		quarantineDir = DefaultQuarantineDir
+	}
+
+	byPath := make(map[string]FileInfo, len(files))
+	for _, file := range files {
+		byPath[file.Path()] = file
+	}
+
+	plan := &DeduplicationPlan{
+		ID:        "dedup-" + strings.TrimPrefix(report.ID, "dup-"),
+		Timestamp: report.Timestamp,
+		Policy:    policy,
+		Action:    action,
+		Groups:    make([]DuplicateGroup, 0, len(report.Duplicates)),
+		Removals:  make([]DuplicateRemoval, 0),
+	}
+	if action == DuplicateQuarantine {
+		plan.QuarantineDir = quarantineDir
+	}
+
+	for _, group := range report.Duplicates {
+		var copies []FileInfo
+		for _, filePath := range group.Files {
+			if file, ok := byPath[filePath]; ok && !file.IsDir() {
+				copies = append(copies, file)
+			}
+		}
+		if len(copies) < 2 {
+			continue
+		}
+
+		keeper := chooseKeeper(copies, policy, dedupOpts.PreferredRoots)
+
+		// The keeper is listed first so reports show which copy survives
+		groupFiles := []string{keeper.Path()}
+		for _, file := range copies {
+			if file == keeper {
+				continue
+			}
+			groupFiles = append(groupFiles, file.Path())
+
+			removal := DuplicateRemoval{
+				ID:         fmt.Sprintf("dedup-%d", len(plan.Removals)+1),
+				Path:       file.Path(),
+				KeeperPath: keeper.Path(),
+				Hash:       file.Hash(),
+				Size:       file.Size(),
+			}
+			if action == DuplicateQuarantine {
+				removal.Destination = path.Join(quarantineDir, plan.ID, file.Path())
+			}
+
+			plan.Removals = append(plan.Removals, removal)
+			plan.Summary.SpaceSaved += file.Size()
+		}
+
+		plan.Groups = append(plan.Groups, DuplicateGroup{
+			Hash:  group.Hash,
+			Files: groupFiles,
+			Size:  keeper.Size(),
+		})
+	}
+
+	plan.Summary.TotalDuplicates = len(plan.Removals)
+	return plan, nil
+}
+
+// validateDeduplicateOptions applies defaults and rejects unknown policies or actions
+func validateDeduplicateOptions(dedupOpts DeduplicateOptions) (KeeperPolicy, DuplicateAction, error) {
+	policy := dedupOpts.Keep
+	if policy == "" {
+		policy = KeepOldest
+	}
+
+	switch policy {
+	case KeepOldest, KeepNewest, KeepShortestPath:
+	case KeepPreferred:
+		if len(dedupOpts.PreferredRoots) == 0 {
+			return "", "", fmt.Errorf("keeper policy %s requires at least one preferred root", KeepPreferred)
+		}
+	default:
+		return "", "", fmt.Errorf("unknown keeper policy: %s (expected oldest, newest, shortest-path or preferred)", policy)
+	}
+
+	action := dedupOpts.Action
+	if action == "" {
+		action = DuplicateQuarantine
+	}
+	if action != DuplicateDelete && action != DuplicateQuarantine {
+		return "", "", fmt.Errorf("unknown duplicate action: %s (expected delete or quarantine)", action)
+	}
+
+	return policy, action, nil
+}
+
+// chooseKeeper returns the copy to keep. Ties are broken by the shortest path
+// and then alphabetically, so the choice never depends on listing order.
+func chooseKeeper(copies []FileInfo, policy KeeperPolicy, preferredRoots []string) FileInfo {
+	sorted := make([]FileInfo, len(copies))
+	copy(sorted, copies)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+
+		switch policy {
+		case KeepOldest:
+			if !a.ModTime().Equal(b.ModTime()) {
+				return a.ModTime().Before(b.ModTime())
+			}
+		case KeepNewest:
+			if !a.ModTime().Equal(b.ModTime()) {
+				return a.ModTime().After(b.ModTime())
+			}
+		case KeepPreferred:
+			rankA, rankB := preferredRank(a.Path(), preferredRoots), preferredRank(b.Path(), preferredRoots)
+			if rankA != rankB {
+				return rankA < rankB
+			}
+		}
+
+		if len(a.Path()) != len(b.Path()) {
+			return len(a.Path()) < len(b.Path())
+		}
+		return a.Path() < b.Path()
+	})
+
+	return sorted[0]
+}
+
+// preferredRank returns the index of the first root containing filePath, or
+// len(roots) if none does
+func preferredRank(filePath string, roots []string) int {
+	for i, root := range roots {
+		root = path.Clean("/" + strings.TrimSpace(root))
+		if root == "/" || filePath == root || strings.HasPrefix(filePath, root+"/") {
+			return i
+		}
+	}
+	return len(roots)
+}
@@ -0,0 +1,116 @@
+package curator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newDuplicateFixture creates three identical copies with distinct modification times
+func newDuplicateFixture(t *testing.T) (*MemoryFileSystem, []FileInfo, *DuplicationReport) {
+	t.Helper()
+
+	fs := NewMemoryFileSystem()
+	content := []byte("holiday photo")
+	fs.AddFile("/Photos/Originals/beach.jpg", content, "image/jpeg")
+	fs.AddFile("/Downloads/beach.jpg", content, "image/jpeg")
+	fs.AddFile("/Desktop/Old Stuff/beach copy.jpg", content, "image/jpeg")
+	fs.AddFile("/notes.txt", []byte("unique"), "text/plain")
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fs.files["/Desktop/Old Stuff/beach copy.jpg"].modTime = base
+	fs.files["/Photos/Originals/beach.jpg"].modTime = base.Add(time.Hour)
+	fs.files["/Downloads/beach.jpg"].modTime = base.Add(2 * time.Hour)
+
+	files, err := getAllFilesRecursively(context.Background(), fs, "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+
+	report, err := NewMockAIAnalyzer().AnalyzeForDuplicates(context.Background(), files)
+	if err != nil {
+		t.Fatalf("Failed to analyze duplicates: %v", err)
+	}
+
+	return fs, files, report
+}
+
+func TestBuildDeduplicationPlan_KeeperPolicies(t *testing.T) {
+	_, files, report := newDuplicateFixture(t)
+
+	tests := []struct {
+		opts   DeduplicateOptions
+		keeper string
+	}{
+		{DeduplicateOptions{}, "/Desktop/Old Stuff/beach copy.jpg"},
+		{DeduplicateOptions{Keep: KeepOldest}, "/Desktop/Old Stuff/beach copy.jpg"},
+		{DeduplicateOptions{Keep: KeepNewest}, "/Downloads/beach.jpg"},
+		{DeduplicateOptions{Keep: KeepShortestPath}, "/Downloads/beach.jpg"},
+		{DeduplicateOptions{Keep: KeepPreferred, PreferredRoots: []string{"/Archive", "/Photos"}}, "/Photos/Originals/beach.jpg"},
+	}
+
+	for _, tt := range tests {
+		plan, err := buildDeduplicationPlan(report, files, tt.opts)
+		if err != nil {
+			t.Fatalf("Failed to build plan for %q: %v", tt.opts.Keep, err)
+		}
+
+		if len(plan.Removals) != 2 {
+			t.Fatalf("Policy %q: expected 2 removals, got %d", tt.opts.Keep, len(plan.Removals))
+		}
+		for _, removal := range plan.Removals {
+			if removal.KeeperPath != tt.keeper {
+				t.Errorf("Policy %q: expected keeper %s, got %s", tt.opts.Keep, tt.keeper, removal.KeeperPath)
+			}
+			if removal.Path == tt.keeper {
+				t.Errorf("Policy %q: keeper must not be removed", tt.opts.Keep)
+			}
+		}
+		if plan.Groups[0].Files[0] != tt.keeper {
+			t.Errorf("Policy %q: expected keeper to be listed first, got %v", tt.opts.Keep, plan.Groups[0].Files)
+		}
+	}
+}
+
+func TestBuildDeduplicationPlan_Actions(t *testing.T) {
+	_, files, report := newDuplicateFixture(t)
+
+	plan, err := buildDeduplicationPlan(report, files, DeduplicateOptions{Action: DuplicateDelete})
+	if err != nil {
+		t.Fatalf("Failed to build plan: %v", err)
+	}
+	for _, removal := range plan.Removals {
+		if removal.Destination != "" {
+			t.Errorf("Delete plans should not have destinations, got %s", removal.Destination)
+		}
+	}
+	if plan.Summary.SpaceSaved != 2*int64(len("holiday photo")) {
+		t.Errorf("Expected SpaceSaved of two copies, got %d", plan.Summary.SpaceSaved)
+	}
+
+	plan, err = buildDeduplicationPlan(report, files, DeduplicateOptions{QuarantineDir: "/Dupes"})
+	if err != nil {
+		t.Fatalf("Failed to build plan: %v", err)
+	}
+	for _, removal := range plan.Removals {
+		expected := "/Dupes/" + plan.ID + removal.Path
+		if removal.Destination != expected {
+			t.Errorf("Expected quarantine destination %s, got %s", expected, removal.Destination)
+		}
+	}
+}
+
+func TestBuildDeduplicationPlan_InvalidOptions(t *testing.T) {
+	_, files, report := newDuplicateFixture(t)
+
+	invalid := []DeduplicateOptions{
+		{Keep: "largest"},
+		{Keep: KeepPreferred},
+		{Action: "archive"},
+	}
+	for _, opts := range invalid {
+		if _, err := buildDeduplicationPlan(report, files, opts); err == nil {
+			t.Errorf("Expected error for options %+v", opts)
+		}
+	}
+}
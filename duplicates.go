@@ -0,0 +1,169 @@
+package curator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// FindDuplicates groups identical files by content. Files are first grouped by
+// size, and only files that share a size are hashed, so unique files are never
+// read. Folders, empty files and files whose hash cannot be computed are ignored.
+// The result is deterministic: groups are ordered by size (largest first) and
+// then by path, and the files of each group are sorted by path.
+func FindDuplicates(files []FileInfo) *DuplicationReport {
+	var groups []DuplicateGroup
+	for size, candidates := range duplicateCandidates(files) {
+		byHash := make(map[string][]string)
+		for _, file := range candidates {
+			if hash := file.Hash(); hash != "" {
+				byHash[hash] = append(byHash[hash], file.Path())
+			}
+		}
+
+		for hash, paths := range byHash {
+			if len(paths) < 2 {
+				continue
+			}
+			sort.Strings(paths)
+			groups = append(groups, DuplicateGroup{Hash: hash, Files: paths, Size: size})
+		}
+	}
+
+	sortDuplicateGroups(groups)
+
+	return &DuplicationReport{
+		ID:         fmt.Sprintf("dup-%d", time.Now().Unix()),
+		Timestamp:  time.Now(),
+		Duplicates: groups,
+		Summary:    summarizeDuplicateGroups(groups),
+	}
+}
+
+// duplicateCandidates groups the files that may have a duplicate by size. Only
+// these files are hashed.
+func duplicateCandidates(files []FileInfo) map[int64][]FileInfo {
+	bySize := make(map[int64][]FileInfo)
+	for _, file := range files {
+		if file.IsDir() || file.Size() == 0 {
+			continue
+		}
+		bySize[file.Size()] = append(bySize[file.Size()], file)
+	}
+
+	for size, candidates := range bySize {
+		if len(candidates) < 2 {
+			delete(bySize, size)
+		}
+	}
+	return bySize
+}
+
+// ConfirmDuplicates compares the files of every group byte by byte and splits
+// groups whose contents differ despite matching hashes. Files that no longer
+// match any other file are dropped.
+func ConfirmDuplicates(ctx context.Context, fs FileSystem, report *DuplicationReport) (*DuplicationReport, error) {
+	var confirmed []DuplicateGroup
+
+	for _, group := range report.Duplicates {
+		// Each partition holds files with identical contents; its first file is the reference
+		var partitions [][]string
+		for _, filePath := range group.Files {
+			placed := false
+			for i, partition := range partitions {
+				same, err := sameContent(ctx, fs, partition[0], filePath)
+				if err != nil {
+					return nil, err
+				}
+				if same {
+					partitions[i] = append(partition, filePath)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				partitions = append(partitions, []string{filePath})
+			}
+		}
+
+		for _, partition := range partitions {
+			if len(partition) > 1 {
+				confirmed = append(confirmed, DuplicateGroup{
+					Hash:        group.Hash,
+					Files:       partition,
+					Size:        group.Size,
+					Explanation: group.Explanation,
+				})
+			}
+		}
+	}
+
+	return &DuplicationReport{
+		ID:         report.ID,
+		Timestamp:  report.Timestamp,
+		Duplicates: confirmed,
+		Summary:    summarizeDuplicateGroups(confirmed),
+	}, nil
+}
+
+// sameContent reports whether two files have identical bytes
+func sameContent(ctx context.Context, fs FileSystem, pathA, pathB string) (bool, error) {
+	readerA, err := fs.Read(ctx, pathA)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", pathA, err)
+	}
+	defer readerA.Close()
+
+	readerB, err := fs.Read(ctx, pathB)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", pathB, err)
+	}
+	defer readerB.Close()
+
+	bufA := make([]byte, 32*1024)
+	bufB := make([]byte, 32*1024)
+	for {
+		nA, errA := io.ReadFull(readerA, bufA)
+		nB, errB := io.ReadFull(readerB, bufB)
+
+		if !bytes.Equal(bufA[:nA], bufB[:nB]) {
+			return false, nil
+		}
+
+		doneA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		doneB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		if errA != nil && !doneA {
+			return false, fmt.Errorf("failed to read %s: %w", pathA, errA)
+		}
+		if errB != nil && !doneB {
+			return false, fmt.Errorf("failed to read %s: %w", pathB, errB)
+		}
+		if doneA || doneB {
+			return doneA == doneB, nil
+		}
+	}
+}
+
+// sortDuplicateGroups orders groups by size (largest first) and then by first path
+func sortDuplicateGroups(groups []DuplicateGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Size != groups[j].Size {
+			return groups[i].Size > groups[j].Size
+		}
+		return groups[i].Files[0] < groups[j].Files[0]
+	})
+}
+
+// summarizeDuplicateGroups counts the redundant copies and the space they use
+func summarizeDuplicateGroups(groups []DuplicateGroup) DuplicationSummary {
+	var summary DuplicationSummary
+	for _, group := range groups {
+		redundant := len(group.Files) - 1
+		summary.TotalDuplicates += redundant
+		summary.SpaceSaved += group.Size * int64(redundant)
+	}
+	return summary
+}
@@ -0,0 +1,115 @@
+package curator
+
+import (
+	"context"
+	"testing"
+)
+
+// countingFileInfo records how often a file is hashed
+type countingFileInfo struct {
+	FileInfo
+	hashCalls *int
+}
+
+func (c countingFileInfo) Hash() string {
+	*c.hashCalls++
+	return c.FileInfo.Hash()
+}
+
+func TestFindDuplicates(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/a/big.bin", []byte("0123456789"), "application/octet-stream")
+	fs.AddFile("/b/big.bin", []byte("0123456789"), "application/octet-stream")
+	fs.AddFile("/c/big copy.bin", []byte("0123456789"), "application/octet-stream")
+	fs.AddFile("/small1.txt", []byte("abc"), "text/plain")
+	fs.AddFile("/small2.txt", []byte("abc"), "text/plain")
+	fs.AddFile("/same-size.txt", []byte("xyz"), "text/plain")
+	fs.AddFile("/empty1.txt", []byte{}, "text/plain")
+	fs.AddFile("/empty2.txt", []byte{}, "text/plain")
+	fs.AddFile("/unique.txt", []byte("only one of these"), "text/plain")
+
+	files, err := getAllFilesRecursively(context.Background(), fs, "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+
+	hashCalls := make(map[string]*int)
+	wrapped := make([]FileInfo, len(files))
+	for i, file := range files {
+		calls := 0
+		hashCalls[file.Path()] = &calls
+		wrapped[i] = countingFileInfo{FileInfo: file, hashCalls: &calls}
+	}
+
+	report := FindDuplicates(wrapped)
+
+	if len(report.Duplicates) != 2 {
+		t.Fatalf("Expected 2 duplicate groups, got %+v", report.Duplicates)
+	}
+
+	// Largest group first, files sorted by path
+	first := report.Duplicates[0]
+	if first.Size != 10 || len(first.Files) != 3 || first.Files[0] != "/a/big.bin" || first.Files[2] != "/c/big copy.bin" {
+		t.Errorf("Unexpected first group: %+v", first)
+	}
+	second := report.Duplicates[1]
+	if len(second.Files) != 2 || second.Files[0] != "/small1.txt" || second.Files[1] != "/small2.txt" {
+		t.Errorf("Unexpected second group: %+v", second)
+	}
+
+	if report.Summary.TotalDuplicates != 3 || report.Summary.SpaceSaved != 2*10+3 {
+		t.Errorf("Unexpected summary: %+v", report.Summary)
+	}
+
+	// Files with a unique size are never hashed
+	if *hashCalls["/unique.txt"] != 0 {
+		t.Error("Expected file with a unique size not to be hashed")
+	}
+	if *hashCalls["/empty1.txt"] != 0 {
+		t.Error("Expected empty files not to be hashed")
+	}
+}
+
+func TestConfirmDuplicates_SplitsHashCollisions(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/one.txt", []byte("aaaa"), "text/plain")
+	fs.AddFile("/two.txt", []byte("aaaa"), "text/plain")
+	fs.AddFile("/three.txt", []byte("bbbb"), "text/plain")
+
+	// Pretend all three share a hash, as they would after a collision
+	report := &DuplicationReport{
+		ID: "dup-1",
+		Duplicates: []DuplicateGroup{
+			{Hash: "collision", Files: []string{"/one.txt", "/three.txt", "/two.txt"}, Size: 4},
+		},
+	}
+
+	confirmed, err := ConfirmDuplicates(context.Background(), fs, report)
+	if err != nil {
+		t.Fatalf("Failed to confirm duplicates: %v", err)
+	}
+
+	if len(confirmed.Duplicates) != 1 {
+		t.Fatalf("Expected 1 confirmed group, got %+v", confirmed.Duplicates)
+	}
+	files := confirmed.Duplicates[0].Files
+	if len(files) != 2 || files[0] != "/one.txt" || files[1] != "/two.txt" {
+		t.Errorf("Expected /one.txt and /two.txt, got %v", files)
+	}
+	if confirmed.Summary.TotalDuplicates != 1 || confirmed.Summary.SpaceSaved != 4 {
+		t.Errorf("Unexpected summary: %+v", confirmed.Summary)
+	}
+}
+
+func TestConfirmDuplicates_MissingFile(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/one.txt", []byte("aaaa"), "text/plain")
+
+	report := &DuplicationReport{
+		Duplicates: []DuplicateGroup{{Hash: "h", Files: []string{"/one.txt", "/gone.txt"}, Size: 4}},
+	}
+
+	if _, err := ConfirmDuplicates(context.Background(), fs, report); err == nil {
+		t.Error("Expected error when a file cannot be read")
+	}
+}
@@ -0,0 +1,79 @@
+package curator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ExcludeFilter decides which paths are left out of a filesystem scan.
+//
+// Patterns use doublestar glob syntax. A pattern starting with "/" is anchored
+// at the filesystem root ("/Private/*"); any other pattern matches at any depth
+// ("node_modules", "*.tmp", "**/cache"). A nil filter excludes nothing.
+type ExcludeFilter struct {
+	patterns []string
+}
+
+// NewExcludeFilter parses a comma-separated list of exclude patterns
+func NewExcludeFilter(exclude string) (*ExcludeFilter, error) {
+	filter := &ExcludeFilter{}
+
+	for _, raw := range strings.Split(exclude, ",") {
+		pattern := strings.TrimSpace(raw)
+		if pattern == "" {
+			continue
+		}
+
+		normalized := normalizeExcludePattern(pattern)
+		if !doublestar.ValidatePattern(normalized) {
+			return nil, fmt.Errorf("invalid exclude pattern: %s", pattern)
+		}
+
+		filter.patterns = append(filter.patterns, normalized)
+	}
+
+	return filter, nil
+}
+
+// Patterns returns the normalized patterns used by the filter
+func (f *ExcludeFilter) Patterns() []string {
+	if f == nil {
+		return nil
+	}
+	return f.patterns
+}
+
+// Matches reports whether the given path is excluded
+func (f *ExcludeFilter) Matches(filePath string) bool {
+	if f == nil || len(f.patterns) == 0 {
+		return false
+	}
+
+	relPath := strings.TrimPrefix(path.Clean("/"+filePath), "/")
+	if relPath == "" {
+		return false // The root itself is never excluded
+	}
+
+	for _, pattern := range f.patterns {
+		if matched, _ := doublestar.Match(pattern, relPath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeExcludePattern converts a user pattern into one matched against root-relative paths
+func normalizeExcludePattern(pattern string) string {
+	if strings.HasPrefix(pattern, "/") {
+		return strings.TrimPrefix(path.Clean(pattern), "/")
+	}
+
+	// Unanchored patterns match at any depth
+	if !strings.HasPrefix(pattern, "**/") {
+		pattern = "**/" + pattern
+	}
+	return pattern
+}
@@ -0,0 +1,118 @@
+package curator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExcludeFilter_Matches(t *testing.T) {
+	filter, err := NewExcludeFilter("/Private/*, **/node_modules, *.tmp, /Work Confidential/")
+	if err != nil {
+		t.Fatalf("Failed to create exclude filter: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		excluded bool
+	}{
+		{"/Private/taxes.pdf", true},
+		{"/Private", false},
+		{"/Public/Private/taxes.pdf", false},
+		{"/node_modules", true},
+		{"/src/app/node_modules", true},
+		{"/src/app/node_modules_backup", false},
+		{"/scratch.tmp", true},
+		{"/Downloads/old/file.tmp", true},
+		{"/Work Confidential", true},
+		{"/Work Confidential Archive", false},
+		{"/", false},
+	}
+
+	for _, tt := range tests {
+		if got := filter.Matches(tt.path); got != tt.excluded {
+			t.Errorf("Matches(%q) = %v, expected %v", tt.path, got, tt.excluded)
+		}
+	}
+}
+
+func TestExcludeFilter_Empty(t *testing.T) {
+	filter, err := NewExcludeFilter(" , ")
+	if err != nil {
+		t.Fatalf("Failed to create exclude filter: %v", err)
+	}
+
+	if filter.Matches("/anything.txt") {
+		t.Error("Empty filter should not exclude anything")
+	}
+
+	var nilFilter *ExcludeFilter
+	if nilFilter.Matches("/anything.txt") {
+		t.Error("Nil filter should not exclude anything")
+	}
+}
+
+func TestExcludeFilter_InvalidPattern(t *testing.T) {
+	if _, err := NewExcludeFilter("/Private/[abc"); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestGetAllFilesRecursively_PrunesExcludedSubtrees(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/report.pdf", []byte("report"), "application/pdf")
+	fs.AddFile("/Private/secret.txt", []byte("secret"), "text/plain")
+	fs.AddFile("/Private/Deep/plans.txt", []byte("plans"), "text/plain")
+	fs.AddFile("/app/node_modules/lib/index.js", []byte("code"), "text/javascript")
+	fs.AddFile("/app/main.js", []byte("code"), "text/javascript")
+
+	filter, err := NewExcludeFilter("/Private,**/node_modules")
+	if err != nil {
+		t.Fatalf("Failed to create exclude filter: %v", err)
+	}
+
+	files, err := getAllFilesRecursively(context.Background(), fs, "/", filter, nil)
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, file := range files {
+		found[file.Path()] = true
+	}
+
+	for _, path := range []string{"/report.pdf", "/app", "/app/main.js"} {
+		if !found[path] {
+			t.Errorf("Expected %s to be listed", path)
+		}
+	}
+
+	for _, path := range []string{"/Private", "/Private/secret.txt", "/Private/Deep/plans.txt", "/app/node_modules", "/app/node_modules/lib/index.js"} {
+		if found[path] {
+			t.Errorf("Expected %s to be excluded", path)
+		}
+	}
+}
+
+func TestExecuteReorganize_ExcludedFilesNotAnalyzed(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/report.pdf", []byte("report"), "application/pdf")
+	fs.AddFile("/Private/secret.pdf", []byte("secret"), "application/pdf")
+
+	opts := CommandOptions{
+		FileSystem: fs,
+		Store:      NewMemoryOperationStore(),
+		Analyzer:   NewMockAIAnalyzer(),
+		Reporter:   NewReporter(),
+	}
+
+	plan, err := ExecuteReorganize(context.Background(), opts, ReorganizeOptions{DryRun: true, Exclude: "/Private/*"})
+	if err != nil {
+		t.Fatalf("ExecuteReorganize failed: %v", err)
+	}
+
+	for _, move := range plan.Moves {
+		if move.Source == "/Private/secret.pdf" {
+			t.Error("Excluded file should never reach the analyzer")
+		}
+	}
+}
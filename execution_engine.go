@@ -1,16 +1,24 @@
 package curator
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
 // ExecutionEngine handles executing reorganization plans with WAL support
 type ExecutionEngine struct {
-	fs    FileSystem
-	store OperationStore
+	fs       FileSystem
+	store    OperationStore
+	progress ProgressReporter
+
+	// folderLocks holds a *sync.Mutex per destination folder, so steps
+	// running at the same time create a missing folder only once
+	folderLocks sync.Map
 }
 
 // NewExecutionEngine creates a new execution engine
@@ -21,123 +29,496 @@ func NewExecutionEngine(fs FileSystem, store OperationStore) *ExecutionEngine {
 	}
 }
 
+// SetProgress makes the engine report the progress of its executions to p
+func (e *ExecutionEngine) SetProgress(p ProgressReporter) {
+	e.progress = p
+}
+
+// ExecuteOptions controls how a plan is executed
+type ExecuteOptions struct {
+	FailFast bool
+	// Resume continues from the plan's last execution: steps it completed are
+	// carried over without running them again, and steps it failed are retried
+	Resume bool
+	// RetrySkipped also retries the steps the last execution skipped; without
+	// it they are carried over as skipped. Only used with Resume.
+	RetrySkipped bool
+	// Filter selects the moves of a reorganization plan to run; the rest are
+	// skipped. Moves a filter skipped are always looked at again on resume.
+	Filter *MoveFilter
+	// Concurrency is how many steps may run at once. Steps that touch the
+	// same path, or a folder and a path inside it, still run in plan order.
+	// Values below 1 run one step at a time.
+	Concurrency int
+}
+
 // ExecutePlan executes a reorganization plan with full WAL support and conflict handling
-func (e *ExecutionEngine) ExecutePlan(planID string, failFast bool) (*ExecutionLog, error) {
-	// Get the plan
-	plan, err := e.store.GetPlan(planID)
+func (e *ExecutionEngine) ExecutePlan(ctx context.Context, planID string, failFast bool) (*ExecutionLog, error) {
+	steps, err := e.reorganizationSteps(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	return e.runSteps(ctx, planID, steps, ExecuteOptions{FailFast: failFast})
+}
+
+// ExecuteCleanupPlan executes the deletions of a cleanup plan with the same WAL
+// support and conflict handling as ExecutePlan
+func (e *ExecutionEngine) ExecuteCleanupPlan(ctx context.Context, planID string, failFast bool) (*ExecutionLog, error) {
+	steps, err := e.cleanupSteps(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	return e.runSteps(ctx, planID, steps, ExecuteOptions{FailFast: failFast})
+}
+
+// ExecuteRenamingPlan executes the renames of a renaming plan with the same WAL
+// support and conflict handling as ExecutePlan
+func (e *ExecutionEngine) ExecuteRenamingPlan(ctx context.Context, planID string, failFast bool) (*ExecutionLog, error) {
+	steps, err := e.renamingSteps(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	return e.runSteps(ctx, planID, steps, ExecuteOptions{FailFast: failFast})
+}
+
+// ExecuteDeduplicationPlan removes the redundant copies of a deduplication plan
+// with the same WAL support and conflict handling as ExecutePlan
+func (e *ExecutionEngine) ExecuteDeduplicationPlan(ctx context.Context, planID string, failFast bool) (*ExecutionLog, error) {
+	steps, err := e.deduplicationSteps(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	return e.runSteps(ctx, planID, steps, ExecuteOptions{FailFast: failFast})
+}
+
+// ExecutePlanWithOptions executes a saved plan of any type
+func (e *ExecutionEngine) ExecutePlanWithOptions(ctx context.Context, planID string, opts ExecuteOptions) (*ExecutionLog, error) {
+	steps, err := e.planSteps(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.runSteps(ctx, planID, steps, opts)
+}
+
+// planSteps returns the steps of a saved plan of any type
+func (e *ExecutionEngine) planSteps(ctx context.Context, planID string) ([]executionStep, error) {
+	planType, err := FindPlanType(ctx, e.store, planID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get plan: %w", err)
 	}
-	
+
+	switch planType {
+	case PlanTypeCleanup:
+		return e.cleanupSteps(ctx, planID)
+	case PlanTypeRenaming:
+		return e.renamingSteps(ctx, planID)
+	case PlanTypeDeduplication:
+		return e.deduplicationSteps(ctx, planID)
+	default:
+		return e.reorganizationSteps(ctx, planID)
+	}
+}
+
+// reorganizationSteps turns the moves of a reorganization plan into steps
+func (e *ExecutionEngine) reorganizationSteps(ctx context.Context, planID string) ([]executionStep, error) {
+	plan, err := e.store.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	steps := make([]executionStep, 0, len(plan.Moves))
+	for _, move := range plan.Moves {
+		if !plan.runsMove(move) {
+			continue
+		}
+
+		opData, err := json.Marshal(move)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal move data: %w", err)
+		}
+
+		move := move
+		steps = append(steps, executionStep{
+			id:     move.ID,
+			opType: "move",
+			data:   opData,
+			move:   &move,
+			paths:  []string{move.Source, move.Destination},
+			run:    func(ctx context.Context) error { return e.executeMove(ctx, move) },
+		})
+	}
+
+	return steps, nil
+}
+
+// cleanupSteps turns the deletions of a cleanup plan into steps
+func (e *ExecutionEngine) cleanupSteps(ctx context.Context, planID string) ([]executionStep, error) {
+	plan, err := e.store.GetCleanupPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleanup plan: %w", err)
+	}
+
+	steps := make([]executionStep, 0, len(plan.Deletions))
+	for _, deletion := range plan.Deletions {
+		opData, err := json.Marshal(deletion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal deletion data: %w", err)
+		}
+
+		deletion := deletion
+		steps = append(steps, executionStep{
+			id:     deletion.ID,
+			opType: "delete",
+			data:   opData,
+			paths:  []string{deletion.Path},
+			run:    func(ctx context.Context) error { return e.executeDeletion(ctx, deletion) },
+		})
+	}
+
+	return steps, nil
+}
+
+// renamingSteps turns the renames of a renaming plan into steps
+func (e *ExecutionEngine) renamingSteps(ctx context.Context, planID string) ([]executionStep, error) {
+	plan, err := e.store.GetRenamingPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renaming plan: %w", err)
+	}
+
+	steps := make([]executionStep, 0, len(plan.Renames))
+	for _, rename := range plan.Renames {
+		opData, err := json.Marshal(rename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal rename data: %w", err)
+		}
+
+		rename := rename
+		steps = append(steps, executionStep{
+			id:     rename.ID,
+			opType: "rename",
+			data:   opData,
+			paths:  []string{rename.OldPath, rename.NewPath},
+			run:    func(ctx context.Context) error { return e.executeRename(ctx, rename) },
+		})
+	}
+
+	return steps, nil
+}
+
+// deduplicationSteps turns the removals of a deduplication plan into steps
+func (e *ExecutionEngine) deduplicationSteps(ctx context.Context, planID string) ([]executionStep, error) {
+	plan, err := e.store.GetDeduplicationPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deduplication plan: %w", err)
+	}
+
+	steps := make([]executionStep, 0, len(plan.Removals))
+	for _, removal := range plan.Removals {
+		opData, err := json.Marshal(removal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal removal data: %w", err)
+		}
+
+		removal := removal
+		steps = append(steps, executionStep{
+			id:     removal.ID,
+			opType: "dedup",
+			data:   opData,
+			paths:  []string{removal.Path, removal.KeeperPath, removal.Destination},
+			run:    func(ctx context.Context) error { return e.executeDuplicateRemoval(ctx, removal) },
+		})
+	}
+
+	return steps, nil
+}
+
+// executionStep is a single plan entry as seen by runSteps
+type executionStep struct {
+	id     string
+	opType string
+	data   []byte
+	move   *Move    // Set for the moves of reorganization plans
+	paths  []string // Paths the step reads or changes, used to order steps
+	run    func(ctx context.Context) error
+}
+
+// runSteps executes steps, logging each one to the WAL and recording the
+// outcome in a new execution log for planID. Up to opts.Concurrency steps run
+// at once, in plan order as far as their dependencies allow. Cancelling ctx
+// stops new steps from starting: the steps in flight finish and the log is
+// saved with StatusInterrupted. A deadline on ctx also bounds the steps in
+// flight.
+func (e *ExecutionEngine) runSteps(ctx context.Context, planID string, steps []executionStep, opts ExecuteOptions) (*ExecutionLog, error) {
+	if opts.Filter != nil {
+		for _, step := range steps {
+			if step.move == nil {
+				return nil, fmt.Errorf("move filters only apply to reorganization plans")
+			}
+		}
+	}
+
 	// Initialize execution log
+	startTime := time.Now()
 	execLog := &ExecutionLog{
+		ID:        newExecutionID(planID, startTime),
 		PlanID:    planID,
-		Timestamp: time.Now(),
+		Timestamp: startTime,
 		Status:    StatusInProgress,
 		Completed: make([]CompletedMove, 0),
 		Failed:    make([]FailedMove, 0),
 		Skipped:   make([]SkippedMove, 0),
 	}
-	
+
+	// When resuming, outcomes of the previous execution that stand are carried
+	// over so the new log describes the plan as a whole
+	completedBefore := make(map[string]CompletedMove)
+	skippedBefore := make(map[string]SkippedMove)
+	if opts.Resume {
+		previous, err := e.GetExecutionStatus(ctx, planID)
+		if err != nil {
+			return nil, fmt.Errorf("nothing to resume: %w", err)
+		}
+		execLog.ResumedFrom = previous.Key()
+		for _, completed := range previous.Completed {
+			completedBefore[completed.MoveID] = completed
+		}
+		if !opts.RetrySkipped {
+			for _, skipped := range previous.Skipped {
+				if skipped.Reason != SkipReasonFiltered {
+					skippedBefore[skipped.MoveID] = skipped
+				}
+			}
+		}
+	}
+
+	// Once the execution has started, the log and the WAL have to keep up with
+	// the filesystem, so store calls ignore cancellation. Steps only honour the
+	// deadline, which lets an interrupted step finish.
+	interrupt := ctx
+	ctx = context.WithoutCancel(ctx)
+	stepCtx := ctx
+	if deadline, ok := interrupt.Deadline(); ok {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
 	// Save initial execution log
-	if err := e.store.SaveExecutionLog(execLog); err != nil {
+	if err := e.store.SaveExecutionLog(ctx, execLog); err != nil {
 		return nil, fmt.Errorf("failed to save initial execution log: %w", err)
 	}
-	
-	// Execute moves in order
-	for _, move := range plan.Moves {
-		// Log operation to WAL before executing
-		opData, err := json.Marshal(move)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal move data: %w", err)
+
+	// Outcomes carried over and moves the filter leaves out need no work
+	var queued []int
+	for i, step := range steps {
+		if completed, ok := completedBefore[step.id]; ok {
+			execLog.Completed = append(execLog.Completed, completed)
+			continue
 		}
-		
-		operation := &Operation{
-			ID:        fmt.Sprintf("%s-%s", planID, move.ID),
-			Type:      "move",
-			Data:      opData,
-			Timestamp: time.Now(),
+		if skipped, ok := skippedBefore[step.id]; ok {
+			execLog.Skipped = append(execLog.Skipped, skipped)
+			continue
 		}
-		
-		if err := e.store.LogOperation(operation); err != nil {
-			return nil, fmt.Errorf("failed to log operation to WAL: %w", err)
+		if step.move != nil && !opts.Filter.Selects(*step.move) {
+			execLog.Skipped = append(execLog.Skipped, SkippedMove{
+				MoveID:    step.id,
+				Timestamp: time.Now(),
+				Reason:    SkipReasonFiltered,
+			})
+			continue
 		}
-		
-		// Execute the move
-		err = e.executeMove(move)
+		queued = append(queued, i)
+	}
+
+	// Steps start in plan order once the earlier steps they depend on are
+	// done. The WAL and the log are only written from this goroutine.
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	graph := newStepGraph(steps, queued)
+	results := make(chan stepResult, concurrency)
+	running, finished := 0, 0
+	var failure, storeErr error
+
+	progress := func(done bool) {
+		elapsed := time.Since(startTime)
+		reportProgress(e.progress, ProgressEvent{
+			Phase:     PhaseApply,
+			Total:     len(steps),
+			Completed: len(execLog.Completed),
+			Failed:    len(execLog.Failed),
+			Skipped:   len(execLog.Skipped),
+			Elapsed:   elapsed,
+			ETA:       estimateRemaining(elapsed, int64(finished), int64(len(queued)-finished)),
+			Done:      done,
+		})
+	}
+	progress(false)
+
+	for {
+		for running < concurrency && failure == nil && storeErr == nil && interrupt.Err() == nil {
+			i, ok := graph.next()
+			if !ok {
+				break
+			}
+
+			// Log operation to WAL before executing
+			step := steps[i]
+			operation := &Operation{
+				ID:          fmt.Sprintf("%s-%s", execLog.ID, step.id),
+				Type:        step.opType,
+				Data:        step.data,
+				Timestamp:   time.Now(),
+				PlanID:      planID,
+				ExecutionID: execLog.ID,
+				StepID:      step.id,
+			}
+			if err := e.store.LogOperation(ctx, operation); err != nil {
+				storeErr = fmt.Errorf("failed to log operation to WAL: %w", err)
+				break
+			}
+
+			running++
+			go func() {
+				results <- stepResult{index: i, operation: operation, err: step.run(stepCtx)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		step := steps[result.index]
+		err := result.err
+
+		if err != nil && stepCtx.Err() != nil {
+			// The deadline cut the step short; it stays pending in the WAL so
+			// recovery can find out whether it took effect
+			continue
+		}
+		if storeErr != nil {
+			// Steps still running when the store failed are left to recovery
+			continue
+		}
+		finished++
+		graph.done(result.index)
+
 		if err != nil {
 			// Check if this is a conflict (file doesn't exist or destination exists)
 			if isConflictError(err) {
-				// Skip this move and continue
+				// Skip this step and continue
 				execLog.Skipped = append(execLog.Skipped, SkippedMove{
-					MoveID:    move.ID,
+					MoveID:    step.id,
 					Timestamp: time.Now(),
 					Reason:    fmt.Sprintf("Conflict: %s", err.Error()),
 				})
 			} else {
 				// Real error - mark as failed
 				execLog.Failed = append(execLog.Failed, FailedMove{
-					MoveID:    move.ID,
+					MoveID:    step.id,
 					Timestamp: time.Now(),
 					Error:     err.Error(),
+					Kind:      failureKind(err),
 				})
-				
-				if failFast {
-					execLog.Status = StatusFailed
-					e.store.SaveExecutionLog(execLog)
-					return execLog, fmt.Errorf("execution failed (fail-fast enabled): %w", err)
+
+				// With fail-fast no new step starts; steps already running finish
+				if opts.FailFast && failure == nil {
+					failure = err
 				}
 			}
 		} else {
-			// Move succeeded - mark as completed
+			// Step succeeded - mark as completed
 			execLog.Completed = append(execLog.Completed, CompletedMove{
-				MoveID:    move.ID,
+				MoveID:    step.id,
 				Timestamp: time.Now(),
 			})
 		}
-		
+
 		// Mark operation as complete in WAL
-		if err := e.store.MarkOperationComplete(operation.ID); err != nil {
-			return nil, fmt.Errorf("failed to mark operation complete: %w", err)
+		if err := e.store.MarkOperationComplete(ctx, result.operation.ID); err != nil {
+			storeErr = fmt.Errorf("failed to mark operation complete: %w", err)
+			continue
 		}
-		
+
 		// Update execution log
-		if err := e.store.SaveExecutionLog(execLog); err != nil {
-			return nil, fmt.Errorf("failed to update execution log: %w", err)
+		if err := e.store.SaveExecutionLog(ctx, execLog); err != nil {
+			storeErr = fmt.Errorf("failed to update execution log: %w", err)
+			continue
 		}
+		progress(false)
+	}
+
+	if storeErr != nil {
+		return nil, storeErr
+	}
+
+	if failure != nil {
+		// The failure is recorded in the log, so the operation is no longer pending
+		execLog.Status = StatusFailed
+		execLog.EndTime = time.Now()
+		e.store.SaveExecutionLog(ctx, execLog)
+		progress(true)
+		return execLog, fmt.Errorf("execution failed (fail-fast enabled): %w", failure)
 	}
-	
+
 	// Determine final status
-	if len(execLog.Failed) > 0 {
-		if len(execLog.Completed) > 0 {
-			execLog.Status = StatusPartial
-		} else {
-			execLog.Status = StatusFailed
-		}
-	} else if len(execLog.Skipped) > 0 {
-		// If no failures but some operations were skipped, it's partial
-		execLog.Status = StatusPartial
-	} else {
-		execLog.Status = StatusCompleted
+	execLog.Status = finalStatus(execLog, len(steps))
+	if finished < len(queued) {
+		// Interrupted: steps that never ran are picked up on resume
+		execLog.Status = StatusInterrupted
 	}
-	
+	execLog.EndTime = time.Now()
+
 	// Save final execution log
-	if err := e.store.SaveExecutionLog(execLog); err != nil {
+	if err := e.store.SaveExecutionLog(ctx, execLog); err != nil {
 		return nil, fmt.Errorf("failed to save final execution log: %w", err)
 	}
-	
+	progress(true)
+
 	return execLog, nil
 }
 
+// stepResult is the outcome of a step run by a runSteps worker
+type stepResult struct {
+	index     int
+	operation *Operation
+	err       error
+}
+
+// finalStatus derives the status of a finished execution of totalSteps steps
+func finalStatus(execLog *ExecutionLog, totalSteps int) ExecutionStatus {
+	recorded := len(execLog.Completed) + len(execLog.Failed) + len(execLog.Skipped)
+
+	if len(execLog.Failed) > 0 {
+		if len(execLog.Completed) > 0 {
+			return StatusPartial
+		}
+		return StatusFailed
+	}
+	if len(execLog.Skipped) > 0 || recorded < totalSteps {
+		// Skipped or never attempted steps leave the plan partially applied
+		return StatusPartial
+	}
+	return StatusCompleted
+}
+
+// newExecutionID returns a unique ID for one attempt to execute a plan
+func newExecutionID(planID string, startTime time.Time) string {
+	return fmt.Sprintf("%s-exec-%d", planID, startTime.UnixNano())
+}
+
 // executeMove executes a single move operation
-func (e *ExecutionEngine) executeMove(move Move) error {
+func (e *ExecutionEngine) executeMove(ctx context.Context, move Move) error {
 	switch move.Type {
 	case CreateFolder:
-		return e.fs.CreateFolder(move.Destination)
+		return e.fs.CreateFolder(ctx, move.Destination)
 		
 	case FileMove:
 		// Check if source still exists
-		exists, err := e.fs.Exists(move.Source)
+		exists, err := e.fs.Exists(ctx, move.Source)
 		if err != nil {
 			return fmt.Errorf("failed to check if source exists: %w", err)
 		}
@@ -146,7 +527,7 @@ func (e *ExecutionEngine) executeMove(move Move) error {
 		}
 		
 		// Check if destination already exists
-		destExists, err := e.fs.Exists(move.Destination)
+		destExists, err := e.fs.Exists(ctx, move.Destination)
 		if err != nil {
 			return fmt.Errorf("failed to check if destination exists: %w", err)
 		}
@@ -156,16 +537,16 @@ func (e *ExecutionEngine) executeMove(move Move) error {
 		
 		// Ensure destination directory exists
 		destDir := filepath.Dir(move.Destination)
-		if err := e.fs.CreateFolder(destDir); err != nil {
+		if err := e.ensureFolder(ctx, destDir); err != nil {
 			return fmt.Errorf("failed to create destination directory: %w", err)
 		}
 		
 		// Execute the move
-		return e.fs.Move(move.Source, move.Destination)
+		return e.fs.Move(ctx, move.Source, move.Destination)
 		
 	case FolderMove:
 		// Check if source folder still exists
-		exists, err := e.fs.Exists(move.Source)
+		exists, err := e.fs.Exists(ctx, move.Source)
 		if err != nil {
 			return fmt.Errorf("failed to check if source folder exists: %w", err)
 		}
@@ -174,7 +555,7 @@ func (e *ExecutionEngine) executeMove(move Move) error {
 		}
 		
 		// Check if destination already exists
-		destExists, err := e.fs.Exists(move.Destination)
+		destExists, err := e.fs.Exists(ctx, move.Destination)
 		if err != nil {
 			return fmt.Errorf("failed to check if destination exists: %w", err)
 		}
@@ -184,55 +565,362 @@ func (e *ExecutionEngine) executeMove(move Move) error {
 		
 		// Ensure parent of destination directory exists
 		destParent := filepath.Dir(move.Destination)
-		if err := e.fs.CreateFolder(destParent); err != nil {
+		if err := e.ensureFolder(ctx, destParent); err != nil {
 			return fmt.Errorf("failed to create destination parent directory: %w", err)
 		}
 		
 		// Execute the folder move
-		return e.fs.Move(move.Source, move.Destination)
-		
+		return e.fs.Move(ctx, move.Source, move.Destination)
+
+	case RemoveFolder:
+		exists, err := e.fs.Exists(ctx, move.Destination)
+		if err != nil {
+			return fmt.Errorf("failed to check if folder exists: %w", err)
+		}
+		if !exists {
+			return &ConflictError{Message: fmt.Sprintf("folder no longer exists: %s", move.Destination)}
+		}
+
+		// Only remove folders that are empty so rollback never deletes user data
+		contents, err := e.fs.List(ctx, move.Destination)
+		if err != nil {
+			return fmt.Errorf("failed to list folder: %w", err)
+		}
+		if len(contents) > 0 {
+			return &ConflictError{Message: fmt.Sprintf("folder is not empty: %s", move.Destination)}
+		}
+
+		return e.fs.Delete(ctx, move.Destination)
+
 	default:
 		return fmt.Errorf("unknown move type: %s", move.Type)
 	}
 }
 
-// ResumePendingOperations resumes any pending operations from WAL after a crash
-func (e *ExecutionEngine) ResumePendingOperations() error {
-	pending, err := e.store.GetPendingOperations()
+// ensureFolder creates folder unless it already exists
+func (e *ExecutionEngine) ensureFolder(ctx context.Context, folder string) error {
+	lock, _ := e.folderLocks.LoadOrStore(folder, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	exists, err := e.fs.Exists(ctx, folder)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return e.fs.CreateFolder(ctx, folder)
+}
+
+// executeDeletion deletes a single file after checking that it is still the
+// file that was analyzed
+func (e *ExecutionEngine) executeDeletion(ctx context.Context, deletion Deletion) error {
+	// Refuse to delete anything that changed since the plan was made
+	if err := e.checkUnchanged(ctx, deletion.Path, deletion.Size, deletion.Hash); err != nil {
+		return err
+	}
+
+	return e.fs.Delete(ctx, deletion.Path)
+}
+
+// executeDuplicateRemoval deletes or quarantines one redundant copy. Both the copy
+// and its keeper must still have the analyzed contents, so a removal can never
+// destroy the last copy of a file.
+func (e *ExecutionEngine) executeDuplicateRemoval(ctx context.Context, removal DuplicateRemoval) error {
+	if removal.Hash == "" {
+		return &ConflictError{Message: fmt.Sprintf("no content hash recorded for %s", removal.Path)}
+	}
+	if err := e.checkUnchanged(ctx, removal.KeeperPath, removal.Size, removal.Hash); err != nil {
+		return &ConflictError{Message: fmt.Sprintf("keeper %s cannot be verified: %s", removal.KeeperPath, err.Error())}
+	}
+	if err := e.checkUnchanged(ctx, removal.Path, removal.Size, removal.Hash); err != nil {
+		return err
+	}
+
+	if removal.Destination == "" {
+		return e.fs.Delete(ctx, removal.Path)
+	}
+
+	return e.executeMove(ctx, Move{
+		ID:          removal.ID,
+		Source:      removal.Path,
+		Destination: removal.Destination,
+		Type:        FileMove,
+		FileCount:   1,
+	})
+}
+
+// checkUnchanged returns a ConflictError unless path is a file with the given
+// size and, when hash is set, the given content hash
+func (e *ExecutionEngine) checkUnchanged(ctx context.Context, path string, size int64, hash string) error {
+	info, err := statPath(ctx, e.fs, path)
+	if err != nil {
+		return fmt.Errorf("failed to look up file: %w", err)
+	}
+	if info == nil {
+		return &ConflictError{Message: fmt.Sprintf("file no longer exists: %s", path)}
+	}
+	if info.IsDir() {
+		return &ConflictError{Message: fmt.Sprintf("path is now a folder: %s", path)}
+	}
+
+	if info.Size() != size {
+		return &ConflictError{Message: fmt.Sprintf("file size changed since analysis: %s", path)}
+	}
+	if hash != "" && info.Hash() != hash {
+		return &ConflictError{Message: fmt.Sprintf("file contents changed since analysis: %s", path)}
+	}
+
+	return nil
+}
+
+// executeRename renames a single file within its folder
+func (e *ExecutionEngine) executeRename(ctx context.Context, rename Rename) error {
+	if filepath.Dir(rename.OldPath) != filepath.Dir(rename.NewPath) {
+		return fmt.Errorf("rename %s would move %s to another folder", rename.ID, rename.OldPath)
+	}
+
+	exists, err := e.fs.Exists(ctx, rename.OldPath)
+	if err != nil {
+		return fmt.Errorf("failed to check if source exists: %w", err)
+	}
+	if !exists {
+		return &ConflictError{Message: fmt.Sprintf("source file no longer exists: %s", rename.OldPath)}
+	}
+
+	destExists, err := e.fs.Exists(ctx, rename.NewPath)
+	if err != nil {
+		return fmt.Errorf("failed to check if destination exists: %w", err)
+	}
+	if destExists {
+		return &ConflictError{Message: fmt.Sprintf("destination already exists: %s", rename.NewPath)}
+	}
+
+	return e.fs.Move(ctx, rename.OldPath, rename.NewPath)
+}
+
+// statPath returns the FileInfo for a path, or nil if it does not exist
+func statPath(ctx context.Context, fs FileSystem, path string) (FileInfo, error) {
+	if stater, ok := fs.(FileStater); ok {
+		return stater.Stat(ctx, path)
+	}
+
+	exists, err := fs.Exists(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	entries, err := fs.List(ctx, filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if filepath.Clean(entry.Path()) == filepath.Clean(path) {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// RollbackPlan undoes the moves completed by every execution of a plan, so a
+// plan that took several attempts is rolled back as a whole. The inverse moves
+// are saved as a rollback plan and executed through ExecutePlan, so they get the
+// same WAL and conflict handling as the original run. A plan is rolled back
+// once: an unfinished rollback is resumed like any other plan.
+func (e *ExecutionEngine) RollbackPlan(ctx context.Context, planID string, failFast bool) (*ExecutionLog, error) {
+	plan, err := e.getReversiblePlan(ctx, planID)
 	if err != nil {
-		return fmt.Errorf("failed to get pending operations: %w", err)
-	}
-	
-	if len(pending) == 0 {
-		return nil // Nothing to resume
-	}
-	
-	fmt.Printf("Found %d pending operations to resume\n", len(pending))
-	
-	for _, op := range pending {
-		if op.Type == "move" {
-			var move Move
-			if err := json.Unmarshal(op.Data, &move); err != nil {
-				fmt.Printf("Failed to unmarshal move data for operation %s: %v\n", op.ID, err)
-				continue
+		return nil, err
+	}
+
+	if err := e.checkNotRolledBack(ctx, planID); err != nil {
+		return nil, err
+	}
+
+	attempts, err := e.GetExecutionAttempts(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempts[0].Status == StatusInProgress {
+		return nil, fmt.Errorf("plan %s is still in progress and cannot be rolled back", planID)
+	}
+
+	// Attempts are newest first; replay them oldest first so the rollback
+	// undoes moves in the reverse of the order they happened
+	combined := &ExecutionLog{PlanID: planID}
+	seen := make(map[string]bool)
+	for i := len(attempts) - 1; i >= 0; i-- {
+		for _, completed := range attempts[i].Completed {
+			if !seen[completed.MoveID] {
+				seen[completed.MoveID] = true
+				combined.Completed = append(combined.Completed, completed)
 			}
-			
-			// Try to execute the move
-			err := e.executeMove(move)
-			if err != nil {
-				fmt.Printf("Failed to resume move operation %s: %v\n", op.ID, err)
-			} else {
-				fmt.Printf("Successfully resumed move operation %s\n", op.ID)
+		}
+	}
+
+	rollback, err := buildRollbackPlan(plan, combined)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.store.SavePlan(ctx, rollback); err != nil {
+		return nil, fmt.Errorf("failed to save rollback plan: %w", err)
+	}
+
+	return e.ExecutePlan(ctx, rollback.ID, failFast)
+}
+
+// checkNotRolledBack refuses a plan whose rollback plan has already been
+// executed. Rolling back again would overwrite that rollback plan and report
+// every move it undid as a conflict.
+func (e *ExecutionEngine) checkNotRolledBack(ctx context.Context, planID string) error {
+	rollbackID := rollbackPlanID(planID)
+	if _, err := e.store.GetPlan(ctx, rollbackID); err != nil {
+		if errors.Is(err, ErrPlanNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check for an earlier rollback: %w", err)
+	}
+
+	attempts, err := e.store.GetExecutionLogs(ctx, rollbackID)
+	if err != nil {
+		return fmt.Errorf("failed to check for an earlier rollback: %w", err)
+	}
+	if len(attempts) == 0 {
+		// Saved but never executed
+		return nil
+	}
+	if attempts[0].Status == StatusCompleted {
+		return fmt.Errorf("plan %s has already been rolled back by %s", planID, rollbackID)
+	}
+	return fmt.Errorf("plan %s is already being rolled back by %s (%s): run 'curator apply %s --resume' to finish it", planID, rollbackID, attempts[0].Status, rollbackID)
+}
+
+// rollbackPlanID is the ID a plan's rollback plan is saved under
+func rollbackPlanID(planID string) string {
+	return "rollback-" + planID
+}
+
+// getReversiblePlan loads a plan that can be rolled back, presenting renaming
+// and quarantine plans as the equivalent file moves
+func (e *ExecutionEngine) getReversiblePlan(ctx context.Context, planID string) (*ReorganizationPlan, error) {
+	plan, err := e.store.GetPlan(ctx, planID)
+	if err == nil {
+		return plan, nil
+	}
+
+	if renamingPlan, renameErr := e.store.GetRenamingPlan(ctx, planID); renameErr == nil {
+		moves := make([]Move, len(renamingPlan.Renames))
+		for i, rename := range renamingPlan.Renames {
+			moves[i] = Move{
+				ID:          rename.ID,
+				Source:      rename.OldPath,
+				Destination: rename.NewPath,
+				Reason:      rename.Reason,
+				Type:        FileMove,
+				FileCount:   1,
 			}
-			
-			// Mark as complete regardless of success/failure
-			if err := e.store.MarkOperationComplete(op.ID); err != nil {
-				fmt.Printf("Failed to mark operation %s as complete: %v\n", op.ID, err)
+		}
+		return &ReorganizationPlan{ID: renamingPlan.ID, Timestamp: renamingPlan.Timestamp, Moves: moves}, nil
+	}
+
+	if dedupPlan, dedupErr := e.store.GetDeduplicationPlan(ctx, planID); dedupErr == nil {
+		if dedupPlan.Action != DuplicateQuarantine {
+			return nil, fmt.Errorf("plan %s deleted its duplicates; deletions cannot be rolled back", planID)
+		}
+
+		// Quarantined copies are moved back to where they were found
+		moves := make([]Move, len(dedupPlan.Removals))
+		for i, removal := range dedupPlan.Removals {
+			moves[i] = Move{
+				ID:          removal.ID,
+				Source:      removal.Path,
+				Destination: removal.Destination,
+				Reason:      fmt.Sprintf("Quarantine duplicate of %s", removal.KeeperPath),
+				Type:        FileMove,
+				FileCount:   1,
 			}
 		}
+		return &ReorganizationPlan{ID: dedupPlan.ID, Timestamp: dedupPlan.Timestamp, Moves: moves}, nil
 	}
-	
-	return nil
+
+	if _, cleanupErr := e.store.GetCleanupPlan(ctx, planID); cleanupErr == nil {
+		return nil, fmt.Errorf("plan %s is a cleanup plan; deletions cannot be rolled back", planID)
+	}
+
+	return nil, fmt.Errorf("failed to get plan: %w", err)
+}
+
+// buildRollbackPlan creates a plan that reverses the completed moves of an execution
+func buildRollbackPlan(plan *ReorganizationPlan, execLog *ExecutionLog) (*ReorganizationPlan, error) {
+	movesByID := make(map[string]Move, len(plan.Moves))
+	for _, move := range plan.Moves {
+		movesByID[move.ID] = move
+	}
+
+	var moves []Move
+	summary := Summary{}
+
+	// Undo in reverse order so folders are emptied before they are removed
+	for i := len(execLog.Completed) - 1; i >= 0; i-- {
+		completed := execLog.Completed[i]
+		move, ok := movesByID[completed.MoveID]
+		if !ok {
+			return nil, fmt.Errorf("completed move %s not found in plan %s", completed.MoveID, plan.ID)
+		}
+
+		switch move.Type {
+		case FileMove, FolderMove:
+			moves = append(moves, Move{
+				ID:          move.ID,
+				Source:      move.Destination,
+				Destination: move.Source,
+				Reason:      fmt.Sprintf("Undo %s: return %s to its original location", move.ID, move.Source),
+				Type:        move.Type,
+				FileCount:   move.FileCount,
+			})
+			summary.FilesMoved += max(move.FileCount, 1)
+
+		case CreateFolder:
+			moves = append(moves, Move{
+				ID:          move.ID,
+				Destination: move.Destination,
+				Reason:      fmt.Sprintf("Undo %s: remove folder created by the plan if it is empty", move.ID),
+				Type:        RemoveFolder,
+			})
+
+		case RemoveFolder:
+			moves = append(moves, Move{
+				ID:          move.ID,
+				Destination: move.Destination,
+				Reason:      fmt.Sprintf("Undo %s: recreate removed folder", move.ID),
+				Type:        CreateFolder,
+			})
+			summary.FoldersCreated++
+
+		default:
+			return nil, fmt.Errorf("cannot roll back move %s of type %s", move.ID, move.Type)
+		}
+	}
+
+	if len(moves) == 0 {
+		return nil, fmt.Errorf("nothing to roll back: no completed moves for plan %s", plan.ID)
+	}
+	summary.OrganizationImprovement = fmt.Sprintf("Restores the layout from before plan %s", plan.ID)
+
+	return &ReorganizationPlan{
+		ID:        rollbackPlanID(plan.ID),
+		Timestamp: time.Now(),
+		Moves:     moves,
+		Summary:   summary,
+		Rationale: fmt.Sprintf("Roll back the %d completed operations of plan %s", len(moves), plan.ID),
+	}, nil
 }
 
 // ConflictError represents a conflict during execution
@@ -244,36 +932,63 @@ func (e *ConflictError) Error() string {
 	return e.Message
 }
 
+// StorageQuotaError reports that a filesystem refused an operation because
+// its storage is full. Retrying fails the same way until space is freed.
+type StorageQuotaError struct {
+	Err error
+}
+
+func (e *StorageQuotaError) Error() string {
+	return fmt.Sprintf("storage quota exceeded: %v", e.Err)
+}
+
+func (e *StorageQuotaError) Unwrap() error {
+	return e.Err
+}
+
+// failureKind classifies the error of a failed step
+func failureKind(err error) FailureKind {
+	var quotaErr *StorageQuotaError
+	if errors.As(err, &quotaErr) {
+		return FailureStorageQuota
+	}
+	return ""
+}
+
 // isConflictError checks if an error is a conflict error
 func isConflictError(err error) bool {
 	_, ok := err.(*ConflictError)
 	return ok
 }
 
-// GetExecutionStatus returns the current status of a plan execution
-func (e *ExecutionEngine) GetExecutionStatus(planID string) (*ExecutionLog, error) {
-	history, err := e.store.GetExecutionHistory()
+// GetExecutionStatus returns the most recent finished execution of a plan, or
+// the one in progress if none has finished
+func (e *ExecutionEngine) GetExecutionStatus(ctx context.Context, planID string) (*ExecutionLog, error) {
+	attempts, err := e.GetExecutionAttempts(ctx, planID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get execution history: %w", err)
+		return nil, err
 	}
-	
-	// Find the most recent execution log for this plan
-	// History is sorted newest first, so find the first match with final status
-	for _, log := range history {
-		if log.PlanID == planID {
-			// Return logs that are not in progress (final state)
-			if log.Status != StatusInProgress {
-				return log, nil
-			}
-		}
-	}
-	
-	// If no completed logs found, return the most recent one (could be in progress)
-	for _, log := range history {
-		if log.PlanID == planID {
+
+	// Attempts are sorted newest first, so find the first one with a final status
+	for _, log := range attempts {
+		if log.Status != StatusInProgress {
 			return log, nil
 		}
 	}
-	
-	return nil, fmt.Errorf("no execution found for plan: %s", planID)
-}
\ No newline at end of file
+
+	return attempts[0], nil
+}
+
+// GetExecutionAttempts returns every execution of a plan, newest first
+func (e *ExecutionEngine) GetExecutionAttempts(ctx context.Context, planID string) ([]*ExecutionLog, error) {
+	attempts, err := e.store.GetExecutionLogs(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution history: %w", err)
+	}
+
+	if len(attempts) == 0 {
+		return nil, fmt.Errorf("no execution found for plan: %s", planID)
+	}
+
+	return attempts, nil
+}
@@ -1,6 +1,10 @@
 package curator
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -44,13 +48,13 @@ func TestExecutionEngine_ExecutePlan_Success(t *testing.T) {
 	}
 	
 	// Save the plan
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save plan: %v", err)
 	}
 	
 	// Execute the plan
-	execLog, err := engine.ExecutePlan(plan.ID, false)
+	execLog, err := engine.ExecutePlan(context.Background(), plan.ID, false)
 	if err != nil {
 		t.Fatalf("Failed to execute plan: %v", err)
 	}
@@ -73,7 +77,7 @@ func TestExecutionEngine_ExecutePlan_Success(t *testing.T) {
 	}
 	
 	// Verify files were actually moved
-	exists, err := fs.Exists("Documents/document.pdf")
+	exists, err := fs.Exists(context.Background(), "Documents/document.pdf")
 	if err != nil {
 		t.Fatalf("Failed to check if file exists: %v", err)
 	}
@@ -82,7 +86,7 @@ func TestExecutionEngine_ExecutePlan_Success(t *testing.T) {
 	}
 	
 	// Original file should no longer exist
-	exists, err = fs.Exists("/document.pdf")
+	exists, err = fs.Exists(context.Background(), "/document.pdf")
 	if err != nil {
 		t.Fatalf("Failed to check if original file exists: %v", err)
 	}
@@ -100,7 +104,7 @@ func TestExecutionEngine_ExecutePlan_WithConflicts(t *testing.T) {
 	fs.AddFile("/document.pdf", []byte("content"), "application/pdf")
 	
 	// Create Documents folder and destination file to cause conflict
-	fs.CreateFolder("Documents")
+	fs.CreateFolder(context.Background(), "Documents")
 	fs.AddFile("Documents/document.pdf", []byte("existing"), "application/pdf")
 	
 	// Create a plan that will have conflicts
@@ -133,13 +137,13 @@ func TestExecutionEngine_ExecutePlan_WithConflicts(t *testing.T) {
 	}
 	
 	// Save the plan
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save plan: %v", err)
 	}
 	
 	// Execute the plan
-	execLog, err := engine.ExecutePlan(plan.ID, false)
+	execLog, err := engine.ExecutePlan(context.Background(), plan.ID, false)
 	if err != nil {
 		t.Fatalf("Failed to execute plan: %v", err)
 	}
@@ -193,13 +197,13 @@ func TestExecutionEngine_ExecutePlan_FailFast(t *testing.T) {
 	}
 	
 	// Save the plan
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save plan: %v", err)
 	}
 	
 	// Execute the plan with fail-fast enabled
-	execLog, err := engine.ExecutePlan(plan.ID, true)
+	execLog, err := engine.ExecutePlan(context.Background(), plan.ID, true)
 	
 	// Should not return an error for conflicts (they are skipped)
 	if err != nil {
@@ -242,18 +246,18 @@ func TestExecutionEngine_GetExecutionStatus(t *testing.T) {
 	}
 	
 	// Save and execute the plan
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save plan: %v", err)
 	}
 	
-	_, err = engine.ExecutePlan(plan.ID, false)
+	_, err = engine.ExecutePlan(context.Background(), plan.ID, false)
 	if err != nil {
 		t.Fatalf("Failed to execute plan: %v", err)
 	}
 	
 	// Get execution status
-	status, err := engine.GetExecutionStatus(plan.ID)
+	status, err := engine.GetExecutionStatus(context.Background(), plan.ID)
 	if err != nil {
 		t.Fatalf("Failed to get execution status: %v", err)
 	}
@@ -294,13 +298,13 @@ func TestExecutionEngine_ResumePendingOperations(t *testing.T) {
 	}
 	
 	// Log the operation but don't mark it complete
-	err := store.LogOperation(operation)
+	err := store.LogOperation(context.Background(), operation)
 	if err != nil {
 		t.Fatalf("Failed to log operation: %v", err)
 	}
 	
 	// Verify there's a pending operation
-	pending, err := store.GetPendingOperations()
+	pending, err := store.GetPendingOperations(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get pending operations: %v", err)
 	}
@@ -310,13 +314,13 @@ func TestExecutionEngine_ResumePendingOperations(t *testing.T) {
 	}
 	
 	// Resume pending operations
-	err = engine.ResumePendingOperations()
+	err = engine.ResumePendingOperations(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to resume pending operations: %v", err)
 	}
 	
 	// Verify the operation was completed
-	pending, err = store.GetPendingOperations()
+	pending, err = store.GetPendingOperations(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get pending operations after resume: %v", err)
 	}
@@ -324,4 +328,737 @@ func TestExecutionEngine_ResumePendingOperations(t *testing.T) {
 	if len(pending) != 0 {
 		t.Errorf("Expected 0 pending operations after resume, got %d", len(pending))
 	}
-}
\ No newline at end of file
+}
+func TestExecutionEngine_RollbackPlan(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/document.pdf", []byte("content"), "application/pdf")
+	fs.AddFile("/Projects/notes.txt", []byte("notes"), "text/plain")
+
+	plan := &ReorganizationPlan{
+		ID:        "test-plan-rollback",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Destination: "/Documents", Type: CreateFolder},
+			{ID: "move-2", Source: "/document.pdf", Destination: "/Documents/document.pdf", Type: FileMove, FileCount: 1},
+			{ID: "move-3", Source: "/Projects", Destination: "/Documents/Projects", Type: FolderMove, FileCount: 1},
+		},
+	}
+
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	if _, err := engine.ExecutePlan(context.Background(), plan.ID, false); err != nil {
+		t.Fatalf("Failed to execute plan: %v", err)
+	}
+
+	execLog, err := engine.RollbackPlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to roll back plan: %v", err)
+	}
+
+	if execLog.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, execLog.Status)
+	}
+
+	// Moves should be undone in reverse order
+	expectedOrder := []string{"move-3", "move-2", "move-1"}
+	if len(execLog.Completed) != len(expectedOrder) {
+		t.Fatalf("Expected %d completed moves, got %d", len(expectedOrder), len(execLog.Completed))
+	}
+	for i, moveID := range expectedOrder {
+		if execLog.Completed[i].MoveID != moveID {
+			t.Errorf("Expected move %d to be %s, got %s", i, moveID, execLog.Completed[i].MoveID)
+		}
+	}
+
+	for _, path := range []string{"/document.pdf", "/Projects/notes.txt"} {
+		if exists, _ := fs.Exists(context.Background(), path); !exists {
+			t.Errorf("Expected %s to be restored", path)
+		}
+	}
+
+	if exists, _ := fs.Exists(context.Background(), "/Documents"); exists {
+		t.Error("Expected empty folder created by the plan to be removed")
+	}
+
+	// The rollback plan is stored so it can be inspected later
+	if _, err := store.GetPlan(context.Background(), "rollback-" + plan.ID); err != nil {
+		t.Errorf("Expected rollback plan to be saved: %v", err)
+	}
+
+	// Rolling back again would only report every undone move as a conflict
+	if _, err := engine.RollbackPlan(context.Background(), plan.ID, false); err == nil || !strings.Contains(err.Error(), "already been rolled back") {
+		t.Errorf("Expected a second rollback to be refused, got %v", err)
+	}
+}
+
+func TestExecutionEngine_RollbackPlan_UnfinishedRollbackIsResumed(t *testing.T) {
+	ctx := context.Background()
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/a.txt", []byte("a"), "text/plain")
+	fs.AddFile("/b.txt", []byte("b"), "text/plain")
+	fs.CreateFolder(ctx, "/Docs")
+	plan := &ReorganizationPlan{
+		ID:        "test-plan-rollback-twice",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Docs/b.txt", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(ctx, plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+	if _, err := engine.ExecutePlan(ctx, plan.ID, false); err != nil {
+		t.Fatalf("ExecutePlan failed: %v", err)
+	}
+
+	// Something new at /b.txt stops one move from being undone
+	fs.AddFile("/b.txt", []byte("new"), "text/plain")
+	execLog, err := engine.RollbackPlan(ctx, plan.ID, false)
+	if err != nil {
+		t.Fatalf("RollbackPlan failed: %v", err)
+	}
+	if execLog.Status == StatusCompleted {
+		t.Fatalf("Expected the rollback to be incomplete, got %s", execLog.Status)
+	}
+
+	if _, err := engine.RollbackPlan(ctx, plan.ID, false); err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Errorf("Expected a second rollback to point to resuming the first, got %v", err)
+	}
+}
+
+func TestExecutionEngine_RollbackPlan_KeepsNonEmptyFolders(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/document.pdf", []byte("content"), "application/pdf")
+
+	plan := &ReorganizationPlan{
+		ID:        "test-plan-rollback-nonempty",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Destination: "/Documents", Type: CreateFolder},
+			{ID: "move-2", Source: "/document.pdf", Destination: "/Documents/document.pdf", Type: FileMove, FileCount: 1},
+		},
+	}
+
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	if _, err := engine.ExecutePlan(context.Background(), plan.ID, false); err != nil {
+		t.Fatalf("Failed to execute plan: %v", err)
+	}
+
+	// A file added by the user after the plan ran must keep the folder alive
+	fs.AddFile("/Documents/new.txt", []byte("new"), "text/plain")
+
+	execLog, err := engine.RollbackPlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to roll back plan: %v", err)
+	}
+
+	if execLog.Status != StatusPartial {
+		t.Errorf("Expected status %s, got %s", StatusPartial, execLog.Status)
+	}
+
+	if len(execLog.Skipped) != 1 || execLog.Skipped[0].MoveID != "move-1" {
+		t.Errorf("Expected folder removal to be skipped, got %+v", execLog.Skipped)
+	}
+
+	if exists, _ := fs.Exists(context.Background(), "/Documents/new.txt"); !exists {
+		t.Error("Rollback must not delete files it did not move")
+	}
+
+	if exists, _ := fs.Exists(context.Background(), "/document.pdf"); !exists {
+		t.Error("Expected moved file to be restored")
+	}
+}
+
+func TestExecutionEngine_RollbackPlan_NoExecution(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	plan := &ReorganizationPlan{ID: "never-applied", Timestamp: time.Now()}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	if _, err := engine.RollbackPlan(context.Background(), plan.ID, false); err == nil {
+		t.Error("Expected error when rolling back a plan that was never executed")
+	}
+}
+
+func TestExecutionEngine_KeepsEveryAttempt(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/a.txt", []byte("a"), "text/plain")
+	fs.AddFile("/Docs/b.txt", []byte("taken"), "text/plain")
+	fs.AddFile("/b.txt", []byte("b"), "text/plain")
+
+	plan := &ReorganizationPlan{
+		ID:        "retry-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Docs/b.txt", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	// The first attempt is partial because /Docs/b.txt is in the way
+	first, err := engine.ExecutePlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("First attempt failed: %v", err)
+	}
+	if first.Status != StatusPartial || first.ID == "" || first.EndTime.IsZero() {
+		t.Fatalf("Unexpected first attempt: %+v", first)
+	}
+
+	if err := fs.Delete(context.Background(), "/Docs/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	second, err := engine.ExecutePlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Second attempt failed: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Fatalf("Expected each attempt to get its own execution ID, both were %s", first.ID)
+	}
+
+	attempts, err := engine.GetExecutionAttempts(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("Failed to get attempts: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].ID != second.ID || attempts[1].ID != first.ID {
+		t.Fatalf("Expected both attempts newest first, got %+v", attempts)
+	}
+	if len(attempts[1].Completed) != 1 || attempts[1].Completed[0].MoveID != "move-1" {
+		t.Errorf("First attempt was overwritten: %+v", attempts[1])
+	}
+
+	status, err := engine.GetExecutionStatus(context.Background(), plan.ID)
+	if err != nil || status.ID != second.ID {
+		t.Errorf("Expected status to report the latest attempt, got %+v (%v)", status, err)
+	}
+
+	// Rollback undoes the moves of both attempts
+	if _, err := engine.RollbackPlan(context.Background(), plan.ID, false); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	for _, path := range []string{"/a.txt", "/b.txt"} {
+		if exists, _ := fs.Exists(context.Background(), path); !exists {
+			t.Errorf("Expected %s to be restored", path)
+		}
+	}
+}
+
+// flakyFileSystem fails the first Move of each listed source
+type flakyFileSystem struct {
+	FileSystem
+	failMoves map[string]bool
+}
+
+func (f *flakyFileSystem) Move(ctx context.Context, source, destination string) error {
+	if f.failMoves[source] {
+		delete(f.failMoves, source)
+		return fmt.Errorf("transient failure moving %s", source)
+	}
+	return f.FileSystem.Move(ctx, source, destination)
+}
+
+func TestExecutionEngine_ResumePartialExecution(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	fs := &flakyFileSystem{FileSystem: memFS, failMoves: map[string]bool{"/b.txt": true}}
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	memFS.AddFile("/a.txt", []byte("a"), "text/plain")
+	memFS.AddFile("/b.txt", []byte("b"), "text/plain")
+	memFS.AddFile("/c.txt", []byte("c"), "text/plain")
+	memFS.AddFile("/Docs/c.txt", []byte("blocker"), "text/plain")
+
+	plan := &ReorganizationPlan{
+		ID:        "resume-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Docs/b.txt", Type: FileMove},
+			{ID: "move-3", Source: "/c.txt", Destination: "/Docs/c.txt", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	first, err := engine.ExecutePlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("First attempt failed: %v", err)
+	}
+	if len(first.Completed) != 1 || len(first.Failed) != 1 || len(first.Skipped) != 1 {
+		t.Fatalf("Unexpected first attempt: %+v", first)
+	}
+
+	if err := memFS.Delete(context.Background(), "/Docs/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resuming retries the failed move, keeps the completed one and leaves the skipped one
+	second, err := engine.ExecutePlanWithOptions(context.Background(), plan.ID, ExecuteOptions{Resume: true})
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if second.ResumedFrom != first.ID {
+		t.Errorf("Expected resume from %s, got %q", first.ID, second.ResumedFrom)
+	}
+	if len(second.Completed) != 2 || len(second.Failed) != 0 || len(second.Skipped) != 1 {
+		t.Fatalf("Unexpected resumed attempt: %+v", second)
+	}
+	if second.Completed[0].MoveID != "move-1" || !second.Completed[0].Timestamp.Equal(first.Completed[0].Timestamp) {
+		t.Errorf("Expected move-1 to be carried over unchanged, got %+v", second.Completed[0])
+	}
+	if second.Status != StatusPartial {
+		t.Errorf("Expected status %s, got %s", StatusPartial, second.Status)
+	}
+
+	// Retrying skipped moves finishes the plan
+	third, err := engine.ExecutePlanWithOptions(context.Background(), plan.ID, ExecuteOptions{Resume: true, RetrySkipped: true})
+	if err != nil {
+		t.Fatalf("Resume with retry-skipped failed: %v", err)
+	}
+	if third.Status != StatusCompleted || len(third.Completed) != 3 {
+		t.Errorf("Expected all 3 moves completed, got %+v", third)
+	}
+	for _, path := range []string{"/Docs/a.txt", "/Docs/b.txt", "/Docs/c.txt"} {
+		if exists, _ := memFS.Exists(context.Background(), path); !exists {
+			t.Errorf("Expected %s to exist", path)
+		}
+	}
+}
+
+// interruptingFileSystem calls interrupt while moving source, then carries out
+// the move unless the context it was given is done
+type interruptingFileSystem struct {
+	FileSystem
+	source    string
+	interrupt func(ctx context.Context)
+}
+
+func (f *interruptingFileSystem) Move(ctx context.Context, source, destination string) error {
+	if source == f.source {
+		f.interrupt(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return f.FileSystem.Move(ctx, source, destination)
+}
+
+func newInterruptPlan(t *testing.T, fs *MemoryFileSystem, store OperationStore) *ReorganizationPlan {
+	t.Helper()
+	plan := &ReorganizationPlan{ID: "interrupt-plan", Timestamp: time.Now()}
+	for i, name := range []string{"a", "b", "c"} {
+		source := "/" + name + ".txt"
+		fs.AddFile(source, []byte(name), "text/plain")
+		plan.Moves = append(plan.Moves, Move{ID: fmt.Sprintf("move-%d", i+1), Source: source, Destination: "/Docs" + source, Type: FileMove})
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+	return plan
+}
+
+func TestExecutionEngine_Interrupt(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	fs := &interruptingFileSystem{FileSystem: memFS, source: "/b.txt", interrupt: func(context.Context) { cancel() }}
+	engine := NewExecutionEngine(fs, store)
+	plan := newInterruptPlan(t, memFS, store)
+
+	execLog, err := engine.ExecutePlan(ctx, plan.ID, false)
+	if err != nil {
+		t.Fatalf("Interrupted execution returned an error: %v", err)
+	}
+
+	// The move in flight finishes; the next one never starts
+	if execLog.Status != StatusInterrupted || len(execLog.Completed) != 2 || len(execLog.Failed) != 0 {
+		t.Fatalf("Expected 2 completed moves and status %s, got %+v", StatusInterrupted, execLog)
+	}
+	if exists, _ := memFS.Exists(context.Background(), "/c.txt"); !exists {
+		t.Error("Expected /c.txt not to be moved after the interrupt")
+	}
+	if saved, _ := engine.GetExecutionStatus(context.Background(), plan.ID); saved == nil || saved.Status != StatusInterrupted {
+		t.Errorf("Expected the interrupted log to be saved, got %+v", saved)
+	}
+	if pending, _ := store.GetPendingOperations(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected no pending operations, got %d", len(pending))
+	}
+
+	resumed, err := engine.ExecutePlanWithOptions(context.Background(), plan.ID, ExecuteOptions{Resume: true})
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if resumed.Status != StatusCompleted || len(resumed.Completed) != 3 {
+		t.Errorf("Expected the resumed execution to finish the plan, got %+v", resumed)
+	}
+}
+
+func TestExecutionEngine_InterruptByDeadline(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The move of /b.txt is still running when the deadline passes
+	fs := &interruptingFileSystem{FileSystem: memFS, source: "/b.txt", interrupt: func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}}
+	engine := NewExecutionEngine(fs, store)
+	plan := newInterruptPlan(t, memFS, store)
+
+	execLog, err := engine.ExecutePlan(ctx, plan.ID, false)
+	if err != nil {
+		t.Fatalf("Interrupted execution returned an error: %v", err)
+	}
+	if execLog.Status != StatusInterrupted || len(execLog.Completed) != 1 || len(execLog.Failed) != 0 {
+		t.Fatalf("Expected 1 completed move and status %s, got %+v", StatusInterrupted, execLog)
+	}
+
+	// The move that was cut short is left for recovery
+	pending, _ := store.GetPendingOperations(context.Background())
+	if len(pending) != 1 || pending[0].StepID != "move-2" {
+		t.Fatalf("Expected move-2 to stay pending, got %+v", pending)
+	}
+}
+
+// concurrentFileSystem slows moves down and records how many overlap and
+// whether any ran before its destination folder existed
+type concurrentFileSystem struct {
+	*MemoryFileSystem
+	mu       sync.Mutex
+	running  int
+	peak     int
+	orphaned []string
+}
+
+func (f *concurrentFileSystem) Move(ctx context.Context, source, destination string) error {
+	f.mu.Lock()
+	f.running++
+	if f.running > f.peak {
+		f.peak = f.running
+	}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	return f.MemoryFileSystem.Move(ctx, source, destination)
+}
+
+func (f *concurrentFileSystem) CreateFolder(ctx context.Context, path string) error {
+	if path == "/Docs/Inbox" {
+		if exists, _ := f.Exists(ctx, "/Docs"); !exists {
+			f.mu.Lock()
+			f.orphaned = append(f.orphaned, path)
+			f.mu.Unlock()
+		}
+	}
+	return f.MemoryFileSystem.CreateFolder(ctx, path)
+}
+
+func TestExecutionEngine_ExecutePlan_Concurrent(t *testing.T) {
+	fs := &concurrentFileSystem{MemoryFileSystem: NewMemoryFileSystem()}
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	plan := &ReorganizationPlan{ID: "concurrent-plan", Timestamp: time.Now()}
+	plan.Moves = append(plan.Moves,
+		Move{ID: "move-0", Destination: "/Docs", Type: CreateFolder},
+		Move{ID: "move-1", Destination: "/Docs/Inbox", Type: CreateFolder},
+	)
+	for i := 0; i < 20; i++ {
+		source := fmt.Sprintf("/file%02d.txt", i)
+		fs.AddFile(source, []byte(source), "text/plain")
+		plan.Moves = append(plan.Moves, Move{ID: fmt.Sprintf("move-%d", i+2), Source: source, Destination: "/Docs/Inbox" + source, Type: FileMove})
+	}
+	fs.AddFolder("/Old")
+	plan.Moves = append(plan.Moves, Move{ID: "move-22", Destination: "/Old", Type: RemoveFolder})
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	execLog, err := engine.ExecutePlanWithOptions(context.Background(), plan.ID, ExecuteOptions{Concurrency: 4})
+	if err != nil {
+		t.Fatalf("Failed to execute plan: %v", err)
+	}
+	if execLog.Status != StatusCompleted || len(execLog.Completed) != len(plan.Moves) {
+		t.Fatalf("Expected all %d moves completed, got %+v", len(plan.Moves), execLog)
+	}
+	if fs.peak < 2 || fs.peak > 4 {
+		t.Errorf("Expected between 2 and 4 moves at once, got %d", fs.peak)
+	}
+	if len(fs.orphaned) > 0 {
+		t.Errorf("Folders were created before their parent: %v", fs.orphaned)
+	}
+	if pending, _ := store.GetPendingOperations(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected no pending operations, got %d", len(pending))
+	}
+	saved, err := engine.GetExecutionStatus(context.Background(), plan.ID)
+	if err != nil || len(saved.Completed) != len(plan.Moves) {
+		t.Errorf("Expected the saved log to list every move, got %+v, %v", saved, err)
+	}
+	if entries, _ := fs.List(context.Background(), "/Docs/Inbox"); len(entries) != 20 {
+		t.Errorf("Expected 20 files in /Docs/Inbox, got %d", len(entries))
+	}
+}
+
+func TestExecutionEngine_ResumeWithoutExecution(t *testing.T) {
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(NewMemoryFileSystem(), store)
+
+	if err := store.SavePlan(context.Background(), &ReorganizationPlan{ID: "fresh", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	if _, err := engine.ExecutePlanWithOptions(context.Background(), "fresh", ExecuteOptions{Resume: true}); err == nil {
+		t.Error("Expected error resuming a plan that was never executed")
+	}
+}
+
+func TestExecutionEngine_ExecuteCleanupPlan(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/old.tmp", []byte("junk"), "text/plain")
+	fs.AddFile("/edited.log", []byte("log"), "text/plain")
+	fs.AddFile("/keep.txt", []byte("keep"), "text/plain")
+
+	files, _ := fs.List(context.Background(), "/")
+	infos := make(map[string]FileInfo)
+	for _, file := range files {
+		infos[file.Path()] = file
+	}
+
+	plan := &CleanupPlan{
+		ID:        "cleanup-plan-1",
+		Timestamp: time.Now(),
+		Deletions: []Deletion{
+			{ID: "del-1", Path: "/old.tmp", Size: infos["/old.tmp"].Size(), Hash: infos["/old.tmp"].Hash()},
+			{ID: "del-2", Path: "/edited.log", Size: infos["/edited.log"].Size(), Hash: infos["/edited.log"].Hash()},
+			{ID: "del-3", Path: "/gone.bak", Size: 10},
+		},
+	}
+	if err := store.SaveCleanupPlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save cleanup plan: %v", err)
+	}
+
+	// The log file grows after analysis, so it must not be deleted
+	fs.AddFile("/edited.log", []byte("log with new entries"), "text/plain")
+
+	execLog, err := engine.ExecuteCleanupPlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to execute cleanup plan: %v", err)
+	}
+
+	if execLog.Status != StatusPartial {
+		t.Errorf("Expected status %s, got %s", StatusPartial, execLog.Status)
+	}
+	if len(execLog.Completed) != 1 || execLog.Completed[0].MoveID != "del-1" {
+		t.Errorf("Expected only del-1 to complete, got %+v", execLog.Completed)
+	}
+	if len(execLog.Skipped) != 2 {
+		t.Errorf("Expected 2 skipped deletions, got %d", len(execLog.Skipped))
+	}
+
+	if exists, _ := fs.Exists(context.Background(), "/old.tmp"); exists {
+		t.Error("Expected /old.tmp to be deleted")
+	}
+	for _, path := range []string{"/edited.log", "/keep.txt"} {
+		if exists, _ := fs.Exists(context.Background(), path); !exists {
+			t.Errorf("Expected %s to be kept", path)
+		}
+	}
+
+	pending, _ := store.GetPendingOperations(context.Background())
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending operations, got %d", len(pending))
+	}
+}
+
+func TestExecutionEngine_ExecuteCleanupPlan_HashMismatch(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/cache.tmp", []byte("aaaa"), "text/plain")
+
+	store.SaveCleanupPlan(context.Background(), &CleanupPlan{
+		ID:        "cleanup-plan-2",
+		Timestamp: time.Now(),
+		Deletions: []Deletion{
+			{ID: "del-1", Path: "/cache.tmp", Size: 4, Hash: "not-the-analyzed-hash"},
+		},
+	})
+
+	execLog, err := engine.ExecuteCleanupPlan(context.Background(), "cleanup-plan-2", false)
+	if err != nil {
+		t.Fatalf("Failed to execute cleanup plan: %v", err)
+	}
+
+	if len(execLog.Skipped) != 1 {
+		t.Errorf("Expected the changed file to be skipped, got %+v", execLog)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/cache.tmp"); !exists {
+		t.Error("Expected /cache.tmp to be kept")
+	}
+
+	if _, err := engine.RollbackPlan(context.Background(), "cleanup-plan-2", false); err == nil {
+		t.Error("Expected rollback of a cleanup plan to fail")
+	}
+}
+
+func TestExecutionEngine_ExecuteRenamingPlan(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	fs.AddFile("/Docs/My Notes.txt", []byte("notes"), "text/plain")
+	fs.AddFile("/Docs/Taken.txt", []byte("one"), "text/plain")
+	fs.AddFile("/Docs/taken.txt", []byte("two"), "text/plain")
+
+	plan := &RenamingPlan{
+		ID:        "rename-plan-1",
+		Timestamp: time.Now(),
+		Renames: []Rename{
+			{ID: "rename-1", OldPath: "/Docs/My Notes.txt", NewPath: "/Docs/my_notes.txt", NewName: "my_notes.txt"},
+			{ID: "rename-2", OldPath: "/Docs/Taken.txt", NewPath: "/Docs/taken.txt", NewName: "taken.txt"},
+			{ID: "rename-3", OldPath: "/Docs/my_notes.txt", NewPath: "/Elsewhere/notes.txt", NewName: "notes.txt"},
+		},
+	}
+	if err := store.SaveRenamingPlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save renaming plan: %v", err)
+	}
+
+	execLog, err := engine.ExecuteRenamingPlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to execute renaming plan: %v", err)
+	}
+
+	if len(execLog.Completed) != 1 || len(execLog.Skipped) != 1 || len(execLog.Failed) != 1 {
+		t.Fatalf("Expected 1 completed, 1 skipped and 1 failed rename, got %+v", execLog)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/Docs/my_notes.txt"); !exists {
+		t.Error("Expected /Docs/my_notes.txt to exist after rename")
+	}
+	if exists, _ := fs.Exists(context.Background(), "/Docs/Taken.txt"); !exists {
+		t.Error("Expected /Docs/Taken.txt to be left alone")
+	}
+
+	// Rolling back restores the original name
+	if _, err := engine.RollbackPlan(context.Background(), plan.ID, false); err != nil {
+		t.Fatalf("Failed to roll back renaming plan: %v", err)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/Docs/My Notes.txt"); !exists {
+		t.Error("Expected /Docs/My Notes.txt to be restored by rollback")
+	}
+	if exists, _ := fs.Exists(context.Background(), "/Docs/my_notes.txt"); exists {
+		t.Error("Expected /Docs/my_notes.txt to be gone after rollback")
+	}
+}
+
+func TestExecutionEngine_ExecuteDeduplicationPlan_Quarantine(t *testing.T) {
+	fs, files, report := newDuplicateFixture(t)
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	plan, err := buildDeduplicationPlan(report, files, DeduplicateOptions{Keep: KeepShortestPath})
+	if err != nil {
+		t.Fatalf("Failed to build plan: %v", err)
+	}
+	store.SaveDeduplicationPlan(context.Background(), plan)
+
+	execLog, err := engine.ExecuteDeduplicationPlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to execute deduplication plan: %v", err)
+	}
+	if execLog.Status != StatusCompleted {
+		t.Fatalf("Expected status %s, got %s (%+v)", StatusCompleted, execLog.Status, execLog)
+	}
+
+	if exists, _ := fs.Exists(context.Background(), "/Downloads/beach.jpg"); !exists {
+		t.Error("Expected keeper to remain in place")
+	}
+	for _, removal := range plan.Removals {
+		if exists, _ := fs.Exists(context.Background(), removal.Path); exists {
+			t.Errorf("Expected %s to be quarantined", removal.Path)
+		}
+		if exists, _ := fs.Exists(context.Background(), removal.Destination); !exists {
+			t.Errorf("Expected quarantined copy at %s", removal.Destination)
+		}
+	}
+
+	// Quarantine is reversible
+	if _, err := engine.RollbackPlan(context.Background(), plan.ID, false); err != nil {
+		t.Fatalf("Failed to roll back quarantine: %v", err)
+	}
+	for _, removal := range plan.Removals {
+		if exists, _ := fs.Exists(context.Background(), removal.Path); !exists {
+			t.Errorf("Expected %s to be restored", removal.Path)
+		}
+	}
+}
+
+func TestExecutionEngine_ExecuteDeduplicationPlan_VerifiesHashes(t *testing.T) {
+	fs, files, report := newDuplicateFixture(t)
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	plan, err := buildDeduplicationPlan(report, files, DeduplicateOptions{Keep: KeepShortestPath, Action: DuplicateDelete})
+	if err != nil {
+		t.Fatalf("Failed to build plan: %v", err)
+	}
+	store.SaveDeduplicationPlan(context.Background(), plan)
+
+	// The keeper is edited after analysis, so none of the copies may be deleted
+	fs.AddFile("/Downloads/beach.jpg", []byte("holiday photo, cropped"), "image/jpeg")
+
+	execLog, err := engine.ExecuteDeduplicationPlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to execute deduplication plan: %v", err)
+	}
+	if len(execLog.Completed) != 0 || len(execLog.Skipped) != len(plan.Removals) {
+		t.Errorf("Expected every removal to be skipped, got %+v", execLog)
+	}
+	for _, removal := range plan.Removals {
+		if exists, _ := fs.Exists(context.Background(), removal.Path); !exists {
+			t.Errorf("Expected %s to be kept", removal.Path)
+		}
+	}
+
+	if _, err := engine.RollbackPlan(context.Background(), plan.ID, false); err == nil {
+		t.Error("Expected rollback of a delete plan to fail")
+	}
+}
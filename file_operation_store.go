@@ -1,8 +1,10 @@
 package curator
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,7 +13,19 @@ import (
 	"time"
 )
 
-// FileOperationStore implements OperationStore interface using JSON files
+// fileStoreSubdirs are the directories holding each kind of record
+var fileStoreSubdirs = []string{"plans", "cleanup_plans", "renaming_plans", "dedup_plans", "operations", "execution_logs"}
+
+// tempFilePrefix marks files that are still being written; they never end in
+// .json, so readers ignore them until they are renamed into place
+const tempFilePrefix = ".tmp-"
+
+// corruptDir is where Repair moves records that cannot be read
+const corruptDir = "corrupt"
+
+// FileOperationStore implements OperationStore interface using JSON files.
+// Every record is written to a temporary file, synced and renamed into place,
+// so a crash leaves either the old or the new record, never a truncated one.
 type FileOperationStore struct {
 	mu       sync.RWMutex
 	storeDir string
@@ -25,8 +39,7 @@ func NewFileOperationStore(storeDir string) (*FileOperationStore, error) {
 	}
 
 	// Create subdirectories
-	subdirs := []string{"plans", "operations", "execution_logs"}
-	for _, subdir := range subdirs {
+	for _, subdir := range fileStoreSubdirs {
 		if err := os.MkdirAll(filepath.Join(storeDir, subdir), 0755); err != nil {
 			return nil, fmt.Errorf("failed to create subdirectory %s: %w", subdir, err)
 		}
@@ -38,7 +51,11 @@ func NewFileOperationStore(storeDir string) (*FileOperationStore, error) {
 }
 
 // SavePlan implements OperationStore.SavePlan
-func (f *FileOperationStore) SavePlan(plan *ReorganizationPlan) error {
+func (f *FileOperationStore) SavePlan(ctx context.Context, plan *ReorganizationPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -48,7 +65,7 @@ func (f *FileOperationStore) SavePlan(plan *ReorganizationPlan) error {
 		return fmt.Errorf("failed to marshal plan: %w", err)
 	}
 
-	if err := os.WriteFile(planPath, data, 0644); err != nil {
+	if err := writeFileAtomic(planPath, data); err != nil {
 		return fmt.Errorf("failed to write plan file: %w", err)
 	}
 
@@ -56,7 +73,7 @@ func (f *FileOperationStore) SavePlan(plan *ReorganizationPlan) error {
 }
 
 // GetPlan implements OperationStore.GetPlan
-func (f *FileOperationStore) GetPlan(id string) (*ReorganizationPlan, error) {
+func (f *FileOperationStore) GetPlan(ctx context.Context, id string) (*ReorganizationPlan, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
@@ -64,7 +81,7 @@ func (f *FileOperationStore) GetPlan(id string) (*ReorganizationPlan, error) {
 	data, err := os.ReadFile(planPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("plan not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrPlanNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to read plan file: %w", err)
 	}
@@ -78,7 +95,7 @@ func (f *FileOperationStore) GetPlan(id string) (*ReorganizationPlan, error) {
 }
 
 // ListPlans implements OperationStore.ListPlans
-func (f *FileOperationStore) ListPlans() ([]*PlanSummary, error) {
+func (f *FileOperationStore) ListPlans(ctx context.Context) ([]*PlanSummary, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
@@ -94,13 +111,15 @@ func (f *FileOperationStore) ListPlans() ([]*PlanSummary, error) {
 			planID := strings.TrimSuffix(entry.Name(), ".json")
 			
 			// Read the plan to get details
-			plan, err := f.GetPlan(planID)
+			plan, err := f.GetPlan(ctx, planID)
 			if err != nil {
-				continue // Skip corrupted files
+				warnCorruptRecord("plan", planID, err)
+				continue
 			}
 
 			summary := &PlanSummary{
 				ID:        plan.ID,
+				Type:      PlanTypeReorganization,
 				Timestamp: plan.Timestamp,
 				Status:    "pending", // Default status
 				FileCount: len(plan.Moves),
@@ -110,6 +129,81 @@ func (f *FileOperationStore) ListPlans() ([]*PlanSummary, error) {
 		}
 	}
 
+	cleanupEntries, err := os.ReadDir(filepath.Join(f.storeDir, "cleanup_plans"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cleanup plans directory: %w", err)
+	}
+
+	for _, entry := range cleanupEntries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			planID := strings.TrimSuffix(entry.Name(), ".json")
+			plan, err := f.GetCleanupPlan(ctx, planID)
+			if err != nil {
+				warnCorruptRecord("cleanup plan", planID, err)
+				continue
+			}
+
+			summaries = append(summaries, &PlanSummary{
+				ID:        plan.ID,
+				Type:      PlanTypeCleanup,
+				Timestamp: plan.Timestamp,
+				Status:    "pending",
+				FileCount: len(plan.Deletions),
+				MoveCount: len(plan.Deletions),
+			})
+		}
+	}
+
+	renameEntries, err := os.ReadDir(filepath.Join(f.storeDir, "renaming_plans"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read renaming plans directory: %w", err)
+	}
+
+	for _, entry := range renameEntries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			planID := strings.TrimSuffix(entry.Name(), ".json")
+			plan, err := f.GetRenamingPlan(ctx, planID)
+			if err != nil {
+				warnCorruptRecord("renaming plan", planID, err)
+				continue
+			}
+
+			summaries = append(summaries, &PlanSummary{
+				ID:        plan.ID,
+				Type:      PlanTypeRenaming,
+				Timestamp: plan.Timestamp,
+				Status:    "pending",
+				FileCount: len(plan.Renames),
+				MoveCount: len(plan.Renames),
+			})
+		}
+	}
+
+	dedupEntries, err := os.ReadDir(filepath.Join(f.storeDir, "dedup_plans"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deduplication plans directory: %w", err)
+	}
+
+	for _, entry := range dedupEntries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			planID := strings.TrimSuffix(entry.Name(), ".json")
+			plan, err := f.GetDeduplicationPlan(ctx, planID)
+			if err != nil {
+				warnCorruptRecord("deduplication plan", planID, err)
+				continue
+			}
+
+			summaries = append(summaries, &PlanSummary{
+				ID:        plan.ID,
+				Type:      PlanTypeDeduplication,
+				Timestamp: plan.Timestamp,
+				Status:    "pending",
+				FileCount: len(plan.Removals),
+				MoveCount: len(plan.Removals),
+			})
+		}
+	}
+
 	// Sort by timestamp descending (newest first)
 	sort.Slice(summaries, func(i, j int) bool {
 		return summaries[i].Timestamp.After(summaries[j].Timestamp)
@@ -118,8 +212,140 @@ func (f *FileOperationStore) ListPlans() ([]*PlanSummary, error) {
 	return summaries, nil
 }
 
+// SaveCleanupPlan implements OperationStore.SaveCleanupPlan
+func (f *FileOperationStore) SaveCleanupPlan(ctx context.Context, plan *CleanupPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	planPath := filepath.Join(f.storeDir, "cleanup_plans", plan.ID+".json")
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cleanup plan: %w", err)
+	}
+
+	if err := writeFileAtomic(planPath, data); err != nil {
+		return fmt.Errorf("failed to write cleanup plan file: %w", err)
+	}
+
+	return nil
+}
+
+// GetCleanupPlan implements OperationStore.GetCleanupPlan
+func (f *FileOperationStore) GetCleanupPlan(ctx context.Context, id string) (*CleanupPlan, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	planPath := filepath.Join(f.storeDir, "cleanup_plans", id+".json")
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("cleanup %w: %s", ErrPlanNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read cleanup plan file: %w", err)
+	}
+
+	var plan CleanupPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cleanup plan: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// SaveRenamingPlan implements OperationStore.SaveRenamingPlan
+func (f *FileOperationStore) SaveRenamingPlan(ctx context.Context, plan *RenamingPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	planPath := filepath.Join(f.storeDir, "renaming_plans", plan.ID+".json")
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal renaming plan: %w", err)
+	}
+
+	if err := writeFileAtomic(planPath, data); err != nil {
+		return fmt.Errorf("failed to write renaming plan file: %w", err)
+	}
+
+	return nil
+}
+
+// GetRenamingPlan implements OperationStore.GetRenamingPlan
+func (f *FileOperationStore) GetRenamingPlan(ctx context.Context, id string) (*RenamingPlan, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	planPath := filepath.Join(f.storeDir, "renaming_plans", id+".json")
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("renaming %w: %s", ErrPlanNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read renaming plan file: %w", err)
+	}
+
+	var plan RenamingPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal renaming plan: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// SaveDeduplicationPlan implements OperationStore.SaveDeduplicationPlan
+func (f *FileOperationStore) SaveDeduplicationPlan(ctx context.Context, plan *DeduplicationPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	planPath := filepath.Join(f.storeDir, "dedup_plans", plan.ID+".json")
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal deduplication plan: %w", err)
+	}
+
+	if err := writeFileAtomic(planPath, data); err != nil {
+		return fmt.Errorf("failed to write deduplication plan file: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeduplicationPlan implements OperationStore.GetDeduplicationPlan
+func (f *FileOperationStore) GetDeduplicationPlan(ctx context.Context, id string) (*DeduplicationPlan, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	planPath := filepath.Join(f.storeDir, "dedup_plans", id+".json")
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("deduplication %w: %s", ErrPlanNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read deduplication plan file: %w", err)
+	}
+
+	var plan DeduplicationPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deduplication plan: %w", err)
+	}
+
+	return &plan, nil
+}
+
 // LogOperation implements OperationStore.LogOperation
-func (f *FileOperationStore) LogOperation(op *Operation) error {
+func (f *FileOperationStore) LogOperation(ctx context.Context, op *Operation) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -129,7 +355,7 @@ func (f *FileOperationStore) LogOperation(op *Operation) error {
 		return fmt.Errorf("failed to marshal operation: %w", err)
 	}
 
-	if err := os.WriteFile(opPath, data, 0644); err != nil {
+	if err := writeFileAtomic(opPath, data); err != nil {
 		return fmt.Errorf("failed to write operation file: %w", err)
 	}
 
@@ -137,7 +363,7 @@ func (f *FileOperationStore) LogOperation(op *Operation) error {
 }
 
 // GetPendingOperations implements OperationStore.GetPendingOperations
-func (f *FileOperationStore) GetPendingOperations() ([]*Operation, error) {
+func (f *FileOperationStore) GetPendingOperations(ctx context.Context) ([]*Operation, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
@@ -171,15 +397,18 @@ func (f *FileOperationStore) GetPendingOperations() ([]*Operation, error) {
 				continue
 			}
 
+			// A pending operation that cannot be read may be an interrupted
+			// move, which recovery cannot redo; it is skipped with a warning
 			opPath := filepath.Join(opsDir, entry.Name())
 			data, err := os.ReadFile(opPath)
 			if err != nil {
-				continue // Skip corrupted files
+				return nil, fmt.Errorf("failed to read operation %s: %w", opID, err)
 			}
 
 			var op Operation
 			if err := json.Unmarshal(data, &op); err != nil {
-				continue // Skip corrupted files
+				warnCorruptRecord("operation", opID, err)
+				continue
 			}
 
 			pending = append(pending, &op)
@@ -195,7 +424,7 @@ func (f *FileOperationStore) GetPendingOperations() ([]*Operation, error) {
 }
 
 // MarkOperationComplete implements OperationStore.MarkOperationComplete
-func (f *FileOperationStore) MarkOperationComplete(id string) error {
+func (f *FileOperationStore) MarkOperationComplete(ctx context.Context, id string) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
@@ -218,7 +447,7 @@ func (f *FileOperationStore) MarkOperationComplete(id string) error {
 		return fmt.Errorf("failed to marshal completion marker: %w", err)
 	}
 
-	if err := os.WriteFile(completionPath, data, 0644); err != nil {
+	if err := writeFileAtomic(completionPath, data); err != nil {
 		return fmt.Errorf("failed to write completion marker: %w", err)
 	}
 
@@ -226,46 +455,90 @@ func (f *FileOperationStore) MarkOperationComplete(id string) error {
 }
 
 // SaveExecutionLog implements OperationStore.SaveExecutionLog
-func (f *FileOperationStore) SaveExecutionLog(log *ExecutionLog) error {
+func (f *FileOperationStore) SaveExecutionLog(ctx context.Context, log *ExecutionLog) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	logPath := filepath.Join(f.storeDir, "execution_logs", log.PlanID+".json")
+	logPath := filepath.Join(f.storeDir, "execution_logs", log.Key()+".json")
 	data, err := json.MarshalIndent(log, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal execution log: %w", err)
 	}
 
-	if err := os.WriteFile(logPath, data, 0644); err != nil {
+	if err := writeFileAtomic(logPath, data); err != nil {
 		return fmt.Errorf("failed to write execution log file: %w", err)
 	}
 
 	return nil
 }
 
-// GetExecutionHistory implements OperationStore.GetExecutionHistory
-func (f *FileOperationStore) GetExecutionHistory() ([]*ExecutionLog, error) {
+// GetExecutionHistory implements OperationStore.GetExecutionHistory. Logs that
+// cannot be decoded are skipped with a warning.
+func (f *FileOperationStore) GetExecutionHistory(ctx context.Context) ([]*ExecutionLog, error) {
+	logs, corrupt, err := f.readExecutionLogs()
+	if err != nil {
+		return nil, err
+	}
+
+	for logID, err := range corrupt {
+		warnCorruptRecord("execution log", logID, err)
+	}
+	return logs, nil
+}
+
+// GetExecutionLogs implements OperationStore.GetExecutionLogs. A corrupt log of
+// the plan is reported rather than skipped: resuming or rolling back from an
+// older log would redo or keep the wrong moves. Corrupt logs of other plans,
+// told apart by their IDs, are skipped with a warning.
+func (f *FileOperationStore) GetExecutionLogs(ctx context.Context, planID string) ([]*ExecutionLog, error) {
+	history, corrupt, err := f.readExecutionLogs()
+	if err != nil {
+		return nil, err
+	}
+
+	for logID, err := range corrupt {
+		if strings.HasPrefix(logID, planID+"-exec-") {
+			return nil, corruptRecordError("execution log", logID, err)
+		}
+		warnCorruptRecord("execution log", logID, err)
+	}
+
+	logs := make([]*ExecutionLog, 0)
+	for _, log := range history {
+		if log.PlanID == planID {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// readExecutionLogs returns every execution log that can be decoded, newest
+// first, along with the decoding errors of the others by log ID
+func (f *FileOperationStore) readExecutionLogs() ([]*ExecutionLog, map[string]error, error) {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
 	logsDir := filepath.Join(f.storeDir, "execution_logs")
 	entries, err := os.ReadDir(logsDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read execution logs directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read execution logs directory: %w", err)
 	}
 
 	var logs []*ExecutionLog
+	corrupt := make(map[string]error)
 	for _, entry := range entries {
 		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			logID := strings.TrimSuffix(entry.Name(), ".json")
 			logPath := filepath.Join(logsDir, entry.Name())
 			data, err := os.ReadFile(logPath)
 			if err != nil {
-				continue // Skip corrupted files
+				return nil, nil, fmt.Errorf("failed to read execution log %s: %w", logID, err)
 			}
 
 			var log ExecutionLog
 			if err := json.Unmarshal(data, &log); err != nil {
-				continue // Skip corrupted files
+				corrupt[logID] = err
+				continue
 			}
 
 			logs = append(logs, &log)
@@ -273,11 +546,11 @@ func (f *FileOperationStore) GetExecutionHistory() ([]*ExecutionLog, error) {
 	}
 
 	// Sort by timestamp descending (newest first)
-	sort.Slice(logs, func(i, j int) bool {
+	sort.SliceStable(logs, func(i, j int) bool {
 		return logs[i].Timestamp.After(logs[j].Timestamp)
 	})
 
-	return logs, nil
+	return logs, corrupt, nil
 }
 
 // Clear removes all stored data (useful for testing)
@@ -285,8 +558,7 @@ func (f *FileOperationStore) Clear() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	subdirs := []string{"plans", "operations", "execution_logs"}
-	for _, subdir := range subdirs {
+	for _, subdir := range fileStoreSubdirs {
 		dirPath := filepath.Join(f.storeDir, subdir)
 		entries, err := os.ReadDir(dirPath)
 		if err != nil {
@@ -303,4 +575,204 @@ func (f *FileOperationStore) Clear() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// Verify implements VerifiableStore.Verify. It reads every record and reports
+// the ones that cannot be decoded, without changing anything.
+func (f *FileOperationStore) Verify() (*StoreReport, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.scan()
+}
+
+// Repair implements VerifiableStore.Repair. Corrupt records and leftover
+// temporary files are moved under the store's corrupt/ directory, so they stop
+// affecting the store but can still be inspected.
+func (f *FileOperationStore) Repair() (*StoreReport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	report, err := f.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Issues {
+		issue := &report.Issues[i]
+		rel, err := filepath.Rel(f.storeDir, issue.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quarantine %s: %w", issue.Path, err)
+		}
+
+		destination := filepath.Join(f.storeDir, corruptDir, rel)
+		if _, err := os.Stat(destination); err == nil {
+			destination = fmt.Sprintf("%s.%d", destination, time.Now().UnixNano())
+		}
+		if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create quarantine directory: %w", err)
+		}
+		if err := os.Rename(issue.Path, destination); err != nil {
+			return nil, fmt.Errorf("failed to quarantine %s: %w", issue.Path, err)
+		}
+		if err := syncDir(filepath.Dir(issue.Path)); err != nil {
+			return nil, err
+		}
+		issue.Quarantined = destination
+	}
+
+	return report, nil
+}
+
+// scan checks every record in the store; callers hold the lock
+func (f *FileOperationStore) scan() (*StoreReport, error) {
+	report := &StoreReport{Issues: make([]StoreIssue, 0)}
+
+	for _, subdir := range fileStoreSubdirs {
+		dirPath := filepath.Join(f.storeDir, subdir)
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s directory: %w", subdir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			filePath := filepath.Join(dirPath, entry.Name())
+
+			if strings.HasPrefix(entry.Name(), tempFilePrefix) {
+				report.Issues = append(report.Issues, StoreIssue{
+					Path:    filePath,
+					Kind:    subdir,
+					Problem: "incomplete write left behind by an interrupted save",
+				})
+				continue
+			}
+			if !strings.HasSuffix(entry.Name(), ".json") {
+				continue
+			}
+
+			report.Checked++
+			if problem := checkRecord(subdir, filePath, strings.TrimSuffix(entry.Name(), ".json")); problem != "" {
+				report.Issues = append(report.Issues, StoreIssue{Path: filePath, Kind: subdir, Problem: problem})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// corruptRecordError reports a record that could not be loaded, pointing to
+// the repair command that quarantines it
+func corruptRecordError(kind, id string, err error) error {
+	return fmt.Errorf("corrupt %s %s (run 'curator store repair' to quarantine it): %w", kind, id, err)
+}
+
+// warnCorruptRecord reports a record that is skipped because it could not be
+// loaded, pointing to the command that lists every such record
+func warnCorruptRecord(kind, id string, err error) {
+	log.Printf("Warning: skipping corrupt %s %s (run 'curator store verify' for details): %v", kind, id, err)
+}
+
+// checkRecord decodes one record and returns what is wrong with it, or "" if
+// it is intact. The ID inside the record must match its file name, since
+// lookups go by file name.
+func checkRecord(subdir, filePath, name string) string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("unreadable: %v", err)
+	}
+	if len(data) == 0 {
+		return "empty file"
+	}
+
+	var record interface{}
+	var recordID func() string
+	switch subdir {
+	case "plans":
+		plan := &ReorganizationPlan{}
+		record, recordID = plan, func() string { return plan.ID }
+	case "cleanup_plans":
+		plan := &CleanupPlan{}
+		record, recordID = plan, func() string { return plan.ID }
+	case "renaming_plans":
+		plan := &RenamingPlan{}
+		record, recordID = plan, func() string { return plan.ID }
+	case "dedup_plans":
+		plan := &DeduplicationPlan{}
+		record, recordID = plan, func() string { return plan.ID }
+	case "operations":
+		op := &Operation{}
+		record, recordID = op, func() string { return op.ID }
+	case "execution_logs":
+		log := &ExecutionLog{}
+		record, recordID = log, func() string { return log.Key() }
+	default:
+		return ""
+	}
+
+	if err := json.Unmarshal(data, record); err != nil {
+		return fmt.Sprintf("invalid JSON: %v", err)
+	}
+	if id := recordID(); id != name {
+		return fmt.Sprintf("record ID %q does not match file name", id)
+	}
+	return ""
+}
+
+// writeFileAtomic replaces path with data. The data is written to a temporary
+// file in the same directory, synced, and renamed over path; the directory is
+// then synced so the rename itself survives a crash.
+func writeFileAtomic(path string, data []byte) error {
+	return writeFileAtomicPerm(path, data, 0644)
+}
+
+// writeFileAtomicPerm is writeFileAtomic for a file with permissions perm
+func writeFileAtomicPerm(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, tempFilePrefix+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir flushes a directory's entries to disk
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open directory %s: %w", dir, err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync directory %s: %w", dir, err)
+	}
+	return nil
+}
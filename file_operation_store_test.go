@@ -0,0 +1,192 @@
+package curator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileOperationStore_WritesLeaveNoTemporaryFiles(t *testing.T) {
+	storeDir := t.TempDir()
+	store, err := NewFileOperationStore(storeDir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	plan := &ReorganizationPlan{ID: "plan-1", Timestamp: time.Now()}
+	for i := 0; i < 2; i++ {
+		if err := store.SavePlan(context.Background(), plan); err != nil {
+			t.Fatalf("Failed to save plan: %v", err)
+		}
+	}
+	if err := store.LogOperation(context.Background(), &Operation{ID: "op-1", Type: "move", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to log operation: %v", err)
+	}
+	if err := store.MarkOperationComplete(context.Background(), "op-1"); err != nil {
+		t.Fatalf("Failed to mark operation complete: %v", err)
+	}
+
+	for _, subdir := range []string{"plans", "operations"} {
+		entries, err := os.ReadDir(filepath.Join(storeDir, subdir))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", subdir, err)
+		}
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), tempFilePrefix) {
+				t.Errorf("Temporary file left behind: %s/%s", subdir, entry.Name())
+			}
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(storeDir, "plans", "plan-1.json"))
+	if err != nil {
+		t.Fatalf("Plan file missing: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected plan file mode 0644, got %v", info.Mode().Perm())
+	}
+}
+
+func TestFileOperationStore_VerifyAndRepair(t *testing.T) {
+	storeDir := t.TempDir()
+	store, err := NewFileOperationStore(storeDir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if err := store.SavePlan(context.Background(), &ReorganizationPlan{ID: "good", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+	if err := store.LogOperation(context.Background(), &Operation{ID: "op-good", Type: "move", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to log operation: %v", err)
+	}
+
+	// A truncated WAL entry, a record under the wrong name and a leftover temp file
+	truncated := filepath.Join(storeDir, "operations", "op-bad.json")
+	if err := os.WriteFile(truncated, []byte(`{"ID": "op-bad", "Ty`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	misnamed := filepath.Join(storeDir, "plans", "renamed.json")
+	if err := os.WriteFile(misnamed, []byte(`{"ID": "other"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	leftover := filepath.Join(storeDir, "execution_logs", tempFilePrefix+"plan.json-123")
+	if err := os.WriteFile(leftover, []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt WAL entries are skipped with a warning, so they do not block every apply
+	if pending, err := store.GetPendingOperations(context.Background()); err != nil || len(pending) != 1 || pending[0].ID != "op-good" {
+		t.Errorf("Expected only the intact pending operation, got %v, %v", pending, err)
+	}
+
+	report, err := store.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if report.Checked != 4 {
+		t.Errorf("Expected 4 records checked, got %d", report.Checked)
+	}
+	if len(report.Issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %+v", report.Issues)
+	}
+	if _, err := os.Stat(truncated); err != nil {
+		t.Error("Verify must not move records")
+	}
+
+	report, err = store.Repair()
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	for _, issue := range report.Issues {
+		if issue.Quarantined == "" {
+			t.Errorf("Expected %s to be quarantined", issue.Path)
+			continue
+		}
+		if _, err := os.Stat(issue.Quarantined); err != nil {
+			t.Errorf("Quarantined file missing: %v", err)
+		}
+		if _, err := os.Stat(issue.Path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be moved out of the store", issue.Path)
+		}
+	}
+	if want := filepath.Join(storeDir, corruptDir, "operations", "op-bad.json"); !fileExists(want) {
+		t.Errorf("Expected truncated operation at %s", want)
+	}
+
+	pending, err := store.GetPendingOperations(context.Background())
+	if err != nil {
+		t.Fatalf("Expected pending operations to load after repair: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != "op-good" {
+		t.Errorf("Expected only op-good to be pending, got %+v", pending)
+	}
+
+	report, err = store.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Expected a clean store after repair, got %+v", report.Issues)
+	}
+}
+
+func TestFileOperationStore_ReportsCorruptRecords(t *testing.T) {
+	ctx := context.Background()
+	storeDir := t.TempDir()
+	store, err := NewFileOperationStore(storeDir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if err := store.SavePlan(ctx, &ReorganizationPlan{ID: "reorg-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+	if err := store.SaveExecutionLog(ctx, &ExecutionLog{ID: "exec-1", PlanID: "reorg-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to save execution log: %v", err)
+	}
+
+	// A truncated latest attempt must not be passed over for an older one
+	if err := os.WriteFile(filepath.Join(storeDir, "execution_logs", "reorg-1-exec-2.json"), []byte(`{"id": "reorg-1-exec-2", "pla`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storeDir, "cleanup_plans", "cleanup-1.json"), []byte(`{"id": "cle`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storeDir, "operations", "reorg-1-exec-2-move-1.json"), []byte(`{"id": "reo`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.GetExecutionLogs(ctx, "reorg-1"); err == nil || !strings.Contains(err.Error(), "reorg-1-exec-2") || !strings.Contains(err.Error(), "store repair") {
+		t.Errorf("Expected execution logs to report the corrupt log, got %v", err)
+	}
+
+	// Everything else skips the corrupt records instead of failing
+	if logs, err := store.GetExecutionLogs(ctx, "reorg-2"); err != nil || len(logs) != 0 {
+		t.Errorf("Expected another plan's logs to be unaffected, got %v, %v", logs, err)
+	}
+	if history, err := store.GetExecutionHistory(ctx); err != nil || len(history) != 1 {
+		t.Errorf("Expected the execution history to skip the corrupt log, got %v, %v", history, err)
+	}
+	if plans, err := store.ListPlans(ctx); err != nil || len(plans) != 1 {
+		t.Errorf("Expected listing plans to skip the corrupt plan, got %v, %v", plans, err)
+	}
+	if pending, err := store.GetPendingOperations(ctx); err != nil || len(pending) != 0 {
+		t.Errorf("Expected pending operations to skip the corrupt operation, got %v, %v", pending, err)
+	}
+
+	if _, err := store.Repair(); err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	logs, err := store.GetExecutionLogs(ctx, "reorg-1")
+	if err != nil || len(logs) != 1 {
+		t.Errorf("Expected the intact log after repair, got %v, %v", logs, err)
+	}
+	plans, err := store.ListPlans(ctx)
+	if err != nil || len(plans) != 1 {
+		t.Errorf("Expected the intact plan after repair, got %v, %v", plans, err)
+	}
+}
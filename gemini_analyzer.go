@@ -4,21 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
 	"golang.org/x/time/rate"
+	"google.golang.org/api/option"
 )
 
-// Package-level verbose flag for debug logging
-var debugMode bool
+// Package-level writer for debug logging; nil disables it
+var debugOutput io.Writer
 
-// SetDebugMode enables or disables debug logging for AI operations
-func SetDebugMode(enabled bool) {
-	debugMode = enabled
+// SetDebugOutput sends debug logging for AI operations to w, or disables it
+// if w is nil
+func SetDebugOutput(w io.Writer) {
+	debugOutput = w
 }
 
 // GeminiConfig holds configuration for Gemini AI analyzer
@@ -122,26 +125,26 @@ func (g *GeminiAnalyzer) extractJSON(response string) (string, error) {
 }
 
 // AnalyzeForReorganization implements AIAnalyzer.AnalyzeForReorganization
-func (g *GeminiAnalyzer) AnalyzeForReorganization(files []FileInfo) (*ReorganizationPlan, error) {
+func (g *GeminiAnalyzer) AnalyzeForReorganization(ctx context.Context, files []FileInfo) (*ReorganizationPlan, error) {
 	prompt := g.buildReorganizationPrompt(files)
 	
-	if debugMode {
-		fmt.Println("\n📝 DEBUG: AI Prompt sent to Gemini:")
-		fmt.Println("=" + strings.Repeat("=", 50))
-		fmt.Println(prompt)
-		fmt.Println("=" + strings.Repeat("=", 50))
+	if debugOutput != nil {
+		fmt.Fprintln(debugOutput, "\n📝 DEBUG: AI Prompt sent to Gemini:")
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
+		fmt.Fprintln(debugOutput, prompt)
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
 	}
 	
-	response, err := g.callGemini(prompt)
+	response, err := g.callGemini(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Gemini for reorganization: %w", err)
 	}
 	
-	if debugMode {
-		fmt.Println("\n💬 DEBUG: AI Response from Gemini:")
-		fmt.Println("=" + strings.Repeat("=", 50))
-		fmt.Println(response)
-		fmt.Println("=" + strings.Repeat("=", 50))
+	if debugOutput != nil {
+		fmt.Fprintln(debugOutput, "\n💬 DEBUG: AI Response from Gemini:")
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
+		fmt.Fprintln(debugOutput, response)
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
 	}
 	
 	plan, err := g.parseReorganizationResponse(response)
@@ -152,42 +155,59 @@ func (g *GeminiAnalyzer) AnalyzeForReorganization(files []FileInfo) (*Reorganiza
 	return plan, nil
 }
 
-// AnalyzeForDuplicates implements AIAnalyzer.AnalyzeForDuplicates
-func (g *GeminiAnalyzer) AnalyzeForDuplicates(files []FileInfo) (*DuplicationReport, error) {
-	prompt := g.buildDuplicationPrompt(files)
+// AnalyzeForDuplicates implements AIAnalyzer.AnalyzeForDuplicates.
+// Duplicates are found locally by content hash; Gemini only ranks and explains
+// the groups it is shown, and the local result is returned unchanged if that fails.
+func (g *GeminiAnalyzer) AnalyzeForDuplicates(ctx context.Context, files []FileInfo) (*DuplicationReport, error) {
+	report := FindDuplicates(files)
+	if len(report.Duplicates) == 0 {
+		return report, nil
+	}
 	
-	if debugMode {
-		fmt.Println("\n📝 DEBUG: AI Prompt for duplicate analysis:")
-		fmt.Println("=" + strings.Repeat("=", 50))
-		fmt.Println(prompt)
-		fmt.Println("=" + strings.Repeat("=", 50))
+	prompt := g.buildDuplicationPrompt(report.Duplicates)
+	
+	if debugOutput != nil {
+		fmt.Fprintln(debugOutput, "\n📝 DEBUG: AI Prompt for duplicate analysis:")
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
+		fmt.Fprintln(debugOutput, prompt)
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
 	}
 	
-	response, err := g.callGemini(prompt)
+	response, err := g.callGemini(ctx, prompt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call Gemini for duplicates: %w", err)
+		// Ranking is optional, but an interrupt or timeout still stops the command
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to rank duplicates: %w", err)
+		}
+		if debugOutput != nil {
+			fmt.Fprintf(debugOutput, "⚠️  DEBUG: Gemini ranking failed, using unranked duplicates: %v\n", err)
+		}
+		return report, nil
 	}
 	
-	if debugMode {
-		fmt.Println("\n💬 DEBUG: AI Response for duplicate analysis:")
-		fmt.Println("=" + strings.Repeat("=", 50))
-		fmt.Println(response)
-		fmt.Println("=" + strings.Repeat("=", 50))
+	if debugOutput != nil {
+		fmt.Fprintln(debugOutput, "\n💬 DEBUG: AI Response for duplicate analysis:")
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
+		fmt.Fprintln(debugOutput, response)
+		fmt.Fprintln(debugOutput, "=" + strings.Repeat("=", 50))
 	}
 	
-	report, err := g.parseDuplicationResponse(response)
+	ranked, err := g.parseDuplicationResponse(response, report)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse duplication response: %w", err)
+		if debugOutput != nil {
+			fmt.Fprintf(debugOutput, "⚠️  DEBUG: Could not parse Gemini ranking, using unranked duplicates: %v\n", err)
+		}
+		return report, nil
 	}
 	
-	return report, nil
+	return ranked, nil
 }
 
 // AnalyzeForCleanup implements AIAnalyzer.AnalyzeForCleanup
-func (g *GeminiAnalyzer) AnalyzeForCleanup(files []FileInfo) (*CleanupPlan, error) {
+func (g *GeminiAnalyzer) AnalyzeForCleanup(ctx context.Context, files []FileInfo) (*CleanupPlan, error) {
 	prompt := g.buildCleanupPrompt(files)
 	
-	response, err := g.callGemini(prompt)
+	response, err := g.callGemini(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Gemini for cleanup: %w", err)
 	}
@@ -201,10 +221,10 @@ func (g *GeminiAnalyzer) AnalyzeForCleanup(files []FileInfo) (*CleanupPlan, erro
 }
 
 // AnalyzeForRenaming implements AIAnalyzer.AnalyzeForRenaming
-func (g *GeminiAnalyzer) AnalyzeForRenaming(files []FileInfo) (*RenamingPlan, error) {
+func (g *GeminiAnalyzer) AnalyzeForRenaming(ctx context.Context, files []FileInfo) (*RenamingPlan, error) {
 	prompt := g.buildRenamingPrompt(files)
 	
-	response, err := g.callGemini(prompt)
+	response, err := g.callGemini(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Gemini for renaming: %w", err)
 	}
@@ -218,17 +238,16 @@ func (g *GeminiAnalyzer) AnalyzeForRenaming(files []FileInfo) (*RenamingPlan, er
 }
 
 // callGemini makes a request to Gemini API with rate limiting and retries
-func (g *GeminiAnalyzer) callGemini(prompt string) (string, error) {
+func (g *GeminiAnalyzer) callGemini(ctx context.Context, prompt string) (string, error) {
 	var lastErr error
 	
 	for attempt := 0; attempt < g.config.MaxRetries; attempt++ {
 		// Wait for rate limiter
-		ctx := context.Background()
 		if err := g.limiter.Wait(ctx); err != nil {
 			return "", fmt.Errorf("rate limiter error: %w", err)
 		}
 		
-		response, err := g.makeRequest(prompt)
+		response, err := g.makeRequest(ctx, prompt)
 		if err == nil {
 			return response, nil
 		}
@@ -237,7 +256,11 @@ func (g *GeminiAnalyzer) callGemini(prompt string) (string, error) {
 		
 		// If this is the last attempt, don't wait
 		if attempt < g.config.MaxRetries-1 {
-			time.Sleep(g.config.RetryDelay)
+			select {
+			case <-ctx.Done():
+				return "", fmt.Errorf("gave up after %d attempts: %w", attempt+1, ctx.Err())
+			case <-time.After(g.config.RetryDelay):
+			}
 		}
 	}
 	
@@ -245,8 +268,8 @@ func (g *GeminiAnalyzer) callGemini(prompt string) (string, error) {
 }
 
 // makeRequest makes a single request to Gemini API
-func (g *GeminiAnalyzer) makeRequest(prompt string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), g.config.Timeout)
+func (g *GeminiAnalyzer) makeRequest(ctx context.Context, prompt string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.config.Timeout)
 	defer cancel()
 	
 	model := g.client.GenerativeModel(g.config.Model)
@@ -303,7 +326,6 @@ Create a reorganization plan that:
 
 Respond with a JSON object in exactly this format:
 {
-  "id": "reorg-<timestamp>",
   "moves": [
     {
       "id": "move-1",
@@ -331,39 +353,44 @@ Important:
 - Avoid moving files that are already well-organized`, filesInfo.String())
 }
 
-// buildDuplicationPrompt creates a prompt for duplicate detection
-func (g *GeminiAnalyzer) buildDuplicationPrompt(files []FileInfo) string {
-	var filesInfo strings.Builder
-	filesInfo.WriteString("Files to analyze for duplicates:\n")
+// maxDuplicateGroupsToRank limits how many groups are sent to Gemini for ranking;
+// the remaining (smaller) groups keep their local order
+const maxDuplicateGroupsToRank = 200
+
+// buildDuplicationPrompt creates a prompt asking Gemini to rank and explain
+// duplicate groups that were already found locally
+func (g *GeminiAnalyzer) buildDuplicationPrompt(groups []DuplicateGroup) string {
+	var groupsInfo strings.Builder
+	groupsInfo.WriteString("Groups of identical files (verified by content hash):\n")
 	
-	for _, file := range files {
-		if !file.IsDir() {
-			filesInfo.WriteString(fmt.Sprintf("FILE: %s (size: %d bytes, hash: %s)\n", 
-				file.Path(), file.Size(), file.Hash()))
+	for i, group := range groups {
+		if i >= maxDuplicateGroupsToRank {
+			break
+		}
+		groupsInfo.WriteString(fmt.Sprintf("GROUP %d (%d copies, %d bytes each):\n", i+1, len(group.Files), group.Size))
+		for _, file := range group.Files {
+			groupsInfo.WriteString(fmt.Sprintf("  %s\n", file))
 		}
 	}
 	
-	return fmt.Sprintf(`You are analyzing files for duplicates. Files with the same hash are identical.
+	return fmt.Sprintf(`You are reviewing duplicates that have already been detected. Every file in a
+group has exactly the same contents, so do not add, remove or regroup files.
 
 %s
 
-Identify duplicate files and respond with a JSON object in exactly this format:
+Rank the groups from most to least worth cleaning up (consider wasted space and
+how likely the copies are accidental), and briefly explain where each group of
+duplicates probably came from. Respond with a JSON object in exactly this format:
 {
-  "id": "dup-<timestamp>",
-  "duplicates": [
+  "groups": [
     {
-      "hash": "abc123",
-      "files": ["/path/to/file1", "/path/to/file2"],
-      "size": 1024
+      "index": 2,
+      "explanation": "Same photo downloaded twice into Downloads and copied to Desktop"
     }
-  ],
-  "summary": {
-    "totalDuplicates": 3,
-    "spaceSaved": 3072
-  }
+  ]
 }
 
-Only include groups where there are 2+ files with the same hash.`, filesInfo.String())
+Use the group numbers shown above as "index".`, groupsInfo.String())
 }
 
 // buildCleanupPrompt creates a prompt for cleanup analysis
@@ -394,7 +421,6 @@ Be conservative - only suggest files that are very likely to be safe to delete.
 
 Respond with a JSON object in exactly this format:
 {
-  "id": "cleanup-<timestamp>",
   "deletions": [
     {
       "id": "del-1",
@@ -417,7 +443,7 @@ func (g *GeminiAnalyzer) buildRenamingPrompt(files []FileInfo) string {
 	
 	for _, file := range files {
 		if !file.IsDir() {
-			filesInfo.WriteString(fmt.Sprintf("FILE: %s\n", file.Name()))
+			filesInfo.WriteString(fmt.Sprintf("FILE: %s\n", file.Path()))
 		}
 	}
 	
@@ -432,14 +458,15 @@ Suggest renames to improve filename consistency by:
 - Making names more descriptive where obvious
 
 Only suggest renames that genuinely improve the filename quality.
+Files are renamed in place: "oldPath" must be one of the paths listed above and
+"newName" must be a bare filename without any folder.
 
 Respond with a JSON object in exactly this format:
 {
-  "id": "rename-<timestamp>",
   "renames": [
     {
       "id": "rename-1",
-      "oldName": "My Document.pdf",
+      "oldPath": "/Documents/My Document.pdf",
       "newName": "my_document.pdf",
       "reason": "Standardize to lowercase with underscores"
     }
@@ -459,7 +486,6 @@ func (g *GeminiAnalyzer) parseReorganizationResponse(response string) (*Reorgani
 	}
 	
 	var result struct {
-		ID        string `json:"id"`
 		Moves     []struct {
 			ID          string `json:"id"`
 			Source      string `json:"source"`
@@ -508,7 +534,7 @@ func (g *GeminiAnalyzer) parseReorganizationResponse(response string) (*Reorgani
 	}
 	
 	plan := &ReorganizationPlan{
-		ID:        result.ID,
+		ID:        fmt.Sprintf("reorg-%d", time.Now().Unix()),
 		Timestamp: time.Now(),
 		Moves:     moves,
 		Summary: Summary{
@@ -524,50 +550,52 @@ func (g *GeminiAnalyzer) parseReorganizationResponse(response string) (*Reorgani
 	return plan, nil
 }
 
-// parseDuplicationResponse parses Gemini's response into a DuplicationReport
-func (g *GeminiAnalyzer) parseDuplicationResponse(response string) (*DuplicationReport, error) {
+// parseDuplicationResponse applies Gemini's ranking and explanations to a locally
+// computed report. Unknown or repeated indexes are ignored, and groups Gemini did
+// not mention keep their original order after the ranked ones.
+func (g *GeminiAnalyzer) parseDuplicationResponse(response string, report *DuplicationReport) (*DuplicationReport, error) {
 	jsonStr, err := g.extractJSON(response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract JSON: %w", err)
 	}
 	
 	var result struct {
-		ID         string `json:"id"`
-		Duplicates []struct {
-			Hash  string   `json:"hash"`
-			Files []string `json:"files"`
-			Size  int64    `json:"size"`
-		} `json:"duplicates"`
-		Summary struct {
-			TotalDuplicates int   `json:"totalDuplicates"`
-			SpaceSaved      int64 `json:"spaceSaved"`
-		} `json:"summary"`
+		Groups []struct {
+			Index       int    `json:"index"`
+			Explanation string `json:"explanation"`
+		} `json:"groups"`
 	}
 	
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
 	
-	duplicates := make([]DuplicateGroup, len(result.Duplicates))
-	for i, d := range result.Duplicates {
-		duplicates[i] = DuplicateGroup{
-			Hash:  d.Hash,
-			Files: d.Files,
-			Size:  d.Size,
+	ranked := make([]DuplicateGroup, 0, len(report.Duplicates))
+	used := make([]bool, len(report.Duplicates))
+	for _, r := range result.Groups {
+		i := r.Index - 1
+		if i < 0 || i >= len(report.Duplicates) || used[i] {
+			continue
 		}
+		used[i] = true
+		
+		group := report.Duplicates[i]
+		group.Explanation = r.Explanation
+		ranked = append(ranked, group)
 	}
 	
-	report := &DuplicationReport{
-		ID:         result.ID,
-		Timestamp:  time.Now(),
-		Duplicates: duplicates,
-		Summary: DuplicationSummary{
-			TotalDuplicates: result.Summary.TotalDuplicates,
-			SpaceSaved:      result.Summary.SpaceSaved,
-		},
+	for i, group := range report.Duplicates {
+		if !used[i] {
+			ranked = append(ranked, group)
+		}
 	}
 	
-	return report, nil
+	return &DuplicationReport{
+		ID:         report.ID,
+		Timestamp:  report.Timestamp,
+		Duplicates: ranked,
+		Summary:    report.Summary,
+	}, nil
 }
 
 // parseCleanupResponse parses Gemini's response into a CleanupPlan
@@ -578,7 +606,6 @@ func (g *GeminiAnalyzer) parseCleanupResponse(response string) (*CleanupPlan, er
 	}
 	
 	var result struct {
-		ID        string `json:"id"`
 		Deletions []struct {
 			ID     string `json:"id"`
 			Path   string `json:"path"`
@@ -606,7 +633,7 @@ func (g *GeminiAnalyzer) parseCleanupResponse(response string) (*CleanupPlan, er
 	}
 	
 	plan := &CleanupPlan{
-		ID:        result.ID,
+		ID:        fmt.Sprintf("cleanup-%d", time.Now().Unix()),
 		Timestamp: time.Now(),
 		Deletions: deletions,
 		Summary: CleanupSummary{
@@ -626,9 +653,9 @@ func (g *GeminiAnalyzer) parseRenamingResponse(response string) (*RenamingPlan,
 	}
 	
 	var result struct {
-		ID      string `json:"id"`
 		Renames []struct {
 			ID      string `json:"id"`
+			OldPath string `json:"oldPath"`
 			OldName string `json:"oldName"`
 			NewName string `json:"newName"`
 			Reason  string `json:"reason"`
@@ -645,16 +672,21 @@ func (g *GeminiAnalyzer) parseRenamingResponse(response string) (*RenamingPlan,
 	
 	renames := make([]Rename, len(result.Renames))
 	for i, r := range result.Renames {
+		oldName := r.OldName
+		if oldName == "" && r.OldPath != "" {
+			oldName = filepath.Base(r.OldPath)
+		}
 		renames[i] = Rename{
 			ID:      r.ID,
-			OldName: r.OldName,
+			OldPath: r.OldPath,
+			OldName: oldName,
 			NewName: r.NewName,
 			Reason:  r.Reason,
 		}
 	}
 	
 	plan := &RenamingPlan{
-		ID:        result.ID,
+		ID:        fmt.Sprintf("rename-%d", time.Now().Unix()),
 		Timestamp: time.Now(),
 		Renames:   renames,
 		Summary: RenamingSummary{
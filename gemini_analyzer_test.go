@@ -1,6 +1,8 @@
 package curator
 
 import (
+	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -212,13 +214,13 @@ func TestGeminiAnalyzer_Integration(t *testing.T) {
 	fs.AddFile("/document.pdf", []byte("test document"), "application/pdf")
 	fs.AddFile("/image.jpg", []byte("test image"), "image/jpeg")
 	
-	files, err := fs.List("/")
+	files, err := fs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
 	
 	// Test reorganization
-	plan, err := analyzer.AnalyzeForReorganization(files)
+	plan, err := analyzer.AnalyzeForReorganization(context.Background(), files)
 	if err != nil {
 		t.Fatalf("Failed to analyze for reorganization: %v", err)
 	}
@@ -258,7 +260,7 @@ func TestGeminiAnalyzer_PromptBuilding(t *testing.T) {
 	fs.AddFile("/document.pdf", []byte("test"), "application/pdf")
 	fs.AddFile("/image.jpg", []byte("test"), "image/jpeg")
 	
-	files, _ := fs.List("/")
+	files, _ := fs.List(context.Background(), "/")
 	
 	// Test reorganization prompt
 	prompt := analyzer.buildReorganizationPrompt(files)
@@ -275,7 +277,7 @@ func TestGeminiAnalyzer_PromptBuilding(t *testing.T) {
 	}
 	
 	// Test duplication prompt
-	prompt = analyzer.buildDuplicationPrompt(files)
+	prompt = analyzer.buildDuplicationPrompt(FindDuplicates(files).Duplicates)
 	if prompt == "" {
 		t.Error("Duplication prompt should not be empty")
 	}
@@ -283,6 +285,10 @@ func TestGeminiAnalyzer_PromptBuilding(t *testing.T) {
 	if !containsSubstring(prompt, "duplicates") {
 		t.Error("Duplication prompt should mention duplicates")
 	}
+
+	if !containsSubstring(prompt, "/document.pdf") {
+		t.Error("Duplication prompt should list the files of each group")
+	}
 	
 	// Test cleanup prompt
 	prompt = analyzer.buildCleanupPrompt(files)
@@ -310,4 +316,67 @@ func containsSubstring(str, substr string) bool {
 	return len(str) >= len(substr) && 
 		   len(substr) > 0 && 
 		   strings.Contains(str, substr)
-}
\ No newline at end of file
+}
+func TestGeminiAnalyzer_ParseDuplicationResponse_RanksLocalGroups(t *testing.T) {
+	config := DefaultGeminiConfig()
+	config.APIKey = "fake-key"
+
+	analyzer, err := NewGeminiAnalyzer(config)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	defer analyzer.Close()
+
+	local := &DuplicationReport{
+		ID: "dup-1",
+		Duplicates: []DuplicateGroup{
+			{Hash: "a", Files: []string{"/a1", "/a2"}, Size: 100},
+			{Hash: "b", Files: []string{"/b1", "/b2"}, Size: 50},
+			{Hash: "c", Files: []string{"/c1", "/c2"}, Size: 10},
+		},
+		Summary: DuplicationSummary{TotalDuplicates: 3, SpaceSaved: 160},
+	}
+
+	// Gemini may repeat or invent groups; neither may change the local result
+	response := `{"groups": [{"index": 3, "explanation": "Backup copies"}, {"index": 3}, {"index": 9}, {"index": 1, "explanation": "Downloaded twice"}]}`
+
+	report, err := analyzer.parseDuplicationResponse(response, local)
+	if err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	order := []string{report.Duplicates[0].Hash, report.Duplicates[1].Hash, report.Duplicates[2].Hash}
+	if len(report.Duplicates) != 3 || order[0] != "c" || order[1] != "a" || order[2] != "b" {
+		t.Errorf("Expected groups ranked c, a, b, got %v", order)
+	}
+	if report.Duplicates[0].Explanation != "Backup copies" {
+		t.Errorf("Expected explanation to be kept, got %q", report.Duplicates[0].Explanation)
+	}
+	if report.Summary != local.Summary {
+		t.Errorf("Expected summary to be unchanged, got %+v", report.Summary)
+	}
+}
+
+func TestGeminiAnalyzer_AnalyzeForDuplicates_StopsWhenCancelled(t *testing.T) {
+	config := DefaultGeminiConfig()
+	config.APIKey = "fake-key"
+
+	analyzer, err := NewGeminiAnalyzer(config)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	defer analyzer.Close()
+
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/a.txt", []byte("same"), "text/plain")
+	fs.AddFile("/b.txt", []byte("same"), "text/plain")
+	files, _ := fs.List(context.Background(), "/")
+
+	// A failed ranking falls back to the unranked report, but an interrupt
+	// or timeout must not
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := analyzer.AnalyzeForDuplicates(ctx, files); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation to be returned, got %v", err)
+	}
+}
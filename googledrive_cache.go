@@ -0,0 +1,152 @@
+package curator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// driveIDCache remembers the Drive IDs behind paths, so resolving a path the
+// filesystem has already seen takes no queries. Entries loaded from an earlier
+// run are checked against Drive the first time they are used, because files
+// may have moved in between.
+type driveIDCache struct {
+	mu      sync.Mutex
+	entries map[string]driveCacheEntry // By normalized path
+}
+
+// driveCacheEntry is what the cache knows about one path
+type driveCacheEntry struct {
+	ID       string `json:"id"`
+	Folder   bool   `json:"folder,omitempty"`
+	verified bool   // Seen in Drive during this run
+}
+
+// driveCacheFile is the saved form of a driveIDCache
+type driveCacheFile struct {
+	RootID string                     `json:"rootId"`
+	Paths  map[string]driveCacheEntry `json:"paths"`
+}
+
+func newDriveIDCache() *driveIDCache {
+	return &driveIDCache{entries: make(map[string]driveCacheEntry)}
+}
+
+// get returns the entry for a normalized path
+func (c *driveIDCache) get(p string) (driveCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[p]
+	return entry, ok
+}
+
+// put records the entry for a normalized path as seen in this run
+func (c *driveIDCache) put(p string, entry driveCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.verified = true
+	c.entries[p] = entry
+}
+
+// forget drops a normalized path and everything below it
+func (c *driveIDCache) forget(p string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forgetLocked(p)
+}
+
+func (c *driveIDCache) forgetLocked(p string) {
+	for key := range c.entries {
+		if isWithin(key, p) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// move re-keys a normalized path and everything below it after a move or rename
+func (c *driveIDCache) move(from, to string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	moved := make(map[string]driveCacheEntry)
+	for key, entry := range c.entries {
+		if isWithin(key, from) {
+			moved[to+strings.TrimPrefix(key, from)] = entry
+			delete(c.entries, key)
+		}
+	}
+	c.forgetLocked(to)
+	for key, entry := range moved {
+		c.entries[key] = entry
+	}
+}
+
+// remember records the children listed in the folder at dir under their
+// names from drivePathNames. A name shared by several children no longer
+// resolves on its own, so it is forgotten.
+func (c *driveIDCache) remember(dir string, children []*drive.File, names map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, file := range children {
+		if names[file.Id] != file.Name {
+			c.forgetLocked(normalizePlanPath(path.Join(dir, file.Name)))
+		}
+	}
+	for _, file := range children {
+		key := normalizePlanPath(path.Join(dir, names[file.Id]))
+		c.entries[key] = driveCacheEntry{ID: file.Id, Folder: file.MimeType == driveFolderMimeType, verified: true}
+	}
+}
+
+// load reads a cache saved for the same root. A missing file leaves the cache empty.
+func (c *driveIDCache) load(file, rootID string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read Drive ID cache: %w", err)
+	}
+
+	var saved driveCacheFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse Drive ID cache %s: %w", file, err)
+	}
+	if saved.RootID != rootID {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range saved.Paths {
+		if _, ok := c.entries[key]; !ok && entry.ID != "" {
+			c.entries[key] = driveCacheEntry{ID: entry.ID, Folder: entry.Folder}
+		}
+	}
+	return nil
+}
+
+// save writes the cache for the next run, replacing the file in one step.
+// Paths in the Drive can be private, so only the user may read it.
+func (c *driveIDCache) save(file, rootID string) error {
+	c.mu.Lock()
+	data, err := json.Marshal(driveCacheFile{RootID: rootID, Paths: c.entries})
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal Drive ID cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create Drive ID cache directory: %w", err)
+	}
+	if err := writeFileAtomicPerm(file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write Drive ID cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package curator
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newCachedDriveTree fills a fake Drive with /Docs/a.txt, /Docs/Work/b.txt and
+// /d.txt and returns the ID of a.txt
+func newCachedDriveTree(fake *fakeDrive) string {
+	docs := fake.add(fakeDriveRootID, "Docs", nil)
+	a := fake.add(docs, "a.txt", []byte("a"))
+	work := fake.add(docs, "Work", nil)
+	fake.add(work, "b.txt", []byte("b"))
+	fake.add(fakeDriveRootID, "d.txt", []byte("d"))
+	return a
+}
+
+func TestGoogleDriveFileSystem_IDCache(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+	newCachedDriveTree(fake)
+
+	// Listing remembers the IDs of everything listed
+	if _, err := gfs.List(ctx, "/"); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, err := gfs.List(ctx, "/Docs"); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	lists := fake.count("list")
+	if exists, err := gfs.Exists(ctx, "/Docs/a.txt"); err != nil || !exists {
+		t.Fatalf("Expected /Docs/a.txt to exist, got %v, %v", exists, err)
+	}
+	if _, err := gfs.List(ctx, "/Docs/Work"); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if got := fake.count("list") - lists; got != 1 {
+		t.Errorf("Expected only the listing of /Docs/Work to query Drive, got %d queries", got)
+	}
+
+	// Creating and moving keep the cache in step, including below a moved folder
+	if err := gfs.CreateFolder(ctx, "/Archive"); err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	if err := gfs.Move(ctx, "/Docs", "/Archive/Docs"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	lists = fake.count("list")
+	reader, err := gfs.Read(ctx, "/Archive/Docs/Work/b.txt")
+	if err != nil {
+		t.Fatalf("Read after the move failed: %v", err)
+	}
+	content, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(content) != "b" {
+		t.Errorf("Expected content b, got %q", content)
+	}
+	if got := fake.count("list") - lists; got != 0 {
+		t.Errorf("Expected the moved paths to come from the cache, got %d queries", got)
+	}
+	if exists, _ := gfs.Exists(ctx, "/Docs/a.txt"); exists {
+		t.Error("Expected the old path to be gone after the move")
+	}
+
+	// Deleting forgets the path
+	if err := gfs.Delete(ctx, "/Archive/Docs/a.txt"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if exists, _ := gfs.Exists(ctx, "/Archive/Docs/a.txt"); exists {
+		t.Error("Expected a deleted file not to exist")
+	}
+}
+
+func TestGoogleDriveFileSystem_IDCacheForgetsMissingFiles(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+	a := newCachedDriveTree(fake)
+
+	if _, err := gfs.List(ctx, "/Docs"); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	// Deleted for good from another client
+	delete(fake.files, a)
+
+	if _, err := gfs.Read(ctx, "/Docs/a.txt"); err == nil {
+		t.Fatal("Expected reading a deleted file to fail")
+	}
+	lists := fake.count("list")
+	if exists, err := gfs.Exists(ctx, "/Docs/a.txt"); err != nil || exists {
+		t.Errorf("Expected the deleted file not to exist, got %v, %v", exists, err)
+	}
+	if fake.count("list") == lists {
+		t.Error("Expected the path to be looked up again after Drive reported it missing")
+	}
+}
+
+func TestGoogleDriveFileSystem_IDCachePersists(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultGoogleDriveConfig()
+	config.IDCacheFile = filepath.Join(t.TempDir(), "drive_ids.json")
+	gfs, fake := newFakeDriveFileSystem(t, config)
+	a := newCachedDriveTree(fake)
+
+	if _, err := gfs.List(ctx, "/Docs"); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if err := gfs.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(config.IDCacheFile))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the cache file to be left, got %d files", len(entries))
+	}
+	if info, err := os.Stat(config.IDCacheFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the cache to be readable by its owner only, got %v, %v", info, err)
+	}
+
+	// The next run checks saved IDs by fetching them instead of searching each folder
+	next := fake.fileSystem(t, config)
+	lists := fake.count("list")
+	if exists, err := next.Exists(ctx, "/Docs/a.txt"); err != nil || !exists {
+		t.Fatalf("Expected /Docs/a.txt to exist, got %v, %v", exists, err)
+	}
+	if got := fake.count("list") - lists; got != 0 {
+		t.Errorf("Expected saved IDs to be used, got %d queries", got)
+	}
+
+	// A saved ID whose file was renamed in between is not trusted
+	fake.files[a].Name = "renamed.txt"
+	later := fake.fileSystem(t, config)
+	if exists, err := later.Exists(ctx, "/Docs/a.txt"); err != nil || exists {
+		t.Errorf("Expected the renamed file not to be found at its old path, got %v, %v", exists, err)
+	}
+	if exists, err := later.Exists(ctx, "/Docs/renamed.txt"); err != nil || !exists {
+		t.Errorf("Expected the renamed file at its new path, got %v, %v", exists, err)
+	}
+}
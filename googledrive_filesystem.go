@@ -3,21 +3,52 @@ package curator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 )
 
+const (
+	// DefaultDrivePageSize is the number of files asked for per page of a listing, the most Drive allows
+	DefaultDrivePageSize = 1000
+
+	// driveFolderMimeType is the MIME type Drive gives folders
+	driveFolderMimeType = "application/vnd.google-apps.folder"
+
+	// driveFileFields are the fields read for every listed file
+	driveFileFields = "id, name, mimeType, size, modifiedTime, md5Checksum, parents"
+
+	// driveIDSeparator joins a name and the start of the file's ID in the paths
+	// of files that share their name with a sibling, e.g. report~1AbCdEfG.pdf
+	driveIDSeparator = "~"
+
+	// driveShortIDLength is the shortest ID prefix used in such paths
+	driveShortIDLength = 8
+)
+
+var (
+	// errDrivePathNotFound is returned when no file in Drive has the given path
+	errDrivePathNotFound = errors.New("path not found")
+
+	// errDriveAmbiguousPath is returned when several siblings have the name in a path
+	errDriveAmbiguousPath = errors.New("ambiguous path")
+)
+
 // GoogleDriveConfig holds configuration for Google Drive filesystem
 type GoogleDriveConfig struct {
 	// OAuth2CredentialsFile is the path to the OAuth2 client credentials JSON file
@@ -28,12 +59,32 @@ type GoogleDriveConfig struct {
 	RootFolderID string
 	// ApplicationName is the name used to identify this application
 	ApplicationName string
+	// PageSize is the number of files asked for per page when listing (1-1000, defaults to DefaultDrivePageSize)
+	PageSize int64
+	// BulkTraversal scans by paging through every file in the Drive and building
+	// the tree locally, instead of listing one folder at a time (optional)
+	BulkTraversal bool
+	// IDCacheFile saves the path to ID cache between runs, so later runs skip most lookups (optional)
+	IDCacheFile string
+	// SharedDrive is the ID or name of a shared drive to operate in (optional, defaults to My Drive).
+	// RootFolderID, when set, must be a folder in it.
+	SharedDrive string
+	// RateLimit is the most Drive API requests sent per second (0 for no limit)
+	RateLimit float64
+	// MaxRetries is how often a request Drive turned away for coming too fast is sent again
+	MaxRetries int
+	// RetryDelay is the first wait before a retry when Drive does not say how long to wait; it doubles each time
+	RetryDelay time.Duration
 }
 
 // DefaultGoogleDriveConfig returns default configuration for Google Drive
 func DefaultGoogleDriveConfig() *GoogleDriveConfig {
 	return &GoogleDriveConfig{
 		ApplicationName: "Curator File Organizer",
+		PageSize:        DefaultDrivePageSize,
+		RateLimit:       10,
+		MaxRetries:      5,
+		RetryDelay:      time.Second,
 	}
 }
 
@@ -42,7 +93,13 @@ type GoogleDriveFileSystem struct {
 	config  *GoogleDriveConfig
 	service *drive.Service
 	rootID  string // Actual root folder ID to use
+	driveID string // Shared drive the root is in, empty for My Drive
 	utils   *FileUtilities
+	cache   *driveIDCache // IDs of the paths seen so far
+
+	// folderLocks holds a *sync.Mutex per folder path, so concurrent calls
+	// create a missing folder only once
+	folderLocks sync.Map
 }
 
 // OAuth2TokenInfo represents the stored OAuth2 tokens
@@ -208,12 +265,12 @@ func (tm *OAuth2TokenManager) performOAuth2Flow(ctx context.Context) (*oauth2.To
 
 	// Generate authorization URL and open browser
 	authURL := tm.config.AuthCodeURL("state", oauth2.AccessTypeOffline)
-	fmt.Printf("\n🔐 Opening browser for Google Drive authorization...\n")
-	fmt.Printf("If browser doesn't open automatically, visit:\n%s\n\n", authURL)
+	fmt.Fprintf(os.Stderr, "\n🔐 Opening browser for Google Drive authorization...\n")
+	fmt.Fprintf(os.Stderr, "If browser doesn't open automatically, visit:\n%s\n\n", authURL)
 	
 	// Try to open browser automatically
 	if err := openBrowser(authURL); err != nil {
-		fmt.Printf("Failed to open browser automatically: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to open browser automatically: %v\n", err)
 	}
 
 	// Wait for authorization code or error
@@ -243,7 +300,7 @@ func (tm *OAuth2TokenManager) performOAuth2Flow(ctx context.Context) (*oauth2.To
 		return nil, fmt.Errorf("failed to save OAuth2 token: %w", err)
 	}
 
-	fmt.Printf("✅ Google Drive authorization successful!\n\n")
+	fmt.Fprintf(os.Stderr, "✅ Google Drive authorization successful!\n\n")
 	return token, nil
 }
 
@@ -316,33 +373,101 @@ func NewGoogleDriveFileSystem(config *GoogleDriveConfig) (*GoogleDriveFileSystem
 		return nil, fmt.Errorf("failed to get OAuth2 token: %w", err)
 	}
 
-	// Create Drive service with OAuth2 token
-	service, err := drive.NewService(ctx, option.WithTokenSource(
-		tokenManager.config.TokenSource(ctx, token),
-	))
+	// Create Drive service with OAuth2 token, pacing and retrying its requests
+	httpClient := oauth2.NewClient(ctx, tokenManager.config.TokenSource(ctx, token))
+	httpClient.Transport = newDriveTransport(config, httpClient.Transport)
+	service, err := drive.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Drive service: %w", err)
 	}
 
-	// Determine root folder ID  
+	return newGoogleDriveFileSystem(ctx, config, service)
+}
+
+// newGoogleDriveFileSystem wraps an authenticated Drive service, finding the
+// root folder and the shared drive it is in
+func newGoogleDriveFileSystem(ctx context.Context, config *GoogleDriveConfig, service *drive.Service) (*GoogleDriveFileSystem, error) {
+	gfs := &GoogleDriveFileSystem{
+		config:  config,
+		service: service,
+		utils:   NewFileUtilities(),
+		cache:   newDriveIDCache(),
+	}
+
+	// Determine root folder ID
 	rootID := config.RootFolderID
+	if config.SharedDrive != "" {
+		driveID, err := gfs.findSharedDrive(ctx, config.SharedDrive)
+		if err != nil {
+			return nil, err
+		}
+		gfs.driveID = driveID
+		if rootID == "" {
+			// The top folder of a shared drive has the drive's ID
+			rootID = driveID
+		}
+	}
 	if rootID == "" {
 		// Use user's root folder (entire Drive)
 		rootID = "root"
 	}
 
-	// Verify we can access the root folder
-	_, err = service.Files.Get(rootID).Do()
+	// Verify we can access the root folder, and use its real ID instead of the "root" alias
+	root, err := service.Files.Get(rootID).Fields("id, driveId").SupportsAllDrives(true).Context(ctx).Do()
 	if err != nil {
 		return nil, fmt.Errorf("failed to access root folder %s: %w", rootID, err)
 	}
+	if gfs.driveID != "" && root.DriveId != gfs.driveID {
+		return nil, fmt.Errorf("root folder %s is not in shared drive %s", rootID, config.SharedDrive)
+	}
+	gfs.rootID = root.Id
+	gfs.driveID = root.DriveId
 
-	return &GoogleDriveFileSystem{
-		config:  config,
-		service: service,
-		rootID:  rootID,
-		utils:   NewFileUtilities(),
-	}, nil
+	if config.IDCacheFile != "" {
+		if err := gfs.cache.load(config.IDCacheFile, gfs.rootID); err != nil {
+			return nil, err
+		}
+	}
+
+	return gfs, nil
+}
+
+// findSharedDrive returns the ID of the shared drive with the given ID or name
+func (gfs *GoogleDriveFileSystem) findSharedDrive(ctx context.Context, idOrName string) (string, error) {
+	sharedDrive, err := gfs.service.Drives.Get(idOrName).Fields("id").Context(ctx).Do()
+	if err == nil {
+		return sharedDrive.Id, nil
+	}
+	if !isDriveNotFound(err) {
+		return "", fmt.Errorf("failed to get shared drive %s: %w", idOrName, err)
+	}
+
+	// Not an ID, so look it up by name
+	var matches []*drive.Drive
+	query := fmt.Sprintf("name='%s'", strings.ReplaceAll(idOrName, "'", "\\'"))
+	err = gfs.service.Drives.List().Q(query).Fields("nextPageToken, drives(id, name)").Pages(ctx, func(page *drive.DriveList) error {
+		matches = append(matches, page.Drives...)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for shared drive %s: %w", idOrName, err)
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("shared drive not found: %s", idOrName)
+	}
+	if len(matches) > 1 {
+		return "", fmt.Errorf("ambiguous shared drive - %d drives named %s, use its ID instead", len(matches), idOrName)
+	}
+	return matches[0].Id, nil
+}
+
+// Close saves the path to ID cache for the next run when IDCacheFile is set
+func (gfs *GoogleDriveFileSystem) Close() error {
+	if gfs.config.IDCacheFile == "" {
+		return nil
+	}
+	return gfs.cache.save(gfs.config.IDCacheFile, gfs.rootID)
 }
 
 // GetRootFolderID returns the root folder ID
@@ -350,145 +475,438 @@ func (gfs *GoogleDriveFileSystem) GetRootFolderID() string {
 	return gfs.rootID
 }
 
+// pageSize returns the configured page size, or the default when it is out of range
+func (gfs *GoogleDriveFileSystem) pageSize() int64 {
+	if gfs.config.PageSize < 1 || gfs.config.PageSize > DefaultDrivePageSize {
+		return DefaultDrivePageSize
+	}
+	return gfs.config.PageSize
+}
+
+// listFiles runs a file query and follows its pages to the end, so large
+// folders are never cut short
+func (gfs *GoogleDriveFileSystem) listFiles(ctx context.Context, query, fields string) ([]*drive.File, error) {
+	call := gfs.service.Files.List().
+		Q(query).
+		PageSize(gfs.pageSize()).
+		Fields(googleapi.Field("nextPageToken, files(" + fields + ")")).
+		SupportsAllDrives(true).
+		IncludeItemsFromAllDrives(true)
+	if gfs.driveID != "" {
+		// Search only the shared drive instead of everything the user can see
+		call = call.Corpora("drive").DriveId(gfs.driveID)
+	}
+
+	var files []*drive.File
+	err := call.Pages(ctx, func(page *drive.FileList) error {
+		files = append(files, page.Files...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
 // resolvePath converts a path to a Google Drive file ID
 // Paths are in format: /folder1/folder2/file.txt
-func (gfs *GoogleDriveFileSystem) resolvePath(path string) (string, error) {
-	// Clean and normalize the path
-	path = filepath.Clean(path)
-	path = strings.TrimPrefix(path, "/")
-	
-	// If empty path, return root
-	if path == "" || path == "." {
-		return gfs.rootID, nil
+func (gfs *GoogleDriveFileSystem) resolvePath(ctx context.Context, path string) (string, error) {
+	entry, err := gfs.resolve(ctx, path)
+	return entry.ID, err
+}
+
+// pathToID is a helper that returns both the file ID and any error
+func (gfs *GoogleDriveFileSystem) pathToID(ctx context.Context, path string) (string, error) {
+	return gfs.resolvePath(ctx, path)
+}
+
+// resolve finds the cache entry for a path. Only the components below the
+// deepest folder already in the cache are looked up, one query each.
+func (gfs *GoogleDriveFileSystem) resolve(ctx context.Context, p string) (driveCacheEntry, error) {
+	key := normalizePlanPath(p)
+	if key == "/" {
+		return driveCacheEntry{ID: gfs.rootID, Folder: true, verified: true}, nil
 	}
-	
-	// Split path into components
-	parts := strings.Split(path, "/")
-	currentID := gfs.rootID
-	
-	// Traverse each path component
-	for _, part := range parts {
-		if part == "" {
-			continue
+
+	if entry, ok := gfs.cache.get(key); ok {
+		if entry.verified {
+			return entry, nil
 		}
-		
-		// Find child with this name in current folder
-		query := fmt.Sprintf("name='%s' and '%s' in parents and trashed=false", 
-			strings.ReplaceAll(part, "'", "\\'"), currentID)
-		
-		fileList, err := gfs.service.Files.List().Q(query).Fields("files(id, name, mimeType)").Do()
+		valid, err := gfs.verify(ctx, key, entry)
 		if err != nil {
-			return "", fmt.Errorf("failed to search for %s: %w", part, err)
+			return driveCacheEntry{}, err
 		}
-		
-		if len(fileList.Files) == 0 {
-			return "", fmt.Errorf("path not found: %s", path)
+		if valid {
+			entry.verified = true
+			return entry, nil
 		}
-		
-		if len(fileList.Files) > 1 {
-			return "", fmt.Errorf("ambiguous path - multiple files named %s", part)
+	}
+
+	parent, err := gfs.resolve(ctx, path.Dir(key))
+	if err != nil {
+		if errors.Is(err, errDrivePathNotFound) {
+			return driveCacheEntry{}, fmt.Errorf("%w: %s", errDrivePathNotFound, strings.TrimPrefix(key, "/"))
 		}
-		
-		currentID = fileList.Files[0].Id
+		return driveCacheEntry{}, err
 	}
-	
-	return currentID, nil
+
+	// Find child with this name in its folder
+	name := path.Base(key)
+	matches, err := gfs.findChildren(ctx, parent.ID, name)
+	if err != nil {
+		return driveCacheEntry{}, err
+	}
+
+	// Siblings that share a name are told apart by the start of their ID
+	if realName, idPrefix, ok := parseDrivePathName(name); ok && len(matches) == 0 {
+		candidates, err := gfs.findChildren(ctx, parent.ID, realName)
+		if err != nil {
+			return driveCacheEntry{}, err
+		}
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate.Id, idPrefix) {
+				matches = append(matches, candidate)
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return driveCacheEntry{}, fmt.Errorf("%w: %s", errDrivePathNotFound, strings.TrimPrefix(key, "/"))
+	}
+
+	if len(matches) > 1 {
+		var names []string
+		pathNames := drivePathNames(matches)
+		for _, match := range matches {
+			names = append(names, pathNames[match.Id])
+		}
+		return driveCacheEntry{}, fmt.Errorf("%w - multiple files named %s, use one of %s",
+			errDriveAmbiguousPath, name, strings.Join(names, ", "))
+	}
+
+	entry := driveCacheEntry{ID: matches[0].Id, Folder: matches[0].MimeType == driveFolderMimeType}
+	gfs.cache.put(key, entry)
+	return entry, nil
 }
 
-// pathToID is a helper that returns both the file ID and any error
-func (gfs *GoogleDriveFileSystem) pathToID(path string) (string, error) {
-	return gfs.resolvePath(path)
+// findChildren returns the files with a name in a folder
+func (gfs *GoogleDriveFileSystem) findChildren(ctx context.Context, folderID, name string) ([]*drive.File, error) {
+	query := fmt.Sprintf("name='%s' and '%s' in parents and trashed=false",
+		strings.ReplaceAll(name, "'", "\\'"), folderID)
+
+	matches, err := gfs.listFiles(ctx, query, "id, name, mimeType")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %s: %w", name, err)
+	}
+	return matches, nil
+}
+
+// drivePathNames returns the name each file in a folder has in paths, by ID.
+// Files that share their name with a sibling get the shortest prefix of their
+// ID that tells them apart added before the extension.
+func drivePathNames(children []*drive.File) map[string]string {
+	byName := make(map[string][]*drive.File)
+	for _, file := range children {
+		byName[file.Name] = append(byName[file.Name], file)
+	}
+
+	names := make(map[string]string, len(children))
+	for name, siblings := range byName {
+		if len(siblings) == 1 {
+			names[siblings[0].Id] = name
+			continue
+		}
+
+		length := driveShortIDLength
+		for !uniquePrefixes(siblings, length) {
+			length++
+		}
+		ext := path.Ext(name)
+		for _, file := range siblings {
+			short := file.Id
+			if len(short) > length {
+				short = short[:length]
+			}
+			names[file.Id] = strings.TrimSuffix(name, ext) + driveIDSeparator + short + ext
+		}
+	}
+	return names
+}
+
+// uniquePrefixes reports whether the IDs of files differ in their first length
+// characters. IDs shorter than that are used whole, so they always do.
+func uniquePrefixes(files []*drive.File, length int) bool {
+	seen := make(map[string]bool)
+	for _, file := range files {
+		prefix := file.Id
+		if len(prefix) > length {
+			prefix = prefix[:length]
+		}
+		if seen[prefix] {
+			return false
+		}
+		seen[prefix] = true
+	}
+	return true
+}
+
+// parseDrivePathName splits a name from drivePathNames into the file's real
+// name and the start of its ID
+func parseDrivePathName(name string) (realName, idPrefix string, ok bool) {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	i := strings.LastIndex(stem, driveIDSeparator)
+	if i < 0 || i == len(stem)-len(driveIDSeparator) {
+		return "", "", false
+	}
+	return stem[:i] + ext, stem[i+len(driveIDSeparator):], true
+}
+
+// verify checks an entry loaded from an earlier run against Drive. An entry
+// whose file was renamed, moved or trashed since is forgotten.
+func (gfs *GoogleDriveFileSystem) verify(ctx context.Context, key string, entry driveCacheEntry) (bool, error) {
+	file, err := gfs.service.Files.Get(entry.ID).Fields("name, mimeType, parents, trashed").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		if isDriveNotFound(err) {
+			gfs.cache.forget(key)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check cached ID of %s: %w", key, err)
+	}
+
+	parent, err := gfs.resolve(ctx, path.Dir(key))
+	if err != nil && !errors.Is(err, errDrivePathNotFound) {
+		return false, err
+	}
+	if err != nil || file.Trashed || file.Name != path.Base(key) || !slices.Contains(file.Parents, parent.ID) {
+		gfs.cache.forget(key)
+		return false, nil
+	}
+
+	gfs.cache.put(key, driveCacheEntry{ID: entry.ID, Folder: file.MimeType == driveFolderMimeType})
+	return true, nil
+}
+
+// forgetIfNotFound drops cached paths after Drive reports that an ID they
+// resolved to no longer exists
+func (gfs *GoogleDriveFileSystem) forgetIfNotFound(err error, paths ...string) {
+	if isDriveNotFound(err) {
+		for _, p := range paths {
+			gfs.cache.forget(normalizePlanPath(p))
+		}
+	}
+}
+
+// driveCrossDriveReasons are the error reasons Drive gives for moves it
+// refuses because they would cross a shared drive boundary
+var driveCrossDriveReasons = map[string]bool{
+	"teamDrivesFolderMoveInNotSupported":  true,
+	"teamDrivesParentLimit":               true,
+	"crossDomainMoveRestriction":          true,
+	"cannotMoveTrashedItemIntoTeamDrive":  true,
+	"cannotMoveTrashedItemOutOfTeamDrive": true,
+}
+
+// isDriveCrossDriveMove reports whether err is Drive refusing a move between drives
+func isDriveCrossDriveMove(err error) bool {
+	return hasDriveReason(err, driveCrossDriveReasons)
+}
+
+// driveStorageQuotaReasons are the error reasons Drive gives when the owner is out of storage
+var driveStorageQuotaReasons = map[string]bool{
+	"storageQuotaExceeded":       true,
+	"teamDriveFileLimitExceeded": true,
+}
+
+// checkStorageQuota returns err as a StorageQuotaError when Drive refused the
+// request for lack of storage, and unchanged otherwise
+func checkStorageQuota(err error) error {
+	if hasDriveReason(err, driveStorageQuotaReasons) {
+		return &StorageQuotaError{Err: err}
+	}
+	return err
+}
+
+// hasDriveReason reports whether err is a Drive API error with one of reasons
+func hasDriveReason(err error, reasons map[string]bool) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, item := range apiErr.Errors {
+		if reasons[item.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// isDriveNotFound reports whether err is Drive's answer for an unknown file ID
+func isDriveNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
 }
 
 // List implements FileSystem.List
-func (gfs *GoogleDriveFileSystem) List(path string) ([]FileInfo, error) {
-	folderID, err := gfs.pathToID(path)
+func (gfs *GoogleDriveFileSystem) List(ctx context.Context, path string) ([]FileInfo, error) {
+	folder, err := gfs.resolve(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 	
 	// Verify it's a folder
-	file, err := gfs.service.Files.Get(folderID).Fields("mimeType").Do()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get folder info: %w", err)
-	}
-	
-	if file.MimeType != "application/vnd.google-apps.folder" {
+	if !folder.Folder {
 		return nil, fmt.Errorf("path is not a folder: %s", path)
 	}
 	
 	// List files in folder
-	query := fmt.Sprintf("'%s' in parents and trashed=false", folderID)
-	fileList, err := gfs.service.Files.List().
-		Q(query).
-		Fields("files(id, name, mimeType, size, modifiedTime, md5Checksum, parents)").
-		Do()
+	query := fmt.Sprintf("'%s' in parents and trashed=false", folder.ID)
+	children, err := gfs.listFiles(ctx, query, driveFileFields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list files: %w", err)
 	}
+	names := drivePathNames(children)
+	gfs.cache.remember(path, children, names)
 	
 	var files []FileInfo
-	for _, file := range fileList.Files {
-		// Build the full path for this file
-		filePath := filepath.Join(path, file.Name)
-		if !strings.HasPrefix(filePath, "/") {
-			filePath = "/" + filePath
-		}
-		filePath = filepath.ToSlash(filePath)
-		
-		fileInfo := &googleDriveFileInfo{
-			id:       file.Id,
-			name:     file.Name,
-			path:     filePath,
-			isDir:    file.MimeType == "application/vnd.google-apps.folder",
-			size:     file.Size,
-			mimeType: file.MimeType,
-			hash:     file.Md5Checksum,
-			service:  gfs.service,
+	for _, file := range children {
+		files = append(files, gfs.newFileInfo(file, drivePath(path, names[file.Id])))
+	}
+	
+	return files, nil
+}
+
+// ListTree implements TreeLister. With BulkTraversal set it pages through
+// every file in the Drive and builds the tree below path from their parents,
+// which takes a few queries where walking the tree takes one per folder.
+func (gfs *GoogleDriveFileSystem) ListTree(ctx context.Context, path string) ([]FileInfo, bool, error) {
+	if !gfs.config.BulkTraversal {
+		return nil, false, nil
+	}
+
+	folderID, err := gfs.pathToID(ctx, path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Listed files name their parents by ID, never by the "root" alias
+	folder, err := gfs.service.Files.Get(folderID).Fields("id, mimeType").SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get folder info: %w", err)
+	}
+	if folder.MimeType != driveFolderMimeType {
+		return nil, false, fmt.Errorf("path is not a folder: %s", path)
+	}
+
+	all, err := gfs.listFiles(ctx, "trashed=false", driveFileFields)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	children := make(map[string][]*drive.File)
+	for _, file := range all {
+		for _, parent := range file.Parents {
+			children[parent] = append(children[parent], file)
 		}
-		
-		// Parse modification time
-		if file.ModifiedTime != "" {
-			if modTime, err := time.Parse(time.RFC3339, file.ModifiedTime); err == nil {
-				fileInfo.modTime = modTime
+	}
+	// Walk the tree the way a folder-by-folder scan would, each folder before its contents
+	var files []FileInfo
+	var walk func(id, dir string)
+	walk = func(id, dir string) {
+		names := drivePathNames(children[id])
+		gfs.cache.remember(dir, children[id], names)
+		for _, file := range children[id] {
+			info := gfs.newFileInfo(file, drivePath(dir, names[file.Id]))
+			files = append(files, info)
+			if info.isDir {
+				walk(file.Id, info.path)
 			}
 		}
-		
-		files = append(files, fileInfo)
 	}
-	
-	return files, nil
+	walk(folder.Id, path)
+
+	return files, true, nil
+}
+
+// newFileInfo describes a listed file found at path
+func (gfs *GoogleDriveFileSystem) newFileInfo(file *drive.File, path string) *googleDriveFileInfo {
+	fileInfo := &googleDriveFileInfo{
+		id:       file.Id,
+		name:     file.Name,
+		path:     path,
+		isDir:    file.MimeType == driveFolderMimeType,
+		size:     file.Size,
+		mimeType: file.MimeType,
+		hash:     file.Md5Checksum,
+		service:  gfs.service,
+	}
+
+	// Parse modification time
+	if file.ModifiedTime != "" {
+		if modTime, err := time.Parse(time.RFC3339, file.ModifiedTime); err == nil {
+			fileInfo.modTime = modTime
+		}
+	}
+
+	return fileInfo
+}
+
+// drivePath builds the full path of a file named name in the folder at dir
+func drivePath(dir, name string) string {
+	filePath := filepath.Join(dir, name)
+	if !strings.HasPrefix(filePath, "/") {
+		filePath = "/" + filePath
+	}
+	return filepath.ToSlash(filePath)
 }
 
 // Read implements FileSystem.Read
-func (gfs *GoogleDriveFileSystem) Read(path string) (io.ReadCloser, error) {
-	fileID, err := gfs.pathToID(path)
+func (gfs *GoogleDriveFileSystem) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	file, err := gfs.resolve(ctx, path)
 	if err != nil {
 		return nil, err
 	}
 	
 	// Verify it's not a folder
-	file, err := gfs.service.Files.Get(fileID).Fields("mimeType").Do()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file info: %w", err)
-	}
-	
-	if file.MimeType == "application/vnd.google-apps.folder" {
+	if file.Folder {
 		return nil, fmt.Errorf("cannot read directory: %s", path)
 	}
 	
 	// Download file content
-	resp, err := gfs.service.Files.Get(fileID).Download()
+	resp, err := gfs.service.Files.Get(file.ID).SupportsAllDrives(true).Context(ctx).Download()
 	if err != nil {
+		gfs.forgetIfNotFound(err, path)
 		return nil, fmt.Errorf("failed to download file %s: %w", path, err)
 	}
 	
 	return resp.Body, nil
 }
 
+// Stat implements FileStater, looking a path up without listing its folder
+func (gfs *GoogleDriveFileSystem) Stat(ctx context.Context, path string) (FileInfo, error) {
+	entry, err := gfs.resolve(ctx, path)
+	if err != nil {
+		if errors.Is(err, errDrivePathNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	file, err := gfs.service.Files.Get(entry.ID).Fields(driveFileFields).SupportsAllDrives(true).Context(ctx).Do()
+	if err != nil {
+		if isDriveNotFound(err) {
+			gfs.forgetIfNotFound(err, path)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	
+	return gfs.newFileInfo(file, normalizePlanPath(path)), nil
+}
+
 // Move implements FileSystem.Move
-func (gfs *GoogleDriveFileSystem) Move(source, destination string) error {
-	sourceID, err := gfs.pathToID(source)
+func (gfs *GoogleDriveFileSystem) Move(ctx context.Context, source, destination string) error {
+	sourceID, err := gfs.pathToID(ctx, source)
 	if err != nil {
 		return fmt.Errorf("invalid source path: %w", err)
 	}
@@ -497,17 +915,28 @@ func (gfs *GoogleDriveFileSystem) Move(source, destination string) error {
 	destDir := filepath.Dir(destination)
 	destName := filepath.Base(destination)
 	
-	destDirID, err := gfs.pathToID(destDir)
+	// A destination that keeps the source's disambiguated name keeps its real name
+	if realName, idPrefix, ok := parseDrivePathName(destName); ok && strings.HasPrefix(sourceID, idPrefix) {
+		destName = realName
+	}
+	
+	destDirID, err := gfs.pathToID(ctx, destDir)
 	if err != nil {
 		return fmt.Errorf("invalid destination directory: %w", err)
 	}
 	
 	// Get current file info to get current parents
-	file, err := gfs.service.Files.Get(sourceID).Fields("parents").Do()
+	file, err := gfs.service.Files.Get(sourceID).Fields("parents, driveId").SupportsAllDrives(true).Context(ctx).Do()
 	if err != nil {
+		gfs.forgetIfNotFound(err, source)
 		return fmt.Errorf("failed to get source file info: %w", err)
 	}
 	
+	// Drive cannot move files between drives the way it moves them between folders
+	if file.DriveId != gfs.driveID {
+		return &ConflictError{Message: fmt.Sprintf("cannot move %s to %s: it is in another drive, and moves between drives are not supported", source, destination)}
+	}
+	
 	// Update file: change name and parent
 	update := &drive.File{
 		Name: destName,
@@ -522,76 +951,102 @@ func (gfs *GoogleDriveFileSystem) Move(source, destination string) error {
 	_, err = gfs.service.Files.Update(sourceID, update).
 		AddParents(destDirID).
 		RemoveParents(strings.Join(removeParents, ",")).
+		SupportsAllDrives(true).
+		Context(ctx).
 		Do()
 	if err != nil {
-		return fmt.Errorf("failed to move %s to %s: %w", source, destination, err)
+		if isDriveCrossDriveMove(err) {
+			return &ConflictError{Message: fmt.Sprintf("cannot move %s to %s: moves between drives are not supported (%v)", source, destination, err)}
+		}
+		gfs.forgetIfNotFound(err, source, destDir)
+		return fmt.Errorf("failed to move %s to %s: %w", source, destination, checkStorageQuota(err))
 	}
+	gfs.cache.move(normalizePlanPath(source), normalizePlanPath(destination))
 	
 	return nil
 }
 
-// CreateFolder implements FileSystem.CreateFolder
-func (gfs *GoogleDriveFileSystem) CreateFolder(path string) error {
-	// Parse path
-	parentDir := filepath.Dir(path)
-	folderName := filepath.Base(path)
-	
-	parentID, err := gfs.pathToID(parentDir)
-	if err != nil {
-		return fmt.Errorf("invalid parent directory: %w", err)
+// CreateFolder implements FileSystem.CreateFolder. Like LocalFileSystem it
+// creates missing parents and succeeds when the folder already exists.
+func (gfs *GoogleDriveFileSystem) CreateFolder(ctx context.Context, folderPath string) error {
+	key := normalizePlanPath(folderPath)
+	if key == "/" {
+		return nil
 	}
-	
-	// Check if folder already exists
-	query := fmt.Sprintf("name='%s' and '%s' in parents and mimeType='application/vnd.google-apps.folder' and trashed=false", 
-		strings.ReplaceAll(folderName, "'", "\\'"), parentID)
-	
-	fileList, err := gfs.service.Files.List().Q(query).Do()
-	if err != nil {
+
+	// Drive allows siblings with the same name, so creating the same folder
+	// at the same time would make two of it. Parents are locked after their
+	// children, always up the tree, so this cannot deadlock.
+	lock, _ := gfs.folderLocks.LoadOrStore(key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	entry, err := gfs.resolve(ctx, key)
+	if err == nil {
+		if !entry.Folder {
+			return fmt.Errorf("failed to create folder %s: a file exists at that path", folderPath)
+		}
+		return nil
+	}
+	if !errors.Is(err, errDrivePathNotFound) {
 		return fmt.Errorf("failed to check if folder exists: %w", err)
 	}
-	
-	if len(fileList.Files) > 0 {
-		return fmt.Errorf("folder already exists: %s", path)
+
+	parentDir := path.Dir(key)
+	if err := gfs.CreateFolder(ctx, parentDir); err != nil {
+		return err
+	}
+	parentID, err := gfs.pathToID(ctx, parentDir)
+	if err != nil {
+		return fmt.Errorf("invalid parent directory: %w", err)
 	}
 	
 	// Create folder
 	folder := &drive.File{
-		Name:     folderName,
-		MimeType: "application/vnd.google-apps.folder",
+		Name:     path.Base(key),
+		MimeType: driveFolderMimeType,
 		Parents:  []string{parentID},
 	}
 	
-	_, err = gfs.service.Files.Create(folder).Do()
+	created, err := gfs.service.Files.Create(folder).Fields("id").SupportsAllDrives(true).Context(ctx).Do()
 	if err != nil {
-		return fmt.Errorf("failed to create folder %s: %w", path, err)
+		gfs.forgetIfNotFound(err, parentDir)
+		return fmt.Errorf("failed to create folder %s: %w", folderPath, checkStorageQuota(err))
 	}
+	gfs.cache.put(key, driveCacheEntry{ID: created.Id, Folder: true})
 	
 	return nil
 }
 
 // Delete implements FileSystem.Delete
-func (gfs *GoogleDriveFileSystem) Delete(path string) error {
-	fileID, err := gfs.pathToID(path)
+func (gfs *GoogleDriveFileSystem) Delete(ctx context.Context, path string) error {
+	fileID, err := gfs.pathToID(ctx, path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 	
 	// Move to trash instead of permanent delete for safety
-	_, err = gfs.service.Files.Update(fileID, &drive.File{Trashed: true}).Do()
+	_, err = gfs.service.Files.Update(fileID, &drive.File{Trashed: true}).SupportsAllDrives(true).Context(ctx).Do()
 	if err != nil {
+		gfs.forgetIfNotFound(err, path)
 		return fmt.Errorf("failed to delete %s: %w", path, err)
 	}
+	gfs.cache.forget(normalizePlanPath(path))
 	
 	return nil
 }
 
 // Exists implements FileSystem.Exists
-func (gfs *GoogleDriveFileSystem) Exists(path string) (bool, error) {
-	_, err := gfs.pathToID(path)
+func (gfs *GoogleDriveFileSystem) Exists(ctx context.Context, path string) (bool, error) {
+	_, err := gfs.pathToID(ctx, path)
 	if err != nil {
-		if strings.Contains(err.Error(), "path not found") {
+		if errors.Is(err, errDrivePathNotFound) {
 			return false, nil
 		}
+		if errors.Is(err, errDriveAmbiguousPath) {
+			// Several files are there
+			return true, nil
+		}
 		return false, err
 	}
 	return true, nil
@@ -652,7 +1107,7 @@ func (gdfi *googleDriveFileInfo) Hash() string {
 
 func (gdfi *googleDriveFileInfo) MimeType() string {
 	if gdfi.isDir {
-		return "application/vnd.google-apps.folder"
+		return driveFolderMimeType
 	}
 	return gdfi.mimeType
 }
\ No newline at end of file
@@ -1,12 +1,24 @@
 package curator
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
 )
 
 func TestDefaultGoogleDriveConfig(t *testing.T) {
@@ -67,6 +79,59 @@ func TestLoadGoogleDriveConfig(t *testing.T) {
 	if config.ApplicationName != "Test App" {
 		t.Errorf("Expected application name 'Test App', got '%s'", config.ApplicationName)
 	}
+	
+	if config.PageSize != DefaultDrivePageSize || config.BulkTraversal {
+		t.Errorf("Expected default listing settings, got page size %d, bulk %v", config.PageSize, config.BulkTraversal)
+	}
+	
+	t.Setenv("GOOGLE_DRIVE_PAGE_SIZE", "200")
+	t.Setenv("GOOGLE_DRIVE_BULK_TRAVERSAL", "true")
+	t.Setenv("GOOGLE_DRIVE_ID_CACHE_FILE", "/path/to/drive_ids.json")
+	t.Setenv("GOOGLE_DRIVE_SHARED_DRIVE", "Team")
+	config = loadGoogleDriveConfig()
+	if config.PageSize != 200 || !config.BulkTraversal {
+		t.Errorf("Expected page size 200 with bulk traversal, got %d, %v", config.PageSize, config.BulkTraversal)
+	}
+	if config.IDCacheFile != "/path/to/drive_ids.json" {
+		t.Errorf("Expected ID cache file '/path/to/drive_ids.json', got '%s'", config.IDCacheFile)
+	}
+	if config.SharedDrive != "Team" {
+		t.Errorf("Expected shared drive 'Team', got '%s'", config.SharedDrive)
+	}
+	if config.RateLimit != 10 || config.MaxRetries != 5 {
+		t.Errorf("Expected default rate limit 10 and 5 retries, got %v, %d", config.RateLimit, config.MaxRetries)
+	}
+
+	t.Setenv("GOOGLE_DRIVE_RATE_LIMIT", "2.5")
+	t.Setenv("GOOGLE_DRIVE_MAX_RETRIES", "0")
+	t.Setenv("GOOGLE_DRIVE_RETRY_DELAY", "250ms")
+	config = loadGoogleDriveConfig()
+	if config.RateLimit != 2.5 || config.MaxRetries != 0 || config.RetryDelay != 250*time.Millisecond {
+		t.Errorf("Expected rate limit 2.5 without retries and a 250ms delay, got %v, %d, %v", config.RateLimit, config.MaxRetries, config.RetryDelay)
+	}
+
+	// Invalid values keep the defaults, with a warning
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+	t.Setenv("GOOGLE_DRIVE_PAGE_SIZE", "5000")
+	t.Setenv("GOOGLE_DRIVE_BULK_TRAVERSAL", "sometimes")
+	t.Setenv("GOOGLE_DRIVE_RATE_LIMIT", "fast")
+	t.Setenv("GOOGLE_DRIVE_MAX_RETRIES", "-1")
+	t.Setenv("GOOGLE_DRIVE_RETRY_DELAY", "2")
+	invalid := loadGoogleDriveConfig()
+	if invalid.PageSize != DefaultDrivePageSize || invalid.BulkTraversal {
+		t.Errorf("Expected invalid listing settings to be ignored, got page size %d, bulk %v", invalid.PageSize, invalid.BulkTraversal)
+	}
+	defaults := DefaultGoogleDriveConfig()
+	if invalid.RateLimit != defaults.RateLimit || invalid.MaxRetries != defaults.MaxRetries || invalid.RetryDelay != defaults.RetryDelay {
+		t.Errorf("Expected invalid retry settings to be ignored, got %v, %d, %v", invalid.RateLimit, invalid.MaxRetries, invalid.RetryDelay)
+	}
+	for _, name := range []string{"GOOGLE_DRIVE_PAGE_SIZE", "GOOGLE_DRIVE_BULK_TRAVERSAL", "GOOGLE_DRIVE_RATE_LIMIT", "GOOGLE_DRIVE_MAX_RETRIES", "GOOGLE_DRIVE_RETRY_DELAY"} {
+		if !strings.Contains(logged.String(), "invalid "+name) {
+			t.Errorf("Expected a warning about %s, got %q", name, logged.String())
+		}
+	}
 }
 
 func TestNewGoogleDriveFileSystem_RequiresOAuth2Credentials(t *testing.T) {
@@ -108,7 +173,7 @@ func TestGoogleDriveFileSystem_Integration(t *testing.T) {
 	}
 	
 	// Test listing root folder
-	files, err := gfs.List("/")
+	files, err := gfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list root folder: %v", err)
 	}
@@ -116,7 +181,7 @@ func TestGoogleDriveFileSystem_Integration(t *testing.T) {
 	t.Logf("Found %d files in root folder", len(files))
 	
 	// Test path resolution
-	exists, err := gfs.Exists("/")
+	exists, err := gfs.Exists(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to check if root exists: %v", err)
 	}
@@ -125,7 +190,7 @@ func TestGoogleDriveFileSystem_Integration(t *testing.T) {
 	}
 	
 	// Test non-existent path
-	exists, err = gfs.Exists("/non-existent-folder-12345")
+	exists, err = gfs.Exists(context.Background(), "/non-existent-folder-12345")
 	if err != nil {
 		t.Fatalf("Failed to check if non-existent path exists: %v", err)
 	}
@@ -154,13 +219,13 @@ func TestGoogleDriveFileSystem_CreateAndDeleteFolder(t *testing.T) {
 	testFolderPath := "/" + testFolderName
 	
 	// Create test folder
-	err = gfs.CreateFolder(testFolderPath)
+	err = gfs.CreateFolder(context.Background(), testFolderPath)
 	if err != nil {
 		t.Fatalf("Failed to create test folder: %v", err)
 	}
 	
 	// Verify folder exists
-	exists, err := gfs.Exists(testFolderPath)
+	exists, err := gfs.Exists(context.Background(), testFolderPath)
 	if err != nil {
 		t.Fatalf("Failed to check if test folder exists: %v", err)
 	}
@@ -169,7 +234,7 @@ func TestGoogleDriveFileSystem_CreateAndDeleteFolder(t *testing.T) {
 	}
 	
 	// List root to find our folder
-	files, err := gfs.List("/")
+	files, err := gfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list root folder: %v", err)
 	}
@@ -201,7 +266,7 @@ func TestGoogleDriveFileSystem_CreateAndDeleteFolder(t *testing.T) {
 	}
 	
 	// Clean up - delete test folder
-	err = gfs.Delete(testFolderPath)
+	err = gfs.Delete(context.Background(), testFolderPath)
 	if err != nil {
 		t.Errorf("Failed to delete test folder: %v", err)
 	}
@@ -486,4 +551,632 @@ func TestGoogleDriveConfig_OAuth2Fields(t *testing.T) {
 	if config.RootFolderID != "root" {
 		t.Errorf("Expected root folder ID 'root', got '%s'", config.RootFolderID)
 	}
-}
\ No newline at end of file
+}
+// Tests against a fake Drive API
+
+// fakeDrive serves the parts of the Drive v3 API that GoogleDriveFileSystem
+// uses, from memory
+type fakeDrive struct {
+	mu      sync.Mutex
+	files   map[string]*drive.File
+	order   []string // File IDs in creation order, the order listings return
+	content map[string][]byte
+	nextID  int
+	calls   map[string]int // Requests by kind: list, get, download, create, update
+	drives  map[string]*drive.Drive
+	service *drive.Service
+
+	failures []fakeDriveFailure // Errors to answer the next matching requests with
+	requests int                // Requests received, including failed ones
+}
+
+// fakeDriveFailure is an error the fake Drive answers one request with
+type fakeDriveFailure struct {
+	method     string // Only requests with this method, or any when empty
+	status     int
+	reason     string
+	retryAfter string
+}
+
+// fakeDriveRootID is the ID behind the fake Drive's "root" alias
+const fakeDriveRootID = "root-folder"
+
+// newFakeDriveFileSystem returns a GoogleDriveFileSystem backed by a fake Drive
+func newFakeDriveFileSystem(t *testing.T, config *GoogleDriveConfig) (*GoogleDriveFileSystem, *fakeDrive) {
+	t.Helper()
+
+	fake := &fakeDrive{
+		files:   map[string]*drive.File{fakeDriveRootID: {Id: fakeDriveRootID, Name: "My Drive", MimeType: driveFolderMimeType}},
+		content: make(map[string][]byte),
+		calls:   make(map[string]int),
+		drives:  make(map[string]*drive.Drive),
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	// Retries go through the real transport, without the waits
+	client := server.Client()
+	client.Transport = newDriveTransport(&GoogleDriveConfig{MaxRetries: 3, RetryDelay: time.Millisecond}, client.Transport)
+	service, err := drive.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithHTTPClient(client),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create Drive service: %v", err)
+	}
+	fake.service = service
+	return fake.fileSystem(t, config), fake
+}
+
+// fileSystem returns another GoogleDriveFileSystem backed by the same fake Drive
+func (f *fakeDrive) fileSystem(t *testing.T, config *GoogleDriveConfig) *GoogleDriveFileSystem {
+	t.Helper()
+
+	if config == nil {
+		config = DefaultGoogleDriveConfig()
+	}
+	gfs, err := newGoogleDriveFileSystem(context.Background(), config, f.service)
+	if err != nil {
+		t.Fatalf("Failed to create Google Drive filesystem: %v", err)
+	}
+	return gfs
+}
+
+// add creates a file, or a folder when content is nil, and returns its ID
+func (f *fakeDrive) add(parentID, name string, content []byte) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	file := &drive.File{
+		Id:           fmt.Sprintf("id-%d", f.nextID),
+		Name:         name,
+		MimeType:     driveFolderMimeType,
+		Parents:      []string{parentID},
+		ModifiedTime: "2024-10-27T12:00:00Z",
+	}
+	if parent := f.files[parentID]; parent != nil {
+		file.DriveId = parent.DriveId
+	}
+	if content != nil {
+		file.MimeType = "text/plain"
+		file.Size = int64(len(content))
+		f.content[file.Id] = content
+	}
+	f.files[file.Id] = file
+	f.order = append(f.order, file.Id)
+	return file.Id
+}
+
+// addSharedDrive creates a shared drive and returns its ID, which is also the
+// ID of its top folder
+func (f *fakeDrive) addSharedDrive(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	id := fmt.Sprintf("drive-%d", f.nextID)
+	f.drives[id] = &drive.Drive{Id: id, Name: name}
+	f.files[id] = &drive.File{Id: id, Name: name, MimeType: driveFolderMimeType, DriveId: id}
+	return id
+}
+
+// count returns the number of requests of a kind served so far
+func (f *fakeDrive) count(kind string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[kind]
+}
+
+// fail answers the next request matching failure with its error
+func (f *fakeDrive) fail(failure fakeDriveFailure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, failure)
+}
+
+func (f *fakeDrive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	for i, failure := range f.failures {
+		if failure.method == "" || failure.method == r.Method {
+			f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			if failure.retryAfter != "" {
+				w.Header().Set("Retry-After", failure.retryAfter)
+			}
+			writeDriveError(w, failure.status, failure.reason, "Injected failure")
+			return
+		}
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/files/")
+	if id == "root" {
+		id = fakeDriveRootID
+	}
+	allDrives := r.URL.Query().Get("supportsAllDrives") == "true"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/drives":
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Query().Get("q"), "name='"), "'")
+		list := &drive.DriveList{}
+		for _, sharedDrive := range f.drives {
+			if sharedDrive.Name == name {
+				list.Drives = append(list.Drives, sharedDrive)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/drives/"):
+		if sharedDrive := f.drives[strings.TrimPrefix(r.URL.Path, "/drives/")]; sharedDrive != nil {
+			json.NewEncoder(w).Encode(sharedDrive)
+		} else {
+			writeDriveError(w, http.StatusNotFound, "notFound", "Shared drive not found")
+		}
+	case r.Method == http.MethodGet && r.URL.Path == "/files":
+		f.calls["list"]++
+		f.list(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/files":
+		f.calls["create"]++
+		var file drive.File
+		if err := json.NewDecoder(r.Body).Decode(&file); err != nil {
+			writeDriveError(w, http.StatusBadRequest, "badRequest", err.Error())
+			return
+		}
+		f.nextID++
+		file.Id = fmt.Sprintf("id-%d", f.nextID)
+		if parent := f.files[file.Parents[0]]; parent != nil {
+			file.DriveId = parent.DriveId
+		}
+		f.files[file.Id] = &file
+		f.order = append(f.order, file.Id)
+		json.NewEncoder(w).Encode(&file)
+	case f.files[id] == nil || (f.files[id].DriveId != "" && !allDrives):
+		// Like Drive, files in shared drives are only found by callers that support them
+		writeDriveError(w, http.StatusNotFound, "notFound", "File not found: "+id)
+	case r.Method == http.MethodGet && r.URL.Query().Get("alt") == "media":
+		f.calls["download"]++
+		w.Write(f.content[id])
+	case r.Method == http.MethodGet:
+		f.calls["get"]++
+		json.NewEncoder(w).Encode(f.files[id])
+	case r.Method == http.MethodPatch:
+		f.calls["update"]++
+		var update drive.File
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeDriveError(w, http.StatusBadRequest, "badRequest", err.Error())
+			return
+		}
+		file := f.files[id]
+		if update.Name != "" {
+			file.Name = update.Name
+		}
+		file.Trashed = file.Trashed || update.Trashed
+		var parents []string
+		for _, parent := range file.Parents {
+			if !strings.Contains(","+r.URL.Query().Get("removeParents")+",", ","+parent+",") {
+				parents = append(parents, parent)
+			}
+		}
+		if add := r.URL.Query().Get("addParents"); add != "" {
+			if f.files[add] != nil && f.files[add].DriveId != file.DriveId {
+				writeDriveError(w, http.StatusForbidden, "teamDrivesFolderMoveInNotSupported", "Moving items between drives is not supported")
+				return
+			}
+			parents = append(parents, add)
+		}
+		file.Parents = parents
+		json.NewEncoder(w).Encode(file)
+	default:
+		writeDriveError(w, http.StatusMethodNotAllowed, "methodNotAllowed", r.Method+" "+r.URL.Path)
+	}
+}
+
+// list serves one page of the files matching the request's query, from My
+// Drive or from the shared drive the request names
+func (f *fakeDrive) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	driveID := ""
+	if query.Get("corpora") == "drive" {
+		if query.Get("supportsAllDrives") != "true" || query.Get("includeItemsFromAllDrives") != "true" || query.Get("driveId") == "" {
+			writeDriveError(w, http.StatusBadRequest, "invalid", "corpora=drive needs driveId, supportsAllDrives and includeItemsFromAllDrives")
+			return
+		}
+		driveID = query.Get("driveId")
+	}
+
+	var matches []*drive.File
+	for _, id := range f.order {
+		if file := f.files[id]; file != nil && file.DriveId == driveID && fakeDriveMatches(file, query.Get("q")) {
+			matches = append(matches, file)
+		}
+	}
+
+	pageSize := 100 // Drive's default
+	if n, err := strconv.Atoi(r.URL.Query().Get("pageSize")); err == nil {
+		pageSize = n
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := start + pageSize
+	page := &drive.FileList{}
+	if end < len(matches) {
+		page.NextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(matches)
+	}
+	page.Files = matches[start:end]
+	json.NewEncoder(w).Encode(page)
+}
+
+// fakeDriveMatches evaluates the clauses of a query joined by " and "
+func fakeDriveMatches(file *drive.File, query string) bool {
+	for _, clause := range strings.Split(query, " and ") {
+		switch {
+		case clause == "trashed=false":
+			if file.Trashed {
+				return false
+			}
+		case strings.HasPrefix(clause, "name='"):
+			name := strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(clause, "name='"), "'"), "\\'", "'")
+			if file.Name != name {
+				return false
+			}
+		case strings.HasPrefix(clause, "mimeType='"):
+			if file.MimeType != strings.TrimSuffix(strings.TrimPrefix(clause, "mimeType='"), "'") {
+				return false
+			}
+		case strings.HasSuffix(clause, "' in parents"):
+			parent := strings.TrimSuffix(strings.TrimPrefix(clause, "'"), "' in parents")
+			if parent == "root" {
+				parent = fakeDriveRootID
+			}
+			found := false
+			for _, p := range file.Parents {
+				found = found || p == parent
+			}
+			if !found {
+				return false
+			}
+		case clause != "":
+			panic("fake Drive cannot evaluate query clause: " + clause)
+		}
+	}
+	return true
+}
+
+// writeDriveError replies with an error in the Drive API's format
+func writeDriveError(w http.ResponseWriter, code int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"errors":  []map[string]string{{"reason": reason, "message": message}},
+		},
+	})
+}
+
+func TestGoogleDriveFileSystem_ListFollowsPages(t *testing.T) {
+	config := DefaultGoogleDriveConfig()
+	config.PageSize = 2
+	gfs, fake := newFakeDriveFileSystem(t, config)
+
+	folder := fake.add(fakeDriveRootID, "Photos", nil)
+	for i := 1; i <= 5; i++ {
+		fake.add(folder, fmt.Sprintf("photo%d.jpg", i), []byte("jpeg"))
+	}
+
+	files, err := gfs.List(context.Background(), "/Photos")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(files) != 5 {
+		t.Fatalf("Expected all 5 files across pages, got %d", len(files))
+	}
+	if files[4].Path() != "/Photos/photo5.jpg" || files[4].Size() != 4 {
+		t.Errorf("Unexpected last file: %s (%d bytes)", files[4].Path(), files[4].Size())
+	}
+
+	// One query resolves the folder, three pages list it
+	if lists := fake.count("list"); lists != 4 {
+		t.Errorf("Expected 4 list requests, got %d", lists)
+	}
+}
+
+func TestGoogleDriveFileSystem_BulkTraversal(t *testing.T) {
+	walked, walkFake := newFakeDriveFileSystem(t, nil)
+	config := DefaultGoogleDriveConfig()
+	config.PageSize = 5
+	config.BulkTraversal = true
+	bulk, bulkFake := newFakeDriveFileSystem(t, config)
+
+	for _, fake := range []*fakeDrive{walkFake, bulkFake} {
+		docs := fake.add(fakeDriveRootID, "Docs", nil)
+		fake.add(docs, "a.txt", []byte("a"))
+		work := fake.add(docs, "Work", nil)
+		fake.add(work, "b.txt", []byte("b"))
+		cache := fake.add(fakeDriveRootID, "node_modules", nil)
+		fake.add(cache, "c.js", []byte("c"))
+		fake.add(fakeDriveRootID, "d.txt", []byte("d"))
+		trashed := fake.add(fakeDriveRootID, "old.txt", []byte("old"))
+		fake.files[trashed].Trashed = true
+		fake.add("someone-elses-folder", "shared.txt", []byte("shared"))
+	}
+
+	exclude, err := NewExcludeFilter("node_modules")
+	if err != nil {
+		t.Fatalf("NewExcludeFilter failed: %v", err)
+	}
+
+	walkFiles, err := getAllFilesRecursively(context.Background(), walked, "/", exclude, nil)
+	if err != nil {
+		t.Fatalf("Folder-by-folder scan failed: %v", err)
+	}
+	var scans []ProgressEvent
+	bulkFiles, err := getAllFilesRecursively(context.Background(), bulk, "/", exclude, ProgressFunc(func(e ProgressEvent) {
+		scans = append(scans, e)
+	}))
+	if err != nil {
+		t.Fatalf("Bulk scan failed: %v", err)
+	}
+
+	var walkPaths, bulkPaths []string
+	for _, file := range walkFiles {
+		walkPaths = append(walkPaths, file.Path())
+	}
+	for _, file := range bulkFiles {
+		bulkPaths = append(bulkPaths, file.Path())
+	}
+	expected := "[/Docs /Docs/a.txt /Docs/Work /Docs/Work/b.txt /d.txt]"
+	if fmt.Sprint(walkPaths) != expected || fmt.Sprint(bulkPaths) != expected {
+		t.Errorf("Expected both scans to find %s, got %v and %v", expected, walkPaths, bulkPaths)
+	}
+
+	// 8 untrashed files in pages of 5, where the walk lists each folder
+	if lists := bulkFake.count("list"); lists != 2 {
+		t.Errorf("Expected 2 list requests for the bulk scan, got %d", lists)
+	}
+	if lists := walkFake.count("list"); lists != 3 {
+		t.Errorf("Expected 3 list requests for the walk, got %d", lists)
+	}
+	if len(scans) != 1 || !scans[0].Done || scans[0].FoldersScanned != 3 || scans[0].FilesScanned != 3 {
+		t.Errorf("Unexpected scan progress: %+v", scans)
+	}
+}
+
+func TestGoogleDriveFileSystem_SharedDrive(t *testing.T) {
+	ctx := context.Background()
+	_, fake := newFakeDriveFileSystem(t, nil)
+	team := fake.addSharedDrive("Team")
+	projects := fake.add(team, "Projects", nil)
+	plan := fake.add(projects, "plan.txt", []byte("plan"))
+	fake.add(team, "Inbox", nil)
+	fake.add(fakeDriveRootID, "mine.txt", []byte("mine"))
+
+	// By name, or by ID
+	config := DefaultGoogleDriveConfig()
+	config.SharedDrive = "Team"
+	gfs := fake.fileSystem(t, config)
+	if gfs.GetRootFolderID() != team {
+		t.Errorf("Expected the shared drive as root, got %s", gfs.GetRootFolderID())
+	}
+	config = DefaultGoogleDriveConfig()
+	config.SharedDrive = team
+	if byID := fake.fileSystem(t, config); byID.GetRootFolderID() != team {
+		t.Errorf("Expected the shared drive as root, got %s", byID.GetRootFolderID())
+	}
+
+	files, err := gfs.List(ctx, "/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path())
+	}
+	if fmt.Sprint(paths) != "[/Projects /Inbox]" {
+		t.Errorf("Expected only the shared drive's files, got %v", paths)
+	}
+	if err := gfs.Move(ctx, "/Projects/plan.txt", "/Inbox/plan.txt"); err != nil {
+		t.Fatalf("Move within the shared drive failed: %v", err)
+	}
+
+	// Moves that would leave the drive are conflicts, whether Drive or curator notices
+	other := fake.addSharedDrive("Other")
+	fake.files[plan].DriveId = other
+	err = gfs.Move(ctx, "/Inbox/plan.txt", "/Projects/plan.txt")
+	if _, ok := err.(*ConflictError); !ok {
+		t.Errorf("Expected a conflict moving a file from another drive, got %v", err)
+	}
+	fake.files[plan].DriveId = team
+	fake.files[projects].DriveId = other
+	err = gfs.Move(ctx, "/Inbox/plan.txt", "/Projects/plan.txt")
+	if _, ok := err.(*ConflictError); !ok {
+		t.Errorf("Expected a conflict when Drive refuses a move between drives, got %v", err)
+	}
+
+	config = DefaultGoogleDriveConfig()
+	config.SharedDrive = "Missing"
+	if _, err := newGoogleDriveFileSystem(ctx, config, fake.service); err == nil || !strings.Contains(err.Error(), "shared drive not found") {
+		t.Errorf("Expected an unknown shared drive to be reported, got %v", err)
+	}
+}
+
+func TestDrivePathNames(t *testing.T) {
+	names := drivePathNames([]*drive.File{
+		{Id: "1AbCdEfGhXXX", Name: "report.pdf"},
+		{Id: "1AbCdEfGhYYY", Name: "report.pdf"},
+		{Id: "2ZzZzZzZzZzZ", Name: "notes"},
+		{Id: "3QqQqQqQqQqQ", Name: "notes"},
+		{Id: "4PpPpPpPpPpP", Name: "photo.jpg"},
+	})
+
+	expected := map[string]string{
+		"1AbCdEfGhXXX": "report~1AbCdEfGhX.pdf",
+		"1AbCdEfGhYYY": "report~1AbCdEfGhY.pdf",
+		"2ZzZzZzZzZzZ": "notes~2ZzZzZzZ",
+		"3QqQqQqQqQqQ": "notes~3QqQqQqQ",
+		"4PpPpPpPpPpP": "photo.jpg",
+	}
+	for id, name := range expected {
+		if names[id] != name {
+			t.Errorf("Expected %s to be named %s, got %s", id, name, names[id])
+		}
+	}
+
+	realName, idPrefix, ok := parseDrivePathName("report~1AbCdEfGhX.pdf")
+	if !ok || realName != "report.pdf" || idPrefix != "1AbCdEfGhX" {
+		t.Errorf("Unexpected parse: %s, %s, %v", realName, idPrefix, ok)
+	}
+	if _, _, ok := parseDrivePathName("photo.jpg"); ok {
+		t.Error("Expected a plain name not to parse")
+	}
+}
+
+func TestGoogleDriveFileSystem_DuplicateNames(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+	docs := fake.add(fakeDriveRootID, "Docs", nil)
+	first := fake.add(docs, "a.txt", []byte("1"))
+	second := fake.add(docs, "a.txt", []byte("2"))
+	fake.add(docs, "b.txt", []byte("b"))
+	fake.add(fakeDriveRootID, "Archive", nil)
+
+	files, err := gfs.List(ctx, "/Docs")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path())
+	}
+	firstPath, secondPath := "/Docs/a~"+first+".txt", "/Docs/a~"+second+".txt"
+	if fmt.Sprint(paths) != fmt.Sprint([]string{firstPath, secondPath, "/Docs/b.txt"}) {
+		t.Fatalf("Expected the duplicates to get distinct paths, got %v", paths)
+	}
+
+	// Every path from List works with the other methods, with or without the cache
+	for _, fs := range []*GoogleDriveFileSystem{gfs, fake.fileSystem(t, nil)} {
+		for i, p := range []string{firstPath, secondPath} {
+			reader, err := fs.Read(ctx, p)
+			if err != nil {
+				t.Fatalf("Read %s failed: %v", p, err)
+			}
+			content, _ := io.ReadAll(reader)
+			reader.Close()
+			if string(content) != fmt.Sprint(i+1) {
+				t.Errorf("Expected %s to hold %d, got %q", p, i+1, content)
+			}
+			info, err := fs.Stat(ctx, p)
+			if err != nil || info == nil || info.Path() != p || info.Name() != "a.txt" {
+				t.Errorf("Unexpected Stat of %s: %v, %v", p, info, err)
+			}
+		}
+	}
+
+	// The bare name is there, but cannot say which file it means
+	if exists, err := gfs.Exists(ctx, "/Docs/a.txt"); err != nil || !exists {
+		t.Errorf("Expected the shared name to exist, got %v, %v", exists, err)
+	}
+	if _, err := gfs.Read(ctx, "/Docs/a.txt"); err == nil || !strings.Contains(err.Error(), "a~"+first+".txt") {
+		t.Errorf("Expected the ambiguity to name the paths to use, got %v", err)
+	}
+
+	// Moving a duplicate gives it back its real name
+	if err := gfs.Move(ctx, secondPath, "/Archive/a~"+second+".txt"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if fake.files[second].Name != "a.txt" {
+		t.Errorf("Expected the moved file to be named a.txt, got %s", fake.files[second].Name)
+	}
+	if exists, _ := gfs.Exists(ctx, "/Archive/a.txt"); !exists {
+		t.Error("Expected the moved file under its real name")
+	}
+
+	// A plan made while the names clashed still finds the file once they no longer do
+	store := NewMemoryOperationStore()
+	plan := &CleanupPlan{ID: "cleanup-1", Deletions: []Deletion{{ID: "delete-1", Path: firstPath, Size: 1}}}
+	if err := store.SaveCleanupPlan(ctx, plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+	execLog, err := NewExecutionEngine(gfs, store).ExecuteCleanupPlan(ctx, plan.ID, false)
+	if err != nil {
+		t.Fatalf("ExecutePlan failed: %v", err)
+	}
+	if len(execLog.Completed) != 1 || !fake.files[first].Trashed {
+		t.Errorf("Expected the duplicate to be deleted, got %+v", execLog)
+	}
+}
+
+func TestGoogleDriveFileSystem_CreateFolder(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+	docs := fake.add(fakeDriveRootID, "Docs", nil)
+	fake.add(docs, "a.txt", []byte("a"))
+
+	// Missing parents are created, and folders that exist are left alone
+	if err := gfs.CreateFolder(ctx, "/Docs/2024/Taxes"); err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	if err := gfs.CreateFolder(ctx, "/Docs/2024/Taxes"); err != nil {
+		t.Errorf("Expected creating an existing folder to succeed, got %v", err)
+	}
+	if err := fake.fileSystem(t, nil).CreateFolder(ctx, "/Docs/2024"); err != nil {
+		t.Errorf("Expected creating an existing folder to succeed without the cache, got %v", err)
+	}
+	if creates := fake.count("create"); creates != 2 {
+		t.Errorf("Expected 2 folders to be created, got %d", creates)
+	}
+	if info, err := gfs.Stat(ctx, "/Docs/2024/Taxes"); err != nil || !info.IsDir() {
+		t.Errorf("Expected the nested folder to exist, got %v, %v", info, err)
+	}
+
+	if err := gfs.CreateFolder(ctx, "/Docs/a.txt"); err == nil {
+		t.Error("Expected an error creating a folder where a file is")
+	}
+	if err := gfs.CreateFolder(ctx, "/Docs/a.txt/Sub"); err == nil {
+		t.Error("Expected an error creating a folder inside a file")
+	}
+}
+
+func TestGoogleDriveFileSystem_CreateFolderConcurrently(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+
+	// Every move needs /Archive/2024, through a folder of its own
+	plan := &ReorganizationPlan{ID: "reorg-1", Timestamp: time.Now()}
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("file-%d.txt", i)
+		fake.add(fakeDriveRootID, name, []byte(name))
+		plan.Moves = append(plan.Moves, Move{
+			ID:          fmt.Sprintf("move-%d", i),
+			Source:      "/" + name,
+			Destination: fmt.Sprintf("/Archive/2024/%d/%s", i, name),
+			Type:        FileMove,
+		})
+	}
+	store := NewMemoryOperationStore()
+	if err := store.SavePlan(ctx, plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	execLog, err := NewExecutionEngine(gfs, store).ExecutePlanWithOptions(ctx, plan.ID, ExecuteOptions{Concurrency: 4})
+	if err != nil {
+		t.Fatalf("ExecutePlan failed: %v", err)
+	}
+	if len(execLog.Failed) != 0 || len(execLog.Completed) != len(plan.Moves) {
+		t.Fatalf("Expected every move to complete, got %d completed and failures %+v", len(execLog.Completed), execLog.Failed)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	folders := make(map[string]int)
+	for _, file := range fake.files {
+		if file.MimeType == driveFolderMimeType {
+			folders[file.Name]++
+		}
+	}
+	if folders["Archive"] != 1 || folders["2024"] != 1 {
+		t.Errorf("Expected each shared folder to be created once, got %v", folders)
+	}
+}
@@ -0,0 +1,182 @@
+package curator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxDriveRetryDelay caps the wait between retries of one request, including
+// the wait a Retry-After asks for
+const maxDriveRetryDelay = time.Minute
+
+// driveRateLimitReasons are the 403 reasons Drive gives for requests that
+// succeed when sent again more slowly
+var driveRateLimitReasons = map[string]bool{
+	"userRateLimitExceeded": true,
+	"rateLimitExceeded":     true,
+}
+
+// driveDailyLimitReasons are the reasons Drive gives when a quota for the day
+// or project is used up. Sending the request again will not help before the
+// quota resets, so they are not retried, whatever the status code.
+var driveDailyLimitReasons = map[string]bool{
+	"dailyLimitExceeded": true,
+	"quotaExceeded":      true,
+}
+
+// driveTransport paces Drive API requests and retries the ones Drive turns
+// away for coming too fast, or fails with a temporary server error. Every
+// other response, including quota and permission errors, is returned as is.
+type driveTransport struct {
+	base       http.RoundTripper
+	limiter    *rate.Limiter
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// newDriveTransport wraps base with the rate limit and retries in config
+func newDriveTransport(config *GoogleDriveConfig, base http.RoundTripper) *driveTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if config.RateLimit > 0 {
+		// Allow a second's worth of requests at once, so short bursts are not slowed down
+		limiter = rate.NewLimiter(rate.Limit(config.RateLimit), max(1, int(config.RateLimit)))
+	}
+
+	return &driveTransport{
+		base:       base,
+		limiter:    limiter,
+		maxRetries: config.MaxRetries,
+		retryDelay: config.RetryDelay,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *driveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter error: %w", err)
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		// A request whose body cannot be sent again gets one attempt
+		canResend := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if attempt >= t.maxRetries || !canResend || !shouldRetryDrive(req, resp) {
+			return resp, nil
+		}
+
+		delay := driveRetryDelay(resp, attempt, t.retryDelay)
+		resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up after %d attempts: %w", attempt+1, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		attemptReq = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to resend request body: %w", err)
+			}
+			attemptReq.Body = body
+		}
+	}
+}
+
+// shouldRetryDrive reports whether a response is Drive asking to slow down or
+// a temporary server error. Used up daily quotas are not retried. Server errors are not retried for creates, which
+// may have happened anyway and would then be done twice.
+func shouldRetryDrive(req *http.Request, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusForbidden:
+		reasons := driveResponseReasons(resp)
+		for _, reason := range reasons {
+			if driveDailyLimitReasons[reason] {
+				return false
+			}
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		for _, reason := range reasons {
+			if driveRateLimitReasons[reason] {
+				return true
+			}
+		}
+		return false
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return req.Method != http.MethodPost
+	default:
+		return false
+	}
+}
+
+// driveResponseReasons returns the reasons in a Drive error response, leaving
+// its body to be read again
+func driveResponseReasons(resp *http.Response) []string {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	var body struct {
+		Error struct {
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, item := range body.Error.Errors {
+		reasons = append(reasons, item.Reason)
+	}
+	return reasons
+}
+
+// driveRetryDelay returns how long to wait before sending a request again: as
+// long as Drive's Retry-After asks, up to maxDriveRetryDelay, or else an
+// exponential backoff from base with jitter, so concurrent requests do not
+// retry in lockstep
+func driveRetryDelay(resp *http.Response, attempt int, base time.Duration) time.Duration {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, maxDriveRetryDelay)
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(0, time.Until(at)), maxDriveRetryDelay)
+		}
+	}
+
+	delay := maxDriveRetryDelay
+	if attempt < 16 && base<<attempt < maxDriveRetryDelay {
+		delay = base << attempt
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,149 @@
+package curator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDriveTransport_RetriesRateLimits(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+	fake.add(fakeDriveRootID, "a.txt", []byte("a"))
+	fake.requests = 0
+
+	fake.fail(fakeDriveFailure{status: http.StatusTooManyRequests, reason: "rateLimitExceeded", retryAfter: "0"})
+	fake.fail(fakeDriveFailure{status: http.StatusForbidden, reason: "userRateLimitExceeded"})
+	fake.fail(fakeDriveFailure{status: http.StatusServiceUnavailable, reason: "backendError"})
+	if exists, err := gfs.Exists(ctx, "/a.txt"); err != nil || !exists {
+		t.Fatalf("Expected the lookup to succeed after retries, got %v, %v", exists, err)
+	}
+	if fake.requests != 4 {
+		t.Errorf("Expected 3 retries, got %d requests", fake.requests)
+	}
+
+	// Permanent errors come back at once
+	fake.requests = 0
+	fake.fail(fakeDriveFailure{status: http.StatusForbidden, reason: "insufficientFilePermissions"})
+	if _, err := gfs.Read(ctx, "/a.txt"); err == nil {
+		t.Error("Expected a permission error")
+	}
+	if fake.requests != 1 {
+		t.Errorf("Expected a permanent error not to be retried, got %d requests", fake.requests)
+	}
+
+	// And so do used up daily quotas, which will not recover before the quota resets
+	for _, failure := range []fakeDriveFailure{
+		{status: http.StatusForbidden, reason: "dailyLimitExceeded"},
+		{status: http.StatusForbidden, reason: "quotaExceeded"},
+		{status: http.StatusTooManyRequests, reason: "quotaExceeded"},
+	} {
+		fake.requests = 0
+		fake.fail(failure)
+		if _, err := gfs.Read(ctx, "/a.txt"); err == nil {
+			t.Errorf("Expected the %d %s error", failure.status, failure.reason)
+		}
+		if fake.requests != 1 {
+			t.Errorf("Expected %d %s not to be retried, got %d requests", failure.status, failure.reason, fake.requests)
+		}
+	}
+
+	// And so do rate limits once the retries run out
+	fake.requests = 0
+	for i := 0; i < 4; i++ {
+		fake.fail(fakeDriveFailure{status: http.StatusTooManyRequests, reason: "rateLimitExceeded"})
+	}
+	if _, err := gfs.Exists(ctx, "/b.txt"); err == nil {
+		t.Error("Expected the rate limit error once retries ran out")
+	}
+	if fake.requests != 4 {
+		t.Errorf("Expected 1 attempt and 3 retries, got %d requests", fake.requests)
+	}
+}
+
+func TestDriveTransport_Paces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// 10 requests at once, then 10 per second
+	client := &http.Client{Transport: newDriveTransport(&GoogleDriveConfig{RateLimit: 10}, server.Client().Transport)}
+	start := time.Now()
+	for i := 0; i < 13; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("Expected the requests past the burst to be paced, took %v", elapsed)
+	}
+}
+
+func TestDriveRetryDelay(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	if delay := driveRetryDelay(resp, 0, time.Second); delay != 7*time.Second {
+		t.Errorf("Expected Retry-After seconds to be honored, got %v", delay)
+	}
+	resp.Header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+	if delay := driveRetryDelay(resp, 0, time.Second); delay < 29*time.Second || delay > 30*time.Second {
+		t.Errorf("Expected a Retry-After date to be honored, got %v", delay)
+	}
+
+	// Long waits are capped, whether as seconds or as a date
+	resp.Header.Set("Retry-After", "86400")
+	if delay := driveRetryDelay(resp, 0, time.Second); delay != maxDriveRetryDelay {
+		t.Errorf("Expected a long Retry-After to be capped, got %v", delay)
+	}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if delay := driveRetryDelay(resp, 0, time.Second); delay != maxDriveRetryDelay {
+		t.Errorf("Expected a far Retry-After date to be capped, got %v", delay)
+	}
+
+	resp.Header.Del("Retry-After")
+	for attempt, limit := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if delay := driveRetryDelay(resp, attempt, time.Second); delay < limit/2 || delay > limit {
+			t.Errorf("Expected attempt %d to wait between %v and %v, got %v", attempt, limit/2, limit, delay)
+		}
+	}
+	if delay := driveRetryDelay(resp, 20, time.Second); delay > maxDriveRetryDelay {
+		t.Errorf("Expected the backoff to be capped, got %v", delay)
+	}
+}
+
+func TestGoogleDriveFileSystem_StorageQuota(t *testing.T) {
+	ctx := context.Background()
+	gfs, fake := newFakeDriveFileSystem(t, nil)
+	fake.add(fakeDriveRootID, "a.txt", []byte("a"))
+	fake.add(fakeDriveRootID, "Docs", nil)
+
+	store := NewMemoryOperationStore()
+	plan := &ReorganizationPlan{ID: "reorg-1", Timestamp: time.Now(), Moves: []Move{
+		{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove},
+	}}
+	if err := store.SavePlan(ctx, plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	fake.fail(fakeDriveFailure{method: http.MethodPatch, status: http.StatusForbidden, reason: "storageQuotaExceeded"})
+	execLog, err := NewExecutionEngine(gfs, store).ExecutePlan(ctx, plan.ID, false)
+	if err != nil {
+		t.Fatalf("ExecutePlan failed: %v", err)
+	}
+	if len(execLog.Failed) != 1 || execLog.Failed[0].Kind != FailureStorageQuota {
+		t.Fatalf("Expected the move to fail for lack of storage, got %+v", execLog.Failed)
+	}
+	if report := NewReporter().FormatExecutionLog(execLog); !strings.Contains(report, "storage is full") {
+		t.Errorf("Expected the report to point out the full storage:\n%s", report)
+	}
+
+	// Other failures have no kind
+	if kind := failureKind(errors.New("permission denied")); kind != "" {
+		t.Error("Expected an ordinary error to have no failure kind")
+	}
+}
@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -65,7 +66,11 @@ func (lfs *LocalFileSystem) resolvePath(path string) (string, error) {
 }
 
 // List implements FileSystem.List
-func (lfs *LocalFileSystem) List(path string) ([]FileInfo, error) {
+func (lfs *LocalFileSystem) List(ctx context.Context, path string) ([]FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	absPath, err := lfs.resolvePath(path)
 	if err != nil {
 		return nil, err
@@ -115,7 +120,11 @@ func (lfs *LocalFileSystem) List(path string) ([]FileInfo, error) {
 }
 
 // Read implements FileSystem.Read
-func (lfs *LocalFileSystem) Read(path string) (io.ReadCloser, error) {
+func (lfs *LocalFileSystem) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	absPath, err := lfs.resolvePath(path)
 	if err != nil {
 		return nil, err
@@ -130,7 +139,7 @@ func (lfs *LocalFileSystem) Read(path string) (io.ReadCloser, error) {
 }
 
 // Move implements FileSystem.Move
-func (lfs *LocalFileSystem) Move(source, destination string) error {
+func (lfs *LocalFileSystem) Move(ctx context.Context, source, destination string) error {
 	srcPath, err := lfs.resolvePath(source)
 	if err != nil {
 		return fmt.Errorf("invalid source path: %w", err)
@@ -166,7 +175,7 @@ func (lfs *LocalFileSystem) Move(source, destination string) error {
 }
 
 // CreateFolder implements FileSystem.CreateFolder
-func (lfs *LocalFileSystem) CreateFolder(path string) error {
+func (lfs *LocalFileSystem) CreateFolder(ctx context.Context, path string) error {
 	absPath, err := lfs.resolvePath(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
@@ -181,7 +190,7 @@ func (lfs *LocalFileSystem) CreateFolder(path string) error {
 }
 
 // Delete implements FileSystem.Delete
-func (lfs *LocalFileSystem) Delete(path string) error {
+func (lfs *LocalFileSystem) Delete(ctx context.Context, path string) error {
 	absPath, err := lfs.resolvePath(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
@@ -201,7 +210,7 @@ func (lfs *LocalFileSystem) Delete(path string) error {
 }
 
 // Exists implements FileSystem.Exists
-func (lfs *LocalFileSystem) Exists(path string) (bool, error) {
+func (lfs *LocalFileSystem) Exists(ctx context.Context, path string) (bool, error) {
 	absPath, err := lfs.resolvePath(path)
 	if err != nil {
 		return false, err
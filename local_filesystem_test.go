@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -50,13 +51,13 @@ func TestLocalFileSystem_CreateFolderAndList(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	
 	// Create a folder
-	err := lfs.CreateFolder("/testdir")
+	err := lfs.CreateFolder(context.Background(), "/testdir")
 	if err != nil {
 		t.Fatalf("Failed to create folder: %v", err)
 	}
 	
 	// Verify folder exists
-	exists, err := lfs.Exists("/testdir")
+	exists, err := lfs.Exists(context.Background(), "/testdir")
 	if err != nil {
 		t.Fatalf("Failed to check if folder exists: %v", err)
 	}
@@ -65,7 +66,7 @@ func TestLocalFileSystem_CreateFolderAndList(t *testing.T) {
 	}
 	
 	// List contents of root
-	files, err := lfs.List("/")
+	files, err := lfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list root directory: %v", err)
 	}
@@ -100,7 +101,7 @@ func TestLocalFileSystem_CreateFileAndRead(t *testing.T) {
 	}
 	
 	// List and verify
-	files, err := lfs.List("/")
+	files, err := lfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
@@ -123,7 +124,7 @@ func TestLocalFileSystem_CreateFileAndRead(t *testing.T) {
 	}
 	
 	// Test reading
-	reader, err := lfs.Read("/test.txt")
+	reader, err := lfs.Read(context.Background(), "/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to read file: %v", err)
 	}
@@ -156,19 +157,19 @@ func TestLocalFileSystem_Move(t *testing.T) {
 	}
 	
 	// Create destination directory
-	err = lfs.CreateFolder("/dest")
+	err = lfs.CreateFolder(context.Background(), "/dest")
 	if err != nil {
 		t.Fatalf("Failed to create destination directory: %v", err)
 	}
 	
 	// Move file
-	err = lfs.Move("/source.txt", "/dest/moved.txt")
+	err = lfs.Move(context.Background(), "/source.txt", "/dest/moved.txt")
 	if err != nil {
 		t.Fatalf("Failed to move file: %v", err)
 	}
 	
 	// Verify source no longer exists
-	exists, err := lfs.Exists("/source.txt")
+	exists, err := lfs.Exists(context.Background(), "/source.txt")
 	if err != nil {
 		t.Fatalf("Failed to check source existence: %v", err)
 	}
@@ -177,7 +178,7 @@ func TestLocalFileSystem_Move(t *testing.T) {
 	}
 	
 	// Verify destination exists
-	exists, err = lfs.Exists("/dest/moved.txt")
+	exists, err = lfs.Exists(context.Background(), "/dest/moved.txt")
 	if err != nil {
 		t.Fatalf("Failed to check destination existence: %v", err)
 	}
@@ -186,7 +187,7 @@ func TestLocalFileSystem_Move(t *testing.T) {
 	}
 	
 	// Verify content is preserved
-	reader, err := lfs.Read("/dest/moved.txt")
+	reader, err := lfs.Read(context.Background(), "/dest/moved.txt")
 	if err != nil {
 		t.Fatalf("Failed to read moved file: %v", err)
 	}
@@ -217,7 +218,7 @@ func TestLocalFileSystem_Delete(t *testing.T) {
 	}
 	
 	// Verify file exists
-	exists, err := lfs.Exists("/delete_me.txt")
+	exists, err := lfs.Exists(context.Background(), "/delete_me.txt")
 	if err != nil {
 		t.Fatalf("Failed to check file existence: %v", err)
 	}
@@ -226,13 +227,13 @@ func TestLocalFileSystem_Delete(t *testing.T) {
 	}
 	
 	// Delete file
-	err = lfs.Delete("/delete_me.txt")
+	err = lfs.Delete(context.Background(), "/delete_me.txt")
 	if err != nil {
 		t.Fatalf("Failed to delete file: %v", err)
 	}
 	
 	// Verify file no longer exists
-	exists, err = lfs.Exists("/delete_me.txt")
+	exists, err = lfs.Exists(context.Background(), "/delete_me.txt")
 	if err != nil {
 		t.Fatalf("Failed to check file existence after deletion: %v", err)
 	}
@@ -268,7 +269,7 @@ func TestLocalFileSystem_Hash(t *testing.T) {
 	}
 	
 	// List files and check hashes
-	files, err := lfs.List("/")
+	files, err := lfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
@@ -328,7 +329,7 @@ func TestLocalFileSystem_MimeType(t *testing.T) {
 	}
 	
 	// List files and check MIME types
-	fileInfos, err := lfs.List("/")
+	fileInfos, err := lfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
@@ -354,13 +355,13 @@ func TestLocalFileSystem_PathSecurity(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	
 	// Try to access files outside root directory
-	_, err := lfs.List("../../")
+	_, err := lfs.List(context.Background(), "../../")
 	if err == nil {
 		t.Error("Should not be able to access files outside root directory")
 	}
 	
 	// Try to create folder outside root
-	err = lfs.CreateFolder("../outside")
+	err = lfs.CreateFolder(context.Background(), "../outside")
 	if err == nil {
 		t.Error("Should not be able to create folder outside root directory")
 	}
@@ -372,7 +373,7 @@ func TestLocalFileSystem_PathSecurity(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 	
-	err = lfs.Move("/test.txt", "../outside.txt")
+	err = lfs.Move(context.Background(), "/test.txt", "../outside.txt")
 	if err == nil {
 		t.Error("Should not be able to move file outside root directory")
 	}
@@ -383,19 +384,19 @@ func TestLocalFileSystem_ErrorHandling(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	
 	// Test reading non-existent file
-	_, err := lfs.Read("/nonexistent.txt")
+	_, err := lfs.Read(context.Background(), "/nonexistent.txt")
 	if err == nil {
 		t.Error("Should return error when reading non-existent file")
 	}
 	
 	// Test moving non-existent file
-	err = lfs.Move("/nonexistent.txt", "/dest.txt")
+	err = lfs.Move(context.Background(), "/nonexistent.txt", "/dest.txt")
 	if err == nil {
 		t.Error("Should return error when moving non-existent file")
 	}
 	
 	// Test deleting non-existent file
-	err = lfs.Delete("/nonexistent.txt")
+	err = lfs.Delete(context.Background(), "/nonexistent.txt")
 	if err == nil {
 		t.Error("Should return error when deleting non-existent file")
 	}
@@ -414,7 +415,7 @@ func TestLocalFileSystem_ErrorHandling(t *testing.T) {
 		t.Fatalf("Failed to create file2: %v", err)
 	}
 	
-	err = lfs.Move("/file1.txt", "/file2.txt")
+	err = lfs.Move(context.Background(), "/file1.txt", "/file2.txt")
 	if err == nil {
 		t.Error("Should return error when moving to existing destination")
 	}
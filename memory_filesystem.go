@@ -1,16 +1,20 @@
 package curator
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
-// MemoryFileSystem implements FileSystem interface for testing
+// MemoryFileSystem implements FileSystem interface for testing. It is safe
+// for concurrent use.
 type MemoryFileSystem struct {
+	mu    sync.RWMutex
 	files map[string]*memoryFile
 	utils *FileUtilities
 }
@@ -33,8 +37,28 @@ func NewMemoryFileSystem() *MemoryFileSystem {
 	}
 }
 
+// NewMemoryFileSystemFromFiles creates an in-memory copy of a scanned tree.
+// Sizes, times and MIME types are copied, file contents are not.
+func NewMemoryFileSystemFromFiles(files []FileInfo) *MemoryFileSystem {
+	mfs := NewMemoryFileSystem()
+	for _, file := range files {
+		if file.IsDir() {
+			mfs.AddFolder(file.Path())
+			continue
+		}
+		mfs.AddFile(file.Path(), nil, file.MimeType())
+		copied := mfs.files[filepath.Clean(file.Path())]
+		copied.size = file.Size()
+		copied.modTime = file.ModTime()
+	}
+	return mfs
+}
+
 // AddFile adds a file to the memory filesystem
 func (mfs *MemoryFileSystem) AddFile(path string, content []byte, mimeType string) {
+	mfs.mu.Lock()
+	defer mfs.mu.Unlock()
+
 	path = filepath.Clean(path)
 	name := filepath.Base(path)
 	
@@ -51,12 +75,20 @@ func (mfs *MemoryFileSystem) AddFile(path string, content []byte, mimeType strin
 	// Create parent directories if they don't exist
 	dir := filepath.Dir(path)
 	if dir != "." && dir != "/" {
-		mfs.CreateFolder(dir)
+		mfs.addFolder(dir)
 	}
 }
 
 // AddFolder adds a directory to the memory filesystem
 func (mfs *MemoryFileSystem) AddFolder(path string) {
+	mfs.mu.Lock()
+	defer mfs.mu.Unlock()
+
+	mfs.addFolder(path)
+}
+
+// addFolder adds a directory and its missing parents; the caller holds mu
+func (mfs *MemoryFileSystem) addFolder(path string) {
 	path = filepath.Clean(path)
 	name := filepath.Base(path)
 	
@@ -73,12 +105,15 @@ func (mfs *MemoryFileSystem) AddFolder(path string) {
 	// Create parent directories if they don't exist
 	dir := filepath.Dir(path)
 	if dir != "." && dir != "/" {
-		mfs.CreateFolder(dir)
+		mfs.addFolder(dir)
 	}
 }
 
 // List implements FileSystem.List
-func (mfs *MemoryFileSystem) List(path string) ([]FileInfo, error) {
+func (mfs *MemoryFileSystem) List(ctx context.Context, path string) ([]FileInfo, error) {
+	mfs.mu.RLock()
+	defer mfs.mu.RUnlock()
+
 	path = filepath.Clean(path)
 	
 	var files []FileInfo
@@ -98,7 +133,10 @@ func (mfs *MemoryFileSystem) List(path string) ([]FileInfo, error) {
 }
 
 // Read implements FileSystem.Read
-func (mfs *MemoryFileSystem) Read(path string) (io.ReadCloser, error) {
+func (mfs *MemoryFileSystem) Read(ctx context.Context, path string) (io.ReadCloser, error) {
+	mfs.mu.RLock()
+	defer mfs.mu.RUnlock()
+
 	path = filepath.Clean(path)
 	
 	file, exists := mfs.files[path]
@@ -114,7 +152,10 @@ func (mfs *MemoryFileSystem) Read(path string) (io.ReadCloser, error) {
 }
 
 // Move implements FileSystem.Move
-func (mfs *MemoryFileSystem) Move(source, destination string) error {
+func (mfs *MemoryFileSystem) Move(ctx context.Context, source, destination string) error {
+	mfs.mu.Lock()
+	defer mfs.mu.Unlock()
+
 	source = filepath.Clean(source)
 	destination = filepath.Clean(destination)
 	
@@ -131,7 +172,7 @@ func (mfs *MemoryFileSystem) Move(source, destination string) error {
 	// Create parent directory if it doesn't exist
 	dir := filepath.Dir(destination)
 	if dir != "." && dir != "/" {
-		mfs.CreateFolder(dir)
+		mfs.addFolder(dir)
 	}
 	
 	// Create new file at destination
@@ -159,10 +200,13 @@ func (mfs *MemoryFileSystem) Move(source, destination string) error {
 		}
 		
 		// Now move the collected paths
+		// Entries are copied rather than updated, since FileInfos handed out
+		// by List share them
 		for _, filePath := range pathsToMove {
 			newPath := strings.Replace(filePath, source, destination, 1)
-			mfs.files[newPath] = mfs.files[filePath]
-			mfs.files[newPath].path = newPath
+			moved := *mfs.files[filePath]
+			moved.path = newPath
+			mfs.files[newPath] = &moved
 			delete(mfs.files, filePath)
 		}
 	}
@@ -171,19 +215,25 @@ func (mfs *MemoryFileSystem) Move(source, destination string) error {
 }
 
 // CreateFolder implements FileSystem.CreateFolder
-func (mfs *MemoryFileSystem) CreateFolder(path string) error {
+func (mfs *MemoryFileSystem) CreateFolder(ctx context.Context, path string) error {
+	mfs.mu.Lock()
+	defer mfs.mu.Unlock()
+
 	path = filepath.Clean(path)
 	
 	if _, exists := mfs.files[path]; exists {
 		return nil // Already exists
 	}
 	
-	mfs.AddFolder(path)
+	mfs.addFolder(path)
 	return nil
 }
 
 // Delete implements FileSystem.Delete
-func (mfs *MemoryFileSystem) Delete(path string) error {
+func (mfs *MemoryFileSystem) Delete(ctx context.Context, path string) error {
+	mfs.mu.Lock()
+	defer mfs.mu.Unlock()
+
 	path = filepath.Clean(path)
 	
 	file, exists := mfs.files[path]
@@ -206,7 +256,10 @@ func (mfs *MemoryFileSystem) Delete(path string) error {
 }
 
 // Exists implements FileSystem.Exists
-func (mfs *MemoryFileSystem) Exists(path string) (bool, error) {
+func (mfs *MemoryFileSystem) Exists(ctx context.Context, path string) (bool, error) {
+	mfs.mu.RLock()
+	defer mfs.mu.RUnlock()
+
 	path = filepath.Clean(path)
 	_, exists := mfs.files[path]
 	return exists, nil
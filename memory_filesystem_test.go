@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"io"
 	"testing"
 )
@@ -13,7 +14,7 @@ func TestMemoryFileSystem_AddFileAndList(t *testing.T) {
 	mfs.AddFile("/test.txt", content, "text/plain")
 	
 	// List files in root
-	files, err := mfs.List("/")
+	files, err := mfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
@@ -51,7 +52,7 @@ func TestMemoryFileSystem_AddFolderAndList(t *testing.T) {
 	mfs.AddFolder("/docs")
 	
 	// List files in root
-	files, err := mfs.List("/")
+	files, err := mfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
@@ -76,7 +77,7 @@ func TestMemoryFileSystem_Read(t *testing.T) {
 	content := []byte("hello world")
 	mfs.AddFile("/hello.txt", content, "text/plain")
 	
-	reader, err := mfs.Read("/hello.txt")
+	reader, err := mfs.Read(context.Background(), "/hello.txt")
 	if err != nil {
 		t.Fatalf("Failed to read file: %v", err)
 	}
@@ -99,13 +100,13 @@ func TestMemoryFileSystem_Move(t *testing.T) {
 	mfs.AddFile("/test.txt", content, "text/plain")
 	
 	// Move the file
-	err := mfs.Move("/test.txt", "/moved/test.txt")
+	err := mfs.Move(context.Background(), "/test.txt", "/moved/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to move file: %v", err)
 	}
 	
 	// Check that original doesn't exist
-	exists, err := mfs.Exists("/test.txt")
+	exists, err := mfs.Exists(context.Background(), "/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to check existence: %v", err)
 	}
@@ -114,7 +115,7 @@ func TestMemoryFileSystem_Move(t *testing.T) {
 	}
 	
 	// Check that new location exists
-	exists, err = mfs.Exists("/moved/test.txt")
+	exists, err = mfs.Exists(context.Background(), "/moved/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to check existence: %v", err)
 	}
@@ -123,7 +124,7 @@ func TestMemoryFileSystem_Move(t *testing.T) {
 	}
 	
 	// Check that parent directory was created
-	exists, err = mfs.Exists("/moved")
+	exists, err = mfs.Exists(context.Background(), "/moved")
 	if err != nil {
 		t.Fatalf("Failed to check existence: %v", err)
 	}
@@ -132,7 +133,7 @@ func TestMemoryFileSystem_Move(t *testing.T) {
 	}
 	
 	// Verify content is preserved
-	reader, err := mfs.Read("/moved/test.txt")
+	reader, err := mfs.Read(context.Background(), "/moved/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to read moved file: %v", err)
 	}
@@ -154,13 +155,13 @@ func TestMemoryFileSystem_Delete(t *testing.T) {
 	mfs.AddFile("/test.txt", []byte("content"), "text/plain")
 	
 	// Delete the file
-	err := mfs.Delete("/test.txt")
+	err := mfs.Delete(context.Background(), "/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to delete file: %v", err)
 	}
 	
 	// Check that it doesn't exist
-	exists, err := mfs.Exists("/test.txt")
+	exists, err := mfs.Exists(context.Background(), "/test.txt")
 	if err != nil {
 		t.Fatalf("Failed to check existence: %v", err)
 	}
@@ -177,7 +178,7 @@ func TestMemoryFileSystem_Hash(t *testing.T) {
 	mfs.AddFile("/file1.txt", content, "text/plain")
 	mfs.AddFile("/file2.txt", content, "text/plain")
 	
-	files, err := mfs.List("/")
+	files, err := mfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
@@ -207,7 +208,7 @@ func TestMemoryFileSystem_NestedDirectories(t *testing.T) {
 	mfs.AddFile("/docs/work/report.pdf", []byte("pdf content"), "application/pdf")
 	
 	// List root directory
-	rootFiles, err := mfs.List("/")
+	rootFiles, err := mfs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list root: %v", err)
 	}
@@ -221,7 +222,7 @@ func TestMemoryFileSystem_NestedDirectories(t *testing.T) {
 	}
 	
 	// List docs directory
-	docsFiles, err := mfs.List("/docs")
+	docsFiles, err := mfs.List(context.Background(), "/docs")
 	if err != nil {
 		t.Fatalf("Failed to list docs: %v", err)
 	}
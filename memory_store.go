@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -9,23 +10,33 @@ import (
 
 // MemoryOperationStore implements OperationStore interface using in-memory storage
 type MemoryOperationStore struct {
-	mu         sync.RWMutex
-	plans      map[string]*ReorganizationPlan
-	operations map[string]*Operation
-	execLogs   []*ExecutionLog
+	mu           sync.RWMutex
+	plans        map[string]*ReorganizationPlan
+	cleanupPlans map[string]*CleanupPlan
+	renamePlans  map[string]*RenamingPlan
+	dedupPlans   map[string]*DeduplicationPlan
+	operations   map[string]*Operation
+	execLogs     []*ExecutionLog
 }
 
 // NewMemoryOperationStore creates a new in-memory operation store
 func NewMemoryOperationStore() *MemoryOperationStore {
 	return &MemoryOperationStore{
-		plans:      make(map[string]*ReorganizationPlan),
-		operations: make(map[string]*Operation),
-		execLogs:   make([]*ExecutionLog, 0),
+		plans:        make(map[string]*ReorganizationPlan),
+		cleanupPlans: make(map[string]*CleanupPlan),
+		renamePlans:  make(map[string]*RenamingPlan),
+		dedupPlans:   make(map[string]*DeduplicationPlan),
+		operations:   make(map[string]*Operation),
+		execLogs:     make([]*ExecutionLog, 0),
 	}
 }
 
 // SavePlan implements OperationStore.SavePlan
-func (m *MemoryOperationStore) SavePlan(plan *ReorganizationPlan) error {
+func (m *MemoryOperationStore) SavePlan(ctx context.Context, plan *ReorganizationPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
@@ -39,13 +50,13 @@ func (m *MemoryOperationStore) SavePlan(plan *ReorganizationPlan) error {
 }
 
 // GetPlan implements OperationStore.GetPlan
-func (m *MemoryOperationStore) GetPlan(id string) (*ReorganizationPlan, error) {
+func (m *MemoryOperationStore) GetPlan(ctx context.Context, id string) (*ReorganizationPlan, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
 	plan, exists := m.plans[id]
 	if !exists {
-		return nil, fmt.Errorf("plan not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrPlanNotFound, id)
 	}
 	
 	// Return a copy to avoid external modifications
@@ -57,7 +68,7 @@ func (m *MemoryOperationStore) GetPlan(id string) (*ReorganizationPlan, error) {
 }
 
 // ListPlans implements OperationStore.ListPlans
-func (m *MemoryOperationStore) ListPlans() ([]*PlanSummary, error) {
+func (m *MemoryOperationStore) ListPlans(ctx context.Context) ([]*PlanSummary, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
@@ -66,6 +77,7 @@ func (m *MemoryOperationStore) ListPlans() ([]*PlanSummary, error) {
 	for _, plan := range m.plans {
 		summary := &PlanSummary{
 			ID:        plan.ID,
+			Type:      PlanTypeReorganization,
 			Timestamp: plan.Timestamp,
 			Status:    "pending", // Default status
 			FileCount: len(plan.Moves),
@@ -73,6 +85,39 @@ func (m *MemoryOperationStore) ListPlans() ([]*PlanSummary, error) {
 		}
 		summaries = append(summaries, summary)
 	}
+
+	for _, plan := range m.cleanupPlans {
+		summaries = append(summaries, &PlanSummary{
+			ID:        plan.ID,
+			Type:      PlanTypeCleanup,
+			Timestamp: plan.Timestamp,
+			Status:    "pending",
+			FileCount: len(plan.Deletions),
+			MoveCount: len(plan.Deletions),
+		})
+	}
+
+	for _, plan := range m.renamePlans {
+		summaries = append(summaries, &PlanSummary{
+			ID:        plan.ID,
+			Type:      PlanTypeRenaming,
+			Timestamp: plan.Timestamp,
+			Status:    "pending",
+			FileCount: len(plan.Renames),
+			MoveCount: len(plan.Renames),
+		})
+	}
+
+	for _, plan := range m.dedupPlans {
+		summaries = append(summaries, &PlanSummary{
+			ID:        plan.ID,
+			Type:      PlanTypeDeduplication,
+			Timestamp: plan.Timestamp,
+			Status:    "pending",
+			FileCount: len(plan.Removals),
+			MoveCount: len(plan.Removals),
+		})
+	}
 	
 	// Sort by timestamp descending (newest first)
 	sort.Slice(summaries, func(i, j int) bool {
@@ -82,8 +127,117 @@ func (m *MemoryOperationStore) ListPlans() ([]*PlanSummary, error) {
 	return summaries, nil
 }
 
+// SaveCleanupPlan implements OperationStore.SaveCleanupPlan
+func (m *MemoryOperationStore) SaveCleanupPlan(ctx context.Context, plan *CleanupPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	planCopy := *plan
+	planCopy.Deletions = make([]Deletion, len(plan.Deletions))
+	copy(planCopy.Deletions, plan.Deletions)
+
+	m.cleanupPlans[plan.ID] = &planCopy
+	return nil
+}
+
+// GetCleanupPlan implements OperationStore.GetCleanupPlan
+func (m *MemoryOperationStore) GetCleanupPlan(ctx context.Context, id string) (*CleanupPlan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plan, exists := m.cleanupPlans[id]
+	if !exists {
+		return nil, fmt.Errorf("cleanup %w: %s", ErrPlanNotFound, id)
+	}
+
+	planCopy := *plan
+	planCopy.Deletions = make([]Deletion, len(plan.Deletions))
+	copy(planCopy.Deletions, plan.Deletions)
+
+	return &planCopy, nil
+}
+
+// SaveRenamingPlan implements OperationStore.SaveRenamingPlan
+func (m *MemoryOperationStore) SaveRenamingPlan(ctx context.Context, plan *RenamingPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	planCopy := *plan
+	planCopy.Renames = make([]Rename, len(plan.Renames))
+	copy(planCopy.Renames, plan.Renames)
+
+	m.renamePlans[plan.ID] = &planCopy
+	return nil
+}
+
+// GetRenamingPlan implements OperationStore.GetRenamingPlan
+func (m *MemoryOperationStore) GetRenamingPlan(ctx context.Context, id string) (*RenamingPlan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plan, exists := m.renamePlans[id]
+	if !exists {
+		return nil, fmt.Errorf("renaming %w: %s", ErrPlanNotFound, id)
+	}
+
+	planCopy := *plan
+	planCopy.Renames = make([]Rename, len(plan.Renames))
+	copy(planCopy.Renames, plan.Renames)
+
+	return &planCopy, nil
+}
+
+// SaveDeduplicationPlan implements OperationStore.SaveDeduplicationPlan
+func (m *MemoryOperationStore) SaveDeduplicationPlan(ctx context.Context, plan *DeduplicationPlan) error {
+	if err := validatePlanID(plan.ID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dedupPlans[plan.ID] = copyDeduplicationPlan(plan)
+	return nil
+}
+
+// GetDeduplicationPlan implements OperationStore.GetDeduplicationPlan
+func (m *MemoryOperationStore) GetDeduplicationPlan(ctx context.Context, id string) (*DeduplicationPlan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	plan, exists := m.dedupPlans[id]
+	if !exists {
+		return nil, fmt.Errorf("deduplication %w: %s", ErrPlanNotFound, id)
+	}
+
+	return copyDeduplicationPlan(plan), nil
+}
+
+// copyDeduplicationPlan copies a plan deeply enough that callers cannot modify stored data
+func copyDeduplicationPlan(plan *DeduplicationPlan) *DeduplicationPlan {
+	planCopy := *plan
+	planCopy.Removals = make([]DuplicateRemoval, len(plan.Removals))
+	copy(planCopy.Removals, plan.Removals)
+
+	planCopy.Groups = make([]DuplicateGroup, len(plan.Groups))
+	for i, group := range plan.Groups {
+		planCopy.Groups[i] = group
+		planCopy.Groups[i].Files = append([]string(nil), group.Files...)
+	}
+
+	return &planCopy
+}
+
 // LogOperation implements OperationStore.LogOperation
-func (m *MemoryOperationStore) LogOperation(op *Operation) error {
+func (m *MemoryOperationStore) LogOperation(ctx context.Context, op *Operation) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
@@ -97,7 +251,7 @@ func (m *MemoryOperationStore) LogOperation(op *Operation) error {
 }
 
 // GetPendingOperations implements OperationStore.GetPendingOperations
-func (m *MemoryOperationStore) GetPendingOperations() ([]*Operation, error) {
+func (m *MemoryOperationStore) GetPendingOperations(ctx context.Context) ([]*Operation, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
@@ -125,7 +279,7 @@ func (m *MemoryOperationStore) GetPendingOperations() ([]*Operation, error) {
 }
 
 // MarkOperationComplete implements OperationStore.MarkOperationComplete
-func (m *MemoryOperationStore) MarkOperationComplete(id string) error {
+func (m *MemoryOperationStore) MarkOperationComplete(ctx context.Context, id string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
@@ -144,7 +298,7 @@ func (m *MemoryOperationStore) MarkOperationComplete(id string) error {
 }
 
 // SaveExecutionLog implements OperationStore.SaveExecutionLog
-func (m *MemoryOperationStore) SaveExecutionLog(log *ExecutionLog) error {
+func (m *MemoryOperationStore) SaveExecutionLog(ctx context.Context, log *ExecutionLog) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
@@ -157,9 +311,9 @@ func (m *MemoryOperationStore) SaveExecutionLog(log *ExecutionLog) error {
 	logCopy.Skipped = make([]SkippedMove, len(log.Skipped))
 	copy(logCopy.Skipped, log.Skipped)
 	
-	// Check if a log for this plan already exists and update it instead of appending
+	// Saving the same execution again updates it; other attempts are kept
 	for i, existingLog := range m.execLogs {
-		if existingLog.PlanID == log.PlanID {
+		if existingLog.Key() == log.Key() {
 			m.execLogs[i] = &logCopy
 			return nil
 		}
@@ -171,7 +325,7 @@ func (m *MemoryOperationStore) SaveExecutionLog(log *ExecutionLog) error {
 }
 
 // GetExecutionHistory implements OperationStore.GetExecutionHistory
-func (m *MemoryOperationStore) GetExecutionHistory() ([]*ExecutionLog, error) {
+func (m *MemoryOperationStore) GetExecutionHistory(ctx context.Context) ([]*ExecutionLog, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	
@@ -188,19 +342,38 @@ func (m *MemoryOperationStore) GetExecutionHistory() ([]*ExecutionLog, error) {
 		logs[i] = &logCopy
 	}
 	
-	sort.Slice(logs, func(i, j int) bool {
+	sort.SliceStable(logs, func(i, j int) bool {
 		return logs[i].Timestamp.After(logs[j].Timestamp)
 	})
 	
 	return logs, nil
 }
 
+// GetExecutionLogs implements OperationStore.GetExecutionLogs
+func (m *MemoryOperationStore) GetExecutionLogs(ctx context.Context, planID string) ([]*ExecutionLog, error) {
+	history, err := m.GetExecutionHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]*ExecutionLog, 0)
+	for _, log := range history {
+		if log.PlanID == planID {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
 // Clear removes all stored data (useful for testing)
 func (m *MemoryOperationStore) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
 	m.plans = make(map[string]*ReorganizationPlan)
+	m.cleanupPlans = make(map[string]*CleanupPlan)
+	m.renamePlans = make(map[string]*RenamingPlan)
+	m.dedupPlans = make(map[string]*DeduplicationPlan)
 	m.operations = make(map[string]*Operation)
 	m.execLogs = make([]*ExecutionLog, 0)
 }
\ No newline at end of file
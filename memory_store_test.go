@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -33,13 +34,13 @@ func TestMemoryOperationStore_SaveAndGetPlan(t *testing.T) {
 	}
 	
 	// Save the plan
-	err := store.SavePlan(plan)
+	err := store.SavePlan(context.Background(), plan)
 	if err != nil {
 		t.Fatalf("Failed to save plan: %v", err)
 	}
 	
 	// Retrieve the plan
-	retrievedPlan, err := store.GetPlan("test-plan-001")
+	retrievedPlan, err := store.GetPlan(context.Background(), "test-plan-001")
 	if err != nil {
 		t.Fatalf("Failed to get plan: %v", err)
 	}
@@ -60,7 +61,7 @@ func TestMemoryOperationStore_SaveAndGetPlan(t *testing.T) {
 	// Test that modifications to retrieved plan don't affect stored plan
 	retrievedPlan.Moves[0].Source = "modified"
 	
-	secondRetrieval, err := store.GetPlan("test-plan-001")
+	secondRetrieval, err := store.GetPlan(context.Background(), "test-plan-001")
 	if err != nil {
 		t.Fatalf("Failed to get plan second time: %v", err)
 	}
@@ -92,18 +93,18 @@ func TestMemoryOperationStore_ListPlans(t *testing.T) {
 	}
 	
 	// Save plans
-	err := store.SavePlan(plan1)
+	err := store.SavePlan(context.Background(), plan1)
 	if err != nil {
 		t.Fatalf("Failed to save plan1: %v", err)
 	}
 	
-	err = store.SavePlan(plan2)
+	err = store.SavePlan(context.Background(), plan2)
 	if err != nil {
 		t.Fatalf("Failed to save plan2: %v", err)
 	}
 	
 	// List plans
-	summaries, err := store.ListPlans()
+	summaries, err := store.ListPlans(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to list plans: %v", err)
 	}
@@ -150,18 +151,18 @@ func TestMemoryOperationStore_Operations(t *testing.T) {
 	}
 	
 	// Log the operations
-	err := store.LogOperation(op1)
+	err := store.LogOperation(context.Background(), op1)
 	if err != nil {
 		t.Fatalf("Failed to log operation 1: %v", err)
 	}
 	
-	err = store.LogOperation(op2)
+	err = store.LogOperation(context.Background(), op2)
 	if err != nil {
 		t.Fatalf("Failed to log operation 2: %v", err)
 	}
 	
 	// Get pending operations
-	pending, err := store.GetPendingOperations()
+	pending, err := store.GetPendingOperations(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get pending operations: %v", err)
 	}
@@ -180,13 +181,13 @@ func TestMemoryOperationStore_Operations(t *testing.T) {
 	}
 	
 	// Mark first operation complete
-	err = store.MarkOperationComplete("op-1")
+	err = store.MarkOperationComplete(context.Background(), "op-1")
 	if err != nil {
 		t.Fatalf("Failed to mark operation complete: %v", err)
 	}
 	
 	// Check that only one operation is pending
-	pending, err = store.GetPendingOperations()
+	pending, err = store.GetPendingOperations(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get pending operations: %v", err)
 	}
@@ -200,13 +201,13 @@ func TestMemoryOperationStore_Operations(t *testing.T) {
 	}
 	
 	// Mark second operation complete
-	err = store.MarkOperationComplete("op-2")
+	err = store.MarkOperationComplete(context.Background(), "op-2")
 	if err != nil {
 		t.Fatalf("Failed to mark operation 2 complete: %v", err)
 	}
 	
 	// Check that no operations are pending
-	pending, err = store.GetPendingOperations()
+	pending, err = store.GetPendingOperations(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get pending operations: %v", err)
 	}
@@ -245,18 +246,18 @@ func TestMemoryOperationStore_ExecutionLog(t *testing.T) {
 	}
 	
 	// Save the execution logs
-	err := store.SaveExecutionLog(log1)
+	err := store.SaveExecutionLog(context.Background(), log1)
 	if err != nil {
 		t.Fatalf("Failed to save execution log 1: %v", err)
 	}
 	
-	err = store.SaveExecutionLog(log2)
+	err = store.SaveExecutionLog(context.Background(), log2)
 	if err != nil {
 		t.Fatalf("Failed to save execution log 2: %v", err)
 	}
 	
 	// Get execution history
-	history, err := store.GetExecutionHistory()
+	history, err := store.GetExecutionHistory(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to get execution history: %v", err)
 	}
@@ -291,7 +292,7 @@ func TestMemoryOperationStore_ExecutionLog(t *testing.T) {
 func TestMemoryOperationStore_GetNonExistentPlan(t *testing.T) {
 	store := NewMemoryOperationStore()
 	
-	_, err := store.GetPlan("non-existent")
+	_, err := store.GetPlan(context.Background(), "non-existent")
 	if err == nil {
 		t.Error("Expected error when getting non-existent plan")
 	}
@@ -300,7 +301,7 @@ func TestMemoryOperationStore_GetNonExistentPlan(t *testing.T) {
 func TestMemoryOperationStore_MarkNonExistentOperationComplete(t *testing.T) {
 	store := NewMemoryOperationStore()
 	
-	err := store.MarkOperationComplete("non-existent")
+	err := store.MarkOperationComplete(context.Background(), "non-existent")
 	if err == nil {
 		t.Error("Expected error when marking non-existent operation complete")
 	}
@@ -311,30 +312,107 @@ func TestMemoryOperationStore_Clear(t *testing.T) {
 	
 	// Add some data
 	plan := &ReorganizationPlan{ID: "test", Timestamp: time.Now()}
-	store.SavePlan(plan)
+	store.SavePlan(context.Background(), plan)
 	
 	op := &Operation{ID: "test", Type: "test", Timestamp: time.Now()}
-	store.LogOperation(op)
+	store.LogOperation(context.Background(), op)
 	
 	log := &ExecutionLog{PlanID: "test", Timestamp: time.Now(), Status: StatusCompleted}
-	store.SaveExecutionLog(log)
+	store.SaveExecutionLog(context.Background(), log)
 	
 	// Clear the store
 	store.Clear()
 	
 	// Verify everything is cleared
-	plans, _ := store.ListPlans()
+	plans, _ := store.ListPlans(context.Background())
 	if len(plans) != 0 {
 		t.Error("Plans should be cleared")
 	}
 	
-	pending, _ := store.GetPendingOperations()
+	pending, _ := store.GetPendingOperations(context.Background())
 	if len(pending) != 0 {
 		t.Error("Operations should be cleared")
 	}
 	
-	history, _ := store.GetExecutionHistory()
+	history, _ := store.GetExecutionHistory(context.Background())
 	if len(history) != 0 {
 		t.Error("Execution history should be cleared")
 	}
-}
\ No newline at end of file
+}
+func TestMemoryOperationStore_CleanupPlans(t *testing.T) {
+	store := NewMemoryOperationStore()
+
+	plan := &CleanupPlan{
+		ID:        "cleanup-1",
+		Timestamp: time.Now(),
+		Deletions: []Deletion{
+			{ID: "del-1", Path: "/old.tmp", Reason: "Temporary file", Size: 4, Hash: "abc"},
+		},
+		Summary: CleanupSummary{FilesDeleted: 1, SpaceFreed: 4},
+	}
+
+	if err := store.SaveCleanupPlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save cleanup plan: %v", err)
+	}
+
+	retrieved, err := store.GetCleanupPlan(context.Background(), "cleanup-1")
+	if err != nil {
+		t.Fatalf("Failed to get cleanup plan: %v", err)
+	}
+	if len(retrieved.Deletions) != 1 || retrieved.Deletions[0].Hash != "abc" {
+		t.Errorf("Unexpected deletions: %+v", retrieved.Deletions)
+	}
+
+	// Cleanup plans are not reorganization plans
+	if _, err := store.GetPlan(context.Background(), "cleanup-1"); err == nil {
+		t.Error("Expected GetPlan to fail for a cleanup plan")
+	}
+
+	store.SavePlan(context.Background(), &ReorganizationPlan{ID: "reorg-1", Timestamp: time.Now()})
+
+	summaries, err := store.ListPlans(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list plans: %v", err)
+	}
+
+	types := make(map[string]PlanType)
+	for _, summary := range summaries {
+		types[summary.ID] = summary.Type
+	}
+	if types["cleanup-1"] != PlanTypeCleanup {
+		t.Errorf("Expected cleanup-1 to be listed as a cleanup plan, got %q", types["cleanup-1"])
+	}
+	if types["reorg-1"] != PlanTypeReorganization {
+		t.Errorf("Expected reorg-1 to be listed as a reorganization plan, got %q", types["reorg-1"])
+	}
+}
+
+func TestMemoryOperationStore_ExecutionAttempts(t *testing.T) {
+	store := NewMemoryOperationStore()
+
+	first := &ExecutionLog{ID: "plan-1-exec-1", PlanID: "plan-1", Timestamp: time.Now().Add(-time.Minute), Status: StatusInProgress}
+	second := &ExecutionLog{ID: "plan-1-exec-2", PlanID: "plan-1", Timestamp: time.Now(), Status: StatusCompleted}
+	other := &ExecutionLog{ID: "plan-2-exec-1", PlanID: "plan-2", Timestamp: time.Now(), Status: StatusCompleted}
+	for _, log := range []*ExecutionLog{first, second, other} {
+		if err := store.SaveExecutionLog(context.Background(), log); err != nil {
+			t.Fatalf("Failed to save execution log: %v", err)
+		}
+	}
+
+	// Saving an attempt again updates it in place
+	first.Status = StatusPartial
+	if err := store.SaveExecutionLog(context.Background(), first); err != nil {
+		t.Fatalf("Failed to update execution log: %v", err)
+	}
+
+	logs, err := store.GetExecutionLogs(context.Background(), "plan-1")
+	if err != nil {
+		t.Fatalf("Failed to get execution logs: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 attempts for plan-1, got %d", len(logs))
+	}
+	if logs[0].ID != "plan-1-exec-2" || logs[1].Status != StatusPartial {
+		t.Errorf("Unexpected attempts: %+v, %+v", logs[0], logs[1])
+	}
+}
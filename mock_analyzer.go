@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -16,7 +17,7 @@ func NewMockAIAnalyzer() *MockAIAnalyzer {
 }
 
 // AnalyzeForReorganization implements AIAnalyzer.AnalyzeForReorganization
-func (m *MockAIAnalyzer) AnalyzeForReorganization(files []FileInfo) (*ReorganizationPlan, error) {
+func (m *MockAIAnalyzer) AnalyzeForReorganization(ctx context.Context, files []FileInfo) (*ReorganizationPlan, error) {
 	planID := fmt.Sprintf("reorg-%d", time.Now().Unix())
 	
 	var moves []Move
@@ -149,63 +150,12 @@ func (m *MockAIAnalyzer) AnalyzeForReorganization(files []FileInfo) (*Reorganiza
 }
 
 // AnalyzeForDuplicates implements AIAnalyzer.AnalyzeForDuplicates
-func (m *MockAIAnalyzer) AnalyzeForDuplicates(files []FileInfo) (*DuplicationReport, error) {
-	hashToFiles := make(map[string][]string)
-	
-	// Group files by hash
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-		
-		hash := file.Hash()
-		if hash != "" {
-			hashToFiles[hash] = append(hashToFiles[hash], file.Path())
-		}
-	}
-	
-	var duplicates []DuplicateGroup
-	var totalDuplicates int
-	var spaceSaved int64
-	
-	// Find duplicate groups (more than 1 file with same hash)
-	for hash, filePaths := range hashToFiles {
-		if len(filePaths) > 1 {
-			// Calculate size from first file
-			var size int64
-			for _, file := range files {
-				if file.Hash() == hash {
-					size = file.Size()
-					break
-				}
-			}
-			
-			duplicates = append(duplicates, DuplicateGroup{
-				Hash:  hash,
-				Files: filePaths,
-				Size:  size,
-			})
-			
-			totalDuplicates += len(filePaths) - 1
-			spaceSaved += size * int64(len(filePaths)-1)
-		}
-	}
-	
-	report := &DuplicationReport{
-		ID:        fmt.Sprintf("dup-%d", time.Now().Unix()),
-		Timestamp: time.Now(),
-		Duplicates: duplicates,
-		Summary: DuplicationSummary{
-			TotalDuplicates: totalDuplicates,
-			SpaceSaved:      spaceSaved,
-		},
-	}
-	
-	return report, nil
+func (m *MockAIAnalyzer) AnalyzeForDuplicates(ctx context.Context, files []FileInfo) (*DuplicationReport, error) {
+	return FindDuplicates(files), nil
 }
 
 // AnalyzeForCleanup implements AIAnalyzer.AnalyzeForCleanup
-func (m *MockAIAnalyzer) AnalyzeForCleanup(files []FileInfo) (*CleanupPlan, error) {
+func (m *MockAIAnalyzer) AnalyzeForCleanup(ctx context.Context, files []FileInfo) (*CleanupPlan, error) {
 	var deletions []Deletion
 	deletionID := 1
 	var totalSize int64
@@ -250,6 +200,7 @@ func (m *MockAIAnalyzer) AnalyzeForCleanup(files []FileInfo) (*CleanupPlan, erro
 				Path:   file.Path(),
 				Reason: reason,
 				Size:   file.Size(),
+				Hash:   file.Hash(),
 			})
 			totalSize += file.Size()
 			deletionID++
@@ -270,7 +221,7 @@ func (m *MockAIAnalyzer) AnalyzeForCleanup(files []FileInfo) (*CleanupPlan, erro
 }
 
 // AnalyzeForRenaming implements AIAnalyzer.AnalyzeForRenaming
-func (m *MockAIAnalyzer) AnalyzeForRenaming(files []FileInfo) (*RenamingPlan, error) {
+func (m *MockAIAnalyzer) AnalyzeForRenaming(ctx context.Context, files []FileInfo) (*RenamingPlan, error) {
 	var renames []Rename
 	renameID := 1
 	
@@ -290,6 +241,8 @@ func (m *MockAIAnalyzer) AnalyzeForRenaming(files []FileInfo) (*RenamingPlan, er
 		if oldName != newName {
 			renames = append(renames, Rename{
 				ID:      fmt.Sprintf("rename-%d", renameID),
+				OldPath: file.Path(),
+				NewPath: filepath.Join(filepath.Dir(file.Path()), newName),
 				OldName: oldName,
 				NewName: newName,
 				Reason:  "Standardize filename to consistent naming convention",
@@ -1,6 +1,7 @@
 package curator
 
 import (
+	"context"
 	"testing"
 )
 
@@ -14,12 +15,12 @@ func TestMockAIAnalyzer_AnalyzeForReorganization(t *testing.T) {
 	fs.AddFile("/video.mp4", []byte("video content"), "video/mp4")
 	fs.AddFile("/script.go", []byte("package main"), "text/plain")
 	
-	files, err := fs.List("/")
+	files, err := fs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
 	
-	plan, err := analyzer.AnalyzeForReorganization(files)
+	plan, err := analyzer.AnalyzeForReorganization(context.Background(), files)
 	if err != nil {
 		t.Fatalf("Failed to analyze for reorganization: %v", err)
 	}
@@ -77,12 +78,12 @@ func TestMockAIAnalyzer_AnalyzeForDuplicates(t *testing.T) {
 	fs.AddFile("/file2.txt", []byte("same content"), "text/plain")
 	fs.AddFile("/file3.txt", []byte("different content"), "text/plain")
 	
-	files, err := fs.List("/")
+	files, err := fs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
 	
-	report, err := analyzer.AnalyzeForDuplicates(files)
+	report, err := analyzer.AnalyzeForDuplicates(context.Background(), files)
 	if err != nil {
 		t.Fatalf("Failed to analyze for duplicates: %v", err)
 	}
@@ -124,12 +125,12 @@ func TestMockAIAnalyzer_AnalyzeForCleanup(t *testing.T) {
 	fs.AddFile("/empty.txt", []byte(""), "text/plain")
 	fs.AddFile("/backup.bak", []byte("backup"), "text/plain")
 	
-	files, err := fs.List("/")
+	files, err := fs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
 	
-	plan, err := analyzer.AnalyzeForCleanup(files)
+	plan, err := analyzer.AnalyzeForCleanup(context.Background(), files)
 	if err != nil {
 		t.Fatalf("Failed to analyze for cleanup: %v", err)
 	}
@@ -172,12 +173,12 @@ func TestMockAIAnalyzer_AnalyzeForRenaming(t *testing.T) {
 	fs.AddFile("/already_good.txt", []byte("text"), "text/plain")
 	fs.AddFile("/file-with-hyphens.txt", []byte("text"), "text/plain")
 	
-	files, err := fs.List("/")
+	files, err := fs.List(context.Background(), "/")
 	if err != nil {
 		t.Fatalf("Failed to list files: %v", err)
 	}
 	
-	plan, err := analyzer.AnalyzeForRenaming(files)
+	plan, err := analyzer.AnalyzeForRenaming(context.Background(), files)
 	if err != nil {
 		t.Fatalf("Failed to analyze for renaming: %v", err)
 	}
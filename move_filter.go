@@ -0,0 +1,163 @@
+package curator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SkipReasonFiltered is the reason recorded for moves a MoveFilter leaves out
+const SkipReasonFiltered = "excluded by filter"
+
+// MoveFilter selects which moves of a reorganization plan an apply runs; the
+// others are recorded as skipped. All of its conditions must hold for a move
+// to be selected. A nil filter selects every move.
+type MoveFilter struct {
+	ids              map[string]bool
+	ranges           []moveIDRange
+	skipDestinations *ExcludeFilter
+	types            map[MoveType]bool
+}
+
+// moveIDRange matches the move IDs prefix+from through prefix+to
+type moveIDRange struct {
+	prefix   string
+	from, to int
+}
+
+// NewMoveFilter builds a filter from the apply flags. only lists move IDs and
+// ranges such as "move-1..move-40"; skipDestinations lists glob patterns for
+// destinations to leave out, with the same syntax as exclude patterns except
+// that, as in .gitignore, a pattern with a "/" in it is anchored to the root;
+// types lists the move types to run. Empty lists do not filter. It returns nil when
+// nothing is filtered.
+func NewMoveFilter(only, skipDestinations, types []string) (*MoveFilter, error) {
+	filter := &MoveFilter{}
+	selective := false
+
+	for _, raw := range only {
+		value := strings.TrimSpace(raw)
+		if value == "" {
+			continue
+		}
+		selective = true
+
+		if from, to, ok := strings.Cut(value, ".."); ok {
+			idRange, err := parseMoveIDRange(from, to)
+			if err != nil {
+				return nil, err
+			}
+			filter.ranges = append(filter.ranges, idRange)
+			continue
+		}
+		if filter.ids == nil {
+			filter.ids = make(map[string]bool)
+		}
+		filter.ids[value] = true
+	}
+
+	var patterns []string
+	for _, raw := range skipDestinations {
+		patterns = append(patterns, anchorDestinationPattern(strings.TrimSpace(raw)))
+	}
+	destinations, err := NewExcludeFilter(strings.Join(patterns, ","))
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination filter: %w", err)
+	}
+	if len(destinations.Patterns()) > 0 {
+		filter.skipDestinations = destinations
+		selective = true
+	}
+
+	for _, raw := range types {
+		value := strings.ToUpper(strings.TrimSpace(raw))
+		if value == "" {
+			continue
+		}
+		switch moveType := MoveType(value); moveType {
+		case CreateFolder, FileMove, FolderMove, RemoveFolder:
+			if filter.types == nil {
+				filter.types = make(map[MoveType]bool)
+			}
+			filter.types[moveType] = true
+			selective = true
+		default:
+			return nil, fmt.Errorf("unknown move type %q: use %s, %s, %s or %s", raw, CreateFolder, FileMove, FolderMove, RemoveFolder)
+		}
+	}
+
+	if !selective {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// anchorDestinationPattern anchors a pattern with a "/" before its end to the
+// root, so "Other/**" means /Other and not every folder named Other. Patterns
+// with no "/", or starting with "**/", still match at any depth.
+func anchorDestinationPattern(pattern string) string {
+	if strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "**/") {
+		return pattern
+	}
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		return "/" + pattern
+	}
+	return pattern
+}
+
+// parseMoveIDRange parses the two ends of an ID range such as move-1..move-40
+func parseMoveIDRange(from, to string) (moveIDRange, error) {
+	fromPrefix, fromNumber, fromOK := splitMoveID(strings.TrimSpace(from))
+	toPrefix, toNumber, toOK := splitMoveID(strings.TrimSpace(to))
+	if !fromOK || !toOK || fromPrefix != toPrefix {
+		return moveIDRange{}, fmt.Errorf("invalid move range %s..%s: both ends need the same prefix and a number, like move-1..move-40", from, to)
+	}
+	if fromNumber > toNumber {
+		return moveIDRange{}, fmt.Errorf("invalid move range %s..%s: the range is backwards", from, to)
+	}
+	return moveIDRange{prefix: fromPrefix, from: fromNumber, to: toNumber}, nil
+}
+
+// splitMoveID splits a move ID into its prefix and trailing number
+func splitMoveID(id string) (string, int, bool) {
+	digits := len(id)
+	for digits > 0 && id[digits-1] >= '0' && id[digits-1] <= '9' {
+		digits--
+	}
+	if digits == len(id) {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(id[digits:])
+	if err != nil {
+		return "", 0, false
+	}
+	return id[:digits], number, true
+}
+
+// Selects reports whether move should run
+func (f *MoveFilter) Selects(move Move) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.ids != nil || f.ranges != nil {
+		listed := f.ids[move.ID]
+		if prefix, number, ok := splitMoveID(move.ID); ok && !listed {
+			for _, r := range f.ranges {
+				if prefix == r.prefix && number >= r.from && number <= r.to {
+					listed = true
+					break
+				}
+			}
+		}
+		if !listed {
+			return false
+		}
+	}
+
+	if f.types != nil && !f.types[move.Type] {
+		return false
+	}
+
+	return !f.skipDestinations.Matches(move.Destination)
+}
@@ -0,0 +1,125 @@
+package curator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMoveFilter_Selects(t *testing.T) {
+	moves := []Move{
+		{ID: "move-1", Destination: "Documents", Type: CreateFolder},
+		{ID: "move-2", Source: "/a.pdf", Destination: "Documents/a.pdf", Type: FileMove},
+		{ID: "move-10", Source: "/b.zip", Destination: "Other/b.zip", Type: FileMove},
+		{ID: "move-11", Source: "/Old", Destination: "/Archive/Old", Type: FolderMove},
+		{ID: "fix-3", Source: "/c.txt", Destination: "/Documents/c.txt", Type: FileMove},
+		{ID: "move-12", Source: "/d.txt", Destination: "/Projects/Other/d.txt", Type: FileMove},
+	}
+
+	tests := []struct {
+		name            string
+		only            []string
+		skipDestination []string
+		onlyType        []string
+		selected        []string
+	}{
+		{name: "range", only: []string{"move-2..move-10"}, selected: []string{"move-2", "move-10"}},
+		{name: "range and ID", only: []string{"move-1..move-2", "fix-3"}, selected: []string{"move-1", "move-2", "fix-3"}},
+		{name: "destination", skipDestination: []string{"Other/**", "/Archive/**"}, selected: []string{"move-1", "move-2", "fix-3", "move-12"}},
+		{name: "destination at any depth", skipDestination: []string{"**/Other/**"}, selected: []string{"move-1", "move-2", "move-11", "fix-3"}},
+		{name: "destination name", skipDestination: []string{"*.txt"}, selected: []string{"move-1", "move-2", "move-10", "move-11"}},
+		{name: "type", onlyType: []string{"file_move"}, selected: []string{"move-2", "move-10", "fix-3", "move-12"}},
+		{name: "combined", only: []string{"move-1..move-11"}, skipDestination: []string{"Other/**"}, onlyType: []string{"FILE_MOVE"}, selected: []string{"move-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewMoveFilter(tt.only, tt.skipDestination, tt.onlyType)
+			if err != nil {
+				t.Fatalf("NewMoveFilter failed: %v", err)
+			}
+
+			var selected []string
+			for _, move := range moves {
+				if filter.Selects(move) {
+					selected = append(selected, move.ID)
+				}
+			}
+			if fmt.Sprint(selected) != fmt.Sprint(tt.selected) {
+				t.Errorf("Expected %v to be selected, got %v", tt.selected, selected)
+			}
+		})
+	}
+}
+
+func TestNewMoveFilter_Invalid(t *testing.T) {
+	filter, err := NewMoveFilter(nil, []string{""}, nil)
+	if err != nil || filter != nil {
+		t.Errorf("Expected no filter without conditions, got %+v, %v", filter, err)
+	}
+
+	invalid := []struct {
+		only, skipDestination, onlyType []string
+	}{
+		{only: []string{"move-1..fix-4"}},
+		{only: []string{"move-9..move-2"}},
+		{only: []string{"move-a..move-b"}},
+		{skipDestination: []string{"Other/[a"}},
+		{onlyType: []string{"COPY"}},
+	}
+	for _, tt := range invalid {
+		if _, err := NewMoveFilter(tt.only, tt.skipDestination, tt.onlyType); err == nil {
+			t.Errorf("Expected an error for %+v", tt)
+		}
+	}
+}
+
+func TestExecutionEngine_ExecutePlan_InSlices(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	plan := &ReorganizationPlan{ID: "sliced-plan", Timestamp: time.Now()}
+	for i := 1; i <= 4; i++ {
+		source := fmt.Sprintf("/file%d.txt", i)
+		fs.AddFile(source, []byte("content"), "text/plain")
+		plan.Moves = append(plan.Moves, Move{ID: fmt.Sprintf("move-%d", i), Source: source, Destination: "/Docs" + source, Type: FileMove})
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	first, _ := NewMoveFilter([]string{"move-1..move-2"}, nil, nil)
+	execLog, err := engine.ExecutePlanWithOptions(context.Background(), plan.ID, ExecuteOptions{Filter: first})
+	if err != nil {
+		t.Fatalf("Failed to execute first slice: %v", err)
+	}
+	if len(execLog.Completed) != 2 || len(execLog.Skipped) != 2 || execLog.Status != StatusPartial {
+		t.Fatalf("Expected 2 completed and 2 filtered moves, got %+v", execLog)
+	}
+	if execLog.Skipped[0].Reason != SkipReasonFiltered {
+		t.Errorf("Expected reason %q, got %q", SkipReasonFiltered, execLog.Skipped[0].Reason)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/file3.txt"); !exists {
+		t.Error("Filtered moves must not run")
+	}
+
+	// Resuming with the next slice runs the moves the first filter left out
+	second, _ := NewMoveFilter([]string{"move-3..move-4"}, nil, nil)
+	execLog, err = engine.ExecutePlanWithOptions(context.Background(), plan.ID, ExecuteOptions{Resume: true, Filter: second})
+	if err != nil {
+		t.Fatalf("Failed to execute second slice: %v", err)
+	}
+	if len(execLog.Completed) != 4 || len(execLog.Skipped) != 0 || execLog.Status != StatusCompleted {
+		t.Errorf("Expected the whole plan to be completed, got %+v", execLog)
+	}
+
+	// Filters need moves to look at
+	if err := store.SaveCleanupPlan(context.Background(), &CleanupPlan{ID: "cleanup-1", Deletions: []Deletion{{ID: "delete-1", Path: "/x"}}}); err != nil {
+		t.Fatalf("Failed to save cleanup plan: %v", err)
+	}
+	if _, err := engine.ExecutePlanWithOptions(context.Background(), "cleanup-1", ExecuteOptions{Filter: first}); err == nil {
+		t.Error("Expected filters to be refused for a cleanup plan")
+	}
+}
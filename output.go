@@ -0,0 +1,121 @@
+package curator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat is how commands print their results
+type OutputFormat string
+
+const (
+	OutputText OutputFormat = "text"
+	OutputJSON OutputFormat = "json"
+	OutputYAML OutputFormat = "yaml"
+)
+
+// OutputAPIVersion identifies the schema of machine-readable output. Fields
+// are only added within a version; renaming or removing one bumps it.
+const OutputAPIVersion = "curator/v1"
+
+// OutputKind names the type of the data in an OutputEnvelope
+type OutputKind string
+
+const (
+	KindReorganizationPlan OutputKind = "ReorganizationPlan"
+	KindPlanPreview        OutputKind = "PlanPreview"
+	KindPlanValidation     OutputKind = "PlanValidation"
+	KindExecutionLog       OutputKind = "ExecutionLog"
+	KindExecutionLogList   OutputKind = "ExecutionLogList"
+	KindPlanSummaryList    OutputKind = "PlanSummaryList"
+	KindDuplicationReport  OutputKind = "DuplicationReport"
+	KindDeduplicationPlan  OutputKind = "DeduplicationPlan"
+	KindCleanupPlan        OutputKind = "CleanupPlan"
+	KindRenamingPlan       OutputKind = "RenamingPlan"
+	KindRecoveryReport     OutputKind = "RecoveryReport"
+	KindStoreReport        OutputKind = "StoreReport"
+)
+
+// OutputEnvelope wraps every machine-readable result so consumers can check
+// what they are reading before they parse it
+type OutputEnvelope struct {
+	APIVersion string      `json:"apiVersion"`
+	Kind       OutputKind  `json:"kind"`
+	Data       interface{} `json:"data"`
+}
+
+// PlanPreview is a reorganization plan together with its simulation
+type PlanPreview struct {
+	Plan       *ReorganizationPlan `json:"plan"`
+	Simulation *PlanSimulation     `json:"simulation"`
+}
+
+// ParseOutputFormat checks the value of the --output flag
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch format := OutputFormat(value); format {
+	case OutputText, OutputJSON, OutputYAML:
+		return format, nil
+	case "":
+		return OutputText, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q: use text, json or yaml", value)
+	}
+}
+
+// WriteOutput writes data wrapped in an OutputEnvelope as JSON or YAML. The
+// YAML document has the same fields, in the same order, as the JSON one.
+func WriteOutput(w io.Writer, format OutputFormat, kind OutputKind, data interface{}) error {
+	envelope := OutputEnvelope{APIVersion: OutputAPIVersion, Kind: kind, Data: data}
+
+	encoded, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+
+	switch format {
+	case OutputJSON:
+		if _, err := w.Write(append(encoded, '\n')); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+
+	case OutputYAML:
+		// JSON is valid YAML, so parse it back as a node tree to keep the JSON
+		// field names and order, then print it in block style
+		var document yaml.Node
+		if err := yaml.Unmarshal(encoded, &document); err != nil {
+			return fmt.Errorf("failed to convert %s to YAML: %w", kind, err)
+		}
+		clearYAMLStyle(&document)
+
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&document); err != nil {
+			return fmt.Errorf("failed to encode %s as YAML: %w", kind, err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to encode %s as YAML: %w", kind, err)
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%s output is not machine-readable", format)
+	}
+}
+
+// clearYAMLStyle drops the flow and quoting styles a node tree picked up from
+// its JSON source; the encoder still quotes strings that need it
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
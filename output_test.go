@@ -0,0 +1,101 @@
+package curator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteOutput_JSON(t *testing.T) {
+	execLog := &ExecutionLog{
+		ID:        "plan-1-exec-1",
+		PlanID:    "plan-1",
+		Timestamp: time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPartial,
+		Completed: []CompletedMove{{MoveID: "move-1", Timestamp: time.Date(2024, 10, 27, 12, 0, 1, 0, time.UTC)}},
+		Skipped:   []SkippedMove{{MoveID: "move-2", Reason: "Conflict: destination already exists"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOutput(&buf, OutputJSON, KindExecutionLog, execLog); err != nil {
+		t.Fatalf("WriteOutput failed: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded["apiVersion"] != OutputAPIVersion || decoded["kind"] != string(KindExecutionLog) {
+		t.Errorf("Unexpected envelope: %v", decoded)
+	}
+
+	// Field names are part of the schema
+	data := decoded["data"].(map[string]interface{})
+	for _, field := range []string{"id", "planId", "timestamp", "resumedFrom", "status", "completed", "failed", "skipped"} {
+		if _, ok := data[field]; !ok {
+			t.Errorf("Expected field %q in %v", field, data)
+		}
+	}
+	if _, ok := data["endTime"]; ok {
+		t.Error("An unfinished execution should have no endTime")
+	}
+	completed := data["completed"].([]interface{})[0].(map[string]interface{})
+	if completed["moveId"] != "move-1" {
+		t.Errorf("Expected camelCase step fields, got %v", completed)
+	}
+}
+
+func TestWriteOutput_YAML(t *testing.T) {
+	plan := &ReorganizationPlan{
+		ID:        "plan-1",
+		Timestamp: time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC),
+		Moves:     []Move{{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Reason: "yes: it's a doc", Type: FileMove}},
+		Rationale: "true",
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOutput(&buf, OutputYAML, KindReorganizationPlan, plan); err != nil {
+		t.Fatalf("WriteOutput failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"apiVersion: curator/v1\nkind: ReorganizationPlan\ndata:\n  id: plan-1\n",
+		"    - id: move-1\n      source: /a.txt\n",
+		"reason: 'yes: it''s a doc'",
+		// A string that looks like a boolean stays a string
+		`rationale: "true"`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected YAML to contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for value, want := range map[string]OutputFormat{"": OutputText, "text": OutputText, "json": OutputJSON, "yaml": OutputYAML} {
+		format, err := ParseOutputFormat(value)
+		if err != nil || format != want {
+			t.Errorf("ParseOutputFormat(%q) = %q, %v; want %q", value, format, err, want)
+		}
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestStoredRecordsWithGoFieldNamesStillLoad(t *testing.T) {
+	// Records saved before the types had JSON tags use Go field names
+	legacy := `{"ID": "plan-1", "Moves": [{"ID": "move-1", "Source": "/a.txt", "Destination": "/Docs/a.txt", "Type": "FILE_MOVE", "FileCount": 1}], "Summary": {"FilesMoved": 1}}`
+
+	var plan ReorganizationPlan
+	if err := json.Unmarshal([]byte(legacy), &plan); err != nil {
+		t.Fatalf("Failed to decode legacy plan: %v", err)
+	}
+	if plan.ID != "plan-1" || plan.Moves[0].Destination != "/Docs/a.txt" || plan.Moves[0].FileCount != 1 || plan.Summary.FilesMoved != 1 {
+		t.Errorf("Legacy plan decoded incorrectly: %+v", plan)
+	}
+}
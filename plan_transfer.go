@@ -0,0 +1,326 @@
+package curator
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ExportFormat is the file format of an exported plan
+type ExportFormat string
+
+const (
+	// ExportJSON writes the plan in the versioned output envelope, which
+	// ExecuteImport reads back
+	ExportJSON ExportFormat = "json"
+	// ExportCSV writes one row per operation for review in a spreadsheet
+	ExportCSV ExportFormat = "csv"
+)
+
+// ImportOptions holds options specific to the import command
+type ImportOptions struct {
+	NewID bool // Register the plan under a fresh ID instead of the one in the file
+}
+
+// planKinds maps each plan type to the output kind it is exported as
+var planKinds = map[PlanType]OutputKind{
+	PlanTypeReorganization: KindReorganizationPlan,
+	PlanTypeCleanup:        KindCleanupPlan,
+	PlanTypeRenaming:       KindRenamingPlan,
+	PlanTypeDeduplication:  KindDeduplicationPlan,
+}
+
+// planIDPrefixes is how generated IDs of each plan type start
+var planIDPrefixes = map[PlanType]string{
+	PlanTypeReorganization: "reorg",
+	PlanTypeCleanup:        "cleanup",
+	PlanTypeRenaming:       "rename",
+	PlanTypeDeduplication:  "dedup",
+}
+
+// planIDPattern is what an imported plan ID may look like. Stores use IDs in
+// file names, so separators and other special characters are not allowed.
+var planIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ExecuteExport writes a saved plan of any type to w
+func ExecuteExport(ctx context.Context, opts CommandOptions, planID string, format ExportFormat, w io.Writer) error {
+	planType, err := FindPlanType(ctx, opts.Store, planID)
+	if err != nil {
+		return err
+	}
+	plan, err := loadPlan(ctx, opts.Store, planType, planID)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case ExportJSON, "":
+		return WriteOutput(w, OutputJSON, planKinds[planType], plan)
+	case ExportCSV:
+		return writePlanCSV(w, plan)
+	default:
+		return fmt.Errorf("unknown export format %q: use json or csv", format)
+	}
+}
+
+// ExecuteImport reads a plan exported as JSON, validates it and saves it. The
+// plan keeps its ID unless importOpts.NewID is set or it has none; importing
+// over an existing plan, or under an ID that is unsafe as a file name, is
+// refused.
+func ExecuteImport(ctx context.Context, opts CommandOptions, r io.Reader, importOpts ImportOptions) (*PlanSummary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var envelope struct {
+		APIVersion string          `json:"apiVersion"`
+		Kind       OutputKind      `json:"kind"`
+		Data       json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: only JSON exports can be imported: %w", err)
+	}
+	if envelope.APIVersion != OutputAPIVersion {
+		return nil, fmt.Errorf("unsupported plan version %q: expected %s", envelope.APIVersion, OutputAPIVersion)
+	}
+
+	var planType PlanType
+	for candidate, kind := range planKinds {
+		if kind == envelope.Kind {
+			planType = candidate
+		}
+	}
+	if planType == "" {
+		return nil, fmt.Errorf("cannot import a %q: only plans can be imported", envelope.Kind)
+	}
+
+	var plan interface{}
+	switch planType {
+	case PlanTypeCleanup:
+		plan = &CleanupPlan{}
+	case PlanTypeRenaming:
+		plan = &RenamingPlan{}
+	case PlanTypeDeduplication:
+		plan = &DeduplicationPlan{}
+	default:
+		plan = &ReorganizationPlan{}
+	}
+	if err := json.Unmarshal(envelope.Data, plan); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", envelope.Kind, err)
+	}
+
+	if err := validateImportedPlan(ctx, opts, plan); err != nil {
+		return nil, err
+	}
+
+	planID := planIDOf(plan)
+	if importOpts.NewID || planID == "" {
+		if planID, err = newPlanID(ctx, opts.Store, planType); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := validatePlanID(planID); err != nil {
+			return nil, err
+		}
+		_, err := FindPlanType(ctx, opts.Store, planID)
+		if err == nil {
+			return nil, fmt.Errorf("plan %s already exists: import it with a new ID instead", planID)
+		}
+		if !errors.Is(err, ErrPlanNotFound) {
+			return nil, fmt.Errorf("failed to check for an existing plan %s: %w", planID, err)
+		}
+	}
+
+	summary := &PlanSummary{ID: planID, Type: planType, Status: "pending"}
+	switch p := plan.(type) {
+	case *ReorganizationPlan:
+		p.ID = planID
+		summary.Timestamp, summary.MoveCount = p.Timestamp, len(p.Moves)
+		err = opts.Store.SavePlan(ctx, p)
+	case *CleanupPlan:
+		p.ID = planID
+		summary.Timestamp, summary.FileCount = p.Timestamp, len(p.Deletions)
+		err = opts.Store.SaveCleanupPlan(ctx, p)
+	case *RenamingPlan:
+		p.ID = planID
+		summary.Timestamp, summary.FileCount = p.Timestamp, len(p.Renames)
+		err = opts.Store.SaveRenamingPlan(ctx, p)
+	case *DeduplicationPlan:
+		p.ID = planID
+		summary.Timestamp, summary.FileCount = p.Timestamp, len(p.Removals)
+		err = opts.Store.SaveDeduplicationPlan(ctx, p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save plan: %w", err)
+	}
+
+	return summary, nil
+}
+
+// loadPlan gets a saved plan of the given type
+func loadPlan(ctx context.Context, store OperationStore, planType PlanType, planID string) (interface{}, error) {
+	var plan interface{}
+	var err error
+	switch planType {
+	case PlanTypeCleanup:
+		plan, err = store.GetCleanupPlan(ctx, planID)
+	case PlanTypeRenaming:
+		plan, err = store.GetRenamingPlan(ctx, planID)
+	case PlanTypeDeduplication:
+		plan, err = store.GetDeduplicationPlan(ctx, planID)
+	default:
+		plan, err = store.GetPlan(ctx, planID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	return plan, nil
+}
+
+// planIDOf returns the ID of any kind of plan
+func planIDOf(plan interface{}) string {
+	switch p := plan.(type) {
+	case *ReorganizationPlan:
+		return p.ID
+	case *CleanupPlan:
+		return p.ID
+	case *RenamingPlan:
+		return p.ID
+	case *DeduplicationPlan:
+		return p.ID
+	}
+	return ""
+}
+
+// validatePlanID rejects plan IDs that could not safely be used as a file name
+func validatePlanID(id string) error {
+	if id == "." || id == ".." || !planIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid plan ID %q: only letters, digits, '.', '_' and '-' are allowed", id)
+	}
+	return nil
+}
+
+// newPlanID generates an ID for planType that no saved plan uses yet
+func newPlanID(ctx context.Context, store OperationStore, planType PlanType) (string, error) {
+	for n := time.Now().Unix(); ; n++ {
+		id := fmt.Sprintf("%s-%d", planIDPrefixes[planType], n)
+		_, err := FindPlanType(ctx, store, id)
+		if errors.Is(err, ErrPlanNotFound) {
+			return id, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check plan ID %s: %w", id, err)
+		}
+	}
+}
+
+// validateImportedPlan checks a plan before it is saved. Reorganization plans
+// are validated against the current filesystem like freshly generated ones;
+// other plans are checked for missing fields and unsafe paths.
+func validateImportedPlan(ctx context.Context, opts CommandOptions, plan interface{}) error {
+	if reorganization, ok := plan.(*ReorganizationPlan); ok {
+		files, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", nil, opts.Progress)
+		if err != nil {
+			return fmt.Errorf("failed to scan filesystem: %w", err)
+		}
+		validation := ValidatePlan(reorganization, files)
+		if validation.HasErrors() {
+			return &PlanValidationError{Validation: validation}
+		}
+		return nil
+	}
+
+	type step struct {
+		id    string
+		paths []string
+	}
+	var steps []step
+	switch p := plan.(type) {
+	case *CleanupPlan:
+		for _, deletion := range p.Deletions {
+			steps = append(steps, step{deletion.ID, []string{deletion.Path}})
+		}
+	case *RenamingPlan:
+		for _, rename := range p.Renames {
+			if path.Dir(path.Clean(rename.OldPath)) != path.Dir(path.Clean(rename.NewPath)) {
+				return fmt.Errorf("invalid plan: %s renames %s into another folder", rename.ID, rename.OldPath)
+			}
+			steps = append(steps, step{rename.ID, []string{rename.OldPath, rename.NewPath}})
+		}
+	case *DeduplicationPlan:
+		for _, removal := range p.Removals {
+			if removal.Path == removal.KeeperPath {
+				return fmt.Errorf("invalid plan: %s removes the copy it keeps", removal.ID)
+			}
+			paths := []string{removal.Path, removal.KeeperPath}
+			if removal.Destination != "" || p.Action == DuplicateQuarantine {
+				paths = append(paths, removal.Destination)
+			}
+			steps = append(steps, step{removal.ID, paths})
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, s := range steps {
+		if s.id == "" {
+			return fmt.Errorf("invalid plan: operation %d has no ID", i+1)
+		}
+		if seen[s.id] {
+			return fmt.Errorf("invalid plan: operation ID %s is used more than once", s.id)
+		}
+		seen[s.id] = true
+
+		for _, p := range s.paths {
+			if p == "" {
+				return fmt.Errorf("invalid plan: %s is missing a path", s.id)
+			}
+			if hasTraversal(p) {
+				return fmt.Errorf("invalid plan: %s has a path containing '..': %s", s.id, p)
+			}
+		}
+	}
+	return nil
+}
+
+// writePlanCSV writes one row per operation of a plan, with the plan ID on
+// every row so rows stay attributable when sheets are combined
+func writePlanCSV(w io.Writer, plan interface{}) error {
+	var rows [][]string
+	switch p := plan.(type) {
+	case *ReorganizationPlan:
+		rows = append(rows, []string{"planId", "id", "type", "source", "destination", "reason", "fileCount"})
+		for _, move := range p.Moves {
+			rows = append(rows, []string{p.ID, move.ID, string(move.Type), move.Source, move.Destination, move.Reason, strconv.Itoa(move.FileCount)})
+		}
+	case *CleanupPlan:
+		rows = append(rows, []string{"planId", "id", "path", "reason", "size", "hash"})
+		for _, deletion := range p.Deletions {
+			rows = append(rows, []string{p.ID, deletion.ID, deletion.Path, deletion.Reason, strconv.FormatInt(deletion.Size, 10), deletion.Hash})
+		}
+	case *RenamingPlan:
+		rows = append(rows, []string{"planId", "id", "oldPath", "newPath", "oldName", "newName", "reason"})
+		for _, rename := range p.Renames {
+			rows = append(rows, []string{p.ID, rename.ID, rename.OldPath, rename.NewPath, rename.OldName, rename.NewName, rename.Reason})
+		}
+	case *DeduplicationPlan:
+		rows = append(rows, []string{"planId", "id", "path", "keeperPath", "hash", "size", "destination"})
+		for _, removal := range p.Removals {
+			rows = append(rows, []string{p.ID, removal.ID, removal.Path, removal.KeeperPath, removal.Hash, strconv.FormatInt(removal.Size, 10), removal.Destination})
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package curator
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	source := CommandOptions{FileSystem: fs, Store: NewMemoryOperationStore(), Reporter: NewReporter()}
+
+	plan := &ReorganizationPlan{
+		ID:        "reorg-1",
+		Timestamp: time.Date(2024, 10, 27, 12, 0, 0, 0, time.UTC),
+		Moves: []Move{
+			{ID: "move-1", Destination: "/Text", Type: CreateFolder},
+			{ID: "move-2", Source: "/a.txt", Destination: "/Text/a.txt", Type: FileMove, Reason: "Text file", FileCount: 1},
+		},
+		Rationale: "Group text files",
+	}
+	if err := source.Store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	var exported bytes.Buffer
+	if err := ExecuteExport(context.Background(), source, "reorg-1", ExportJSON, &exported); err != nil {
+		t.Fatalf("ExecuteExport failed: %v", err)
+	}
+
+	// Another machine with the same files imports it under the same ID
+	target := CommandOptions{FileSystem: fs, Store: NewMemoryOperationStore(), Reporter: NewReporter()}
+	summary, err := ExecuteImport(context.Background(), target, bytes.NewReader(exported.Bytes()), ImportOptions{})
+	if err != nil {
+		t.Fatalf("ExecuteImport failed: %v", err)
+	}
+	if summary.ID != "reorg-1" || summary.Type != PlanTypeReorganization || summary.MoveCount != 2 {
+		t.Errorf("Unexpected import summary: %+v", summary)
+	}
+	imported, err := target.Store.GetPlan(context.Background(), "reorg-1")
+	if err != nil {
+		t.Fatalf("Imported plan not saved: %v", err)
+	}
+	if imported.Rationale != plan.Rationale || imported.Moves[1].Destination != "/Text/a.txt" || !imported.Timestamp.Equal(plan.Timestamp) {
+		t.Errorf("Imported plan differs from the export: %+v", imported)
+	}
+
+	// Importing it again would overwrite the plan, unless it gets a new ID
+	if _, err := ExecuteImport(context.Background(), target, bytes.NewReader(exported.Bytes()), ImportOptions{}); err == nil {
+		t.Error("Expected importing over an existing plan to fail")
+	}
+	summary, err = ExecuteImport(context.Background(), target, bytes.NewReader(exported.Bytes()), ImportOptions{NewID: true})
+	if err != nil {
+		t.Fatalf("ExecuteImport with a new ID failed: %v", err)
+	}
+	if summary.ID == "reorg-1" || !strings.HasPrefix(summary.ID, "reorg-") {
+		t.Errorf("Expected a new reorg- ID, got %s", summary.ID)
+	}
+	if _, err := target.Store.GetPlan(context.Background(), summary.ID); err != nil {
+		t.Errorf("Plan with new ID not saved: %v", err)
+	}
+}
+
+func TestExecuteImport_RejectsInvalidPlans(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	opts := CommandOptions{FileSystem: fs, Store: NewMemoryOperationStore(), Reporter: NewReporter()}
+
+	tests := []struct {
+		name  string
+		kind  OutputKind
+		data  interface{}
+		check func(err error) bool
+	}{
+		{
+			name: "hand-edited move of a missing file",
+			kind: KindReorganizationPlan,
+			data: &ReorganizationPlan{ID: "reorg-2", Moves: []Move{{ID: "move-1", Source: "/gone.txt", Destination: "/Text/gone.txt", Type: FileMove}}},
+			check: func(err error) bool {
+				var validationErr *PlanValidationError
+				return errors.As(err, &validationErr)
+			},
+		},
+		{
+			name: "rename into another folder",
+			kind: KindRenamingPlan,
+			data: &RenamingPlan{ID: "rename-2", Renames: []Rename{{ID: "rename-1", OldPath: "/a.txt", NewPath: "/Docs/a.txt"}}},
+		},
+		{
+			name: "deletion outside the tree",
+			kind: KindCleanupPlan,
+			data: &CleanupPlan{ID: "cleanup-2", Deletions: []Deletion{{ID: "delete-1", Path: "/../etc/passwd"}}},
+		},
+		{
+			name: "duplicate operation IDs",
+			kind: KindCleanupPlan,
+			data: &CleanupPlan{ID: "cleanup-3", Deletions: []Deletion{{ID: "delete-1", Path: "/a.txt"}, {ID: "delete-1", Path: "/b.txt"}}},
+		},
+		{
+			name: "ID escaping the store",
+			kind: KindCleanupPlan,
+			data: &CleanupPlan{ID: "../../outside", Deletions: []Deletion{{ID: "delete-1", Path: "/a.txt"}}},
+		},
+		{
+			name: "not a plan",
+			kind: KindExecutionLog,
+			data: &ExecutionLog{ID: "exec-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteOutput(&buf, OutputJSON, tt.kind, tt.data); err != nil {
+				t.Fatalf("WriteOutput failed: %v", err)
+			}
+			_, err := ExecuteImport(context.Background(), opts, &buf, ImportOptions{})
+			if err == nil {
+				t.Fatal("Expected the import to fail")
+			}
+			if tt.check != nil && !tt.check(err) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	if plans, _ := opts.Store.ListPlans(context.Background()); len(plans) != 0 {
+		t.Errorf("Rejected plans must not be saved, got %+v", plans)
+	}
+	if _, err := ExecuteImport(context.Background(), opts, strings.NewReader(`{"apiVersion": "curator/v0", "kind": "CleanupPlan", "data": {}}`), ImportOptions{}); err == nil {
+		t.Error("Expected an unknown version to be rejected")
+	}
+}
+
+func TestExecuteImport_FileStore(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	root := t.TempDir()
+	store, err := NewFileOperationStore(filepath.Join(root, "store"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	opts := CommandOptions{FileSystem: fs, Store: store, Reporter: NewReporter()}
+
+	importPlan := func(plan *CleanupPlan) error {
+		var buf bytes.Buffer
+		if err := WriteOutput(&buf, OutputJSON, KindCleanupPlan, plan); err != nil {
+			t.Fatalf("WriteOutput failed: %v", err)
+		}
+		_, err := ExecuteImport(context.Background(), opts, &buf, ImportOptions{})
+		return err
+	}
+
+	for _, id := range []string{"../../escaped", "..", "plans/x", `a\b`} {
+		if err := importPlan(&CleanupPlan{ID: id, Deletions: []Deletion{{ID: "delete-1", Path: "/a.txt"}}}); err == nil {
+			t.Errorf("Expected plan ID %q to be rejected", id)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "escaped.json")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written outside the store")
+	}
+
+	// A plan that exists but cannot be read is not overwritten
+	corrupt := filepath.Join(root, "store", "plans", "cleanup-1.json")
+	if err := os.WriteFile(corrupt, []byte("{trunc"), 0644); err != nil {
+		t.Fatalf("Failed to write corrupt plan: %v", err)
+	}
+	if err := importPlan(&CleanupPlan{ID: "cleanup-1", Deletions: []Deletion{{ID: "delete-1", Path: "/a.txt"}}}); err == nil || errors.Is(err, ErrPlanNotFound) {
+		t.Errorf("Expected the unreadable plan to be reported, got %v", err)
+	}
+	if data, _ := os.ReadFile(corrupt); string(data) != "{trunc" {
+		t.Errorf("Expected the corrupt plan to be left alone, got %q", data)
+	}
+}
+
+func TestExecuteExport_CSV(t *testing.T) {
+	opts := CommandOptions{FileSystem: NewMemoryFileSystem(), Store: NewMemoryOperationStore(), Reporter: NewReporter()}
+	plan := &RenamingPlan{
+		ID: "rename-1",
+		Renames: []Rename{
+			{ID: "rename-1", OldPath: "/IMG 1.jpg", NewPath: "/img-1.jpg", OldName: "IMG 1.jpg", NewName: "img-1.jpg", Reason: "Lowercase, dashes"},
+		},
+	}
+	if err := opts.Store.SaveRenamingPlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExecuteExport(context.Background(), opts, "rename-1", ExportCSV, &buf); err != nil {
+		t.Fatalf("ExecuteExport failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected a header and one row, got %v", rows)
+	}
+	if strings.Join(rows[0], ",") != "planId,id,oldPath,newPath,oldName,newName,reason" {
+		t.Errorf("Unexpected header: %v", rows[0])
+	}
+	if rows[1][0] != "rename-1" || rows[1][6] != "Lowercase, dashes" {
+		t.Errorf("Unexpected row: %v", rows[1])
+	}
+
+	if err := ExecuteExport(context.Background(), opts, "rename-1", "xml", &buf); err == nil {
+		t.Error("Expected an unknown export format to fail")
+	}
+}
@@ -0,0 +1,150 @@
+package curator
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressPhase names the long-running stage a ProgressEvent describes
+type ProgressPhase string
+
+const (
+	PhaseScan  ProgressPhase = "scan"  // Listing the filesystem
+	PhaseHash  ProgressPhase = "hash"  // Hashing duplicate candidates
+	PhaseApply ProgressPhase = "apply" // Executing the steps of a plan
+)
+
+// ProgressEvent is a snapshot of a scan, hash or execution. Counters are
+// running totals for the phase; only the ones that belong to the phase are
+// set.
+type ProgressEvent struct {
+	Phase ProgressPhase
+
+	// Scan
+	FoldersScanned int
+	FilesScanned   int
+
+	// Hash
+	BytesHashed int64
+	BytesToHash int64
+
+	// Apply. Completed and Skipped include outcomes carried over on resume.
+	Total     int
+	Completed int
+	Failed    int
+	Skipped   int
+
+	Elapsed time.Duration
+	ETA     time.Duration // Estimated time left, 0 when unknown
+	Done    bool          // Last event of the phase
+}
+
+// Fraction returns how much of the phase is done, between 0 and 1. It
+// reports false for scans, whose size is not known in advance.
+func (e ProgressEvent) Fraction() (float64, bool) {
+	switch e.Phase {
+	case PhaseHash:
+		if e.BytesToHash > 0 {
+			return float64(e.BytesHashed) / float64(e.BytesToHash), true
+		}
+	case PhaseApply:
+		if e.Total > 0 {
+			return float64(e.Completed+e.Failed+e.Skipped) / float64(e.Total), true
+		}
+	}
+	return 0, false
+}
+
+// ProgressReporter receives progress events. Events are sent from the
+// goroutine doing the work, one at a time, so Progress should return quickly.
+type ProgressReporter interface {
+	Progress(event ProgressEvent)
+}
+
+// ProgressFunc lets an ordinary function receive progress events
+type ProgressFunc func(event ProgressEvent)
+
+// Progress implements ProgressReporter
+func (f ProgressFunc) Progress(event ProgressEvent) {
+	f(event)
+}
+
+// reportProgress sends event to p unless p is nil
+func reportProgress(p ProgressReporter, event ProgressEvent) {
+	if p != nil {
+		p.Progress(event)
+	}
+}
+
+// estimateRemaining extrapolates the time left from the pace so far
+func estimateRemaining(elapsed time.Duration, done, remaining int64) time.Duration {
+	if done <= 0 || remaining <= 0 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) / float64(done) * float64(remaining))
+}
+
+// hashProgress counts the bytes hashed through the FileInfos it wraps
+type hashProgress struct {
+	mu       sync.Mutex
+	reporter ProgressReporter
+	start    time.Time
+	hashed   int64
+	toHash   int64
+}
+
+// trackHashing wraps the files FindDuplicates will hash, so hashing them
+// reports progress. Other files are returned as they are.
+func trackHashing(files []FileInfo, reporter ProgressReporter) []FileInfo {
+	if reporter == nil {
+		return files
+	}
+
+	tracker := &hashProgress{reporter: reporter, start: time.Now()}
+	candidates := make(map[string]bool)
+	for _, group := range duplicateCandidates(files) {
+		for _, file := range group {
+			candidates[file.Path()] = true
+			tracker.toHash += file.Size()
+		}
+	}
+
+	tracked := make([]FileInfo, len(files))
+	for i, file := range files {
+		tracked[i] = file
+		if candidates[file.Path()] {
+			tracked[i] = &hashTrackedFile{FileInfo: file, tracker: tracker}
+		}
+	}
+	return tracked
+}
+
+// add records that size more bytes were hashed
+func (h *hashProgress) add(size int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hashed += size
+	elapsed := time.Since(h.start)
+	h.reporter.Progress(ProgressEvent{
+		Phase:       PhaseHash,
+		BytesHashed: h.hashed,
+		BytesToHash: h.toHash,
+		Elapsed:     elapsed,
+		ETA:         estimateRemaining(elapsed, h.hashed, h.toHash-h.hashed),
+		Done:        h.hashed >= h.toHash,
+	})
+}
+
+// hashTrackedFile reports its size to a hashProgress the first time it is hashed
+type hashTrackedFile struct {
+	FileInfo
+	tracker *hashProgress
+	once    sync.Once
+}
+
+func (f *hashTrackedFile) Hash() string {
+	hash := f.FileInfo.Hash()
+	f.once.Do(func() { f.tracker.add(f.Size()) })
+	return hash
+}
@@ -0,0 +1,102 @@
+package curator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordProgress collects progress events per phase
+func recordProgress() (ProgressReporter, map[ProgressPhase][]ProgressEvent) {
+	events := make(map[ProgressPhase][]ProgressEvent)
+	return ProgressFunc(func(event ProgressEvent) {
+		events[event.Phase] = append(events[event.Phase], event)
+	}), events
+}
+
+func TestProgress_ScanAndHash(t *testing.T) {
+	fs, _, _ := newDuplicateFixture(t)
+	progress, events := recordProgress()
+	opts := CommandOptions{FileSystem: fs, Store: NewMemoryOperationStore(), Analyzer: NewMockAIAnalyzer(), Reporter: NewReporter(), Progress: progress}
+
+	if _, err := ExecuteDeduplicate(context.Background(), opts, DeduplicateOptions{DryRun: true}); err != nil {
+		t.Fatalf("ExecuteDeduplicate failed: %v", err)
+	}
+
+	scan := events[PhaseScan]
+	if len(scan) == 0 {
+		t.Fatal("Expected scan progress")
+	}
+	last := scan[len(scan)-1]
+	if !last.Done || last.FilesScanned != 4 || last.FoldersScanned != 6 {
+		t.Errorf("Expected a final scan event with 4 files in 6 folders, got %+v", last)
+	}
+
+	// Only the three copies of the same size are hashed
+	hash := events[PhaseHash]
+	if len(hash) != 3 {
+		t.Fatalf("Expected one hash event per candidate, got %+v", hash)
+	}
+	last = hash[len(hash)-1]
+	if !last.Done || last.BytesHashed != 3*int64(len("holiday photo")) || last.BytesHashed != last.BytesToHash {
+		t.Errorf("Expected every candidate byte to be hashed, got %+v", last)
+	}
+}
+
+func TestProgress_Apply(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+	progress, events := recordProgress()
+	engine.SetProgress(progress)
+
+	plan := &ReorganizationPlan{ID: "progress-plan", Timestamp: time.Now()}
+	for i := 1; i <= 3; i++ {
+		source := fmt.Sprintf("/file%d.txt", i)
+		fs.AddFile(source, []byte("content"), "text/plain")
+		plan.Moves = append(plan.Moves, Move{ID: fmt.Sprintf("move-%d", i), Source: source, Destination: "/Docs" + source, Type: FileMove})
+	}
+	plan.Moves = append(plan.Moves, Move{ID: "move-4", Source: "/missing.txt", Destination: "/Docs/missing.txt", Type: FileMove})
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	if _, err := engine.ExecutePlan(context.Background(), plan.ID, false); err != nil {
+		t.Fatalf("Failed to execute plan: %v", err)
+	}
+
+	apply := events[PhaseApply]
+	if len(apply) != 6 {
+		t.Fatalf("Expected a start event, one per step and a final event, got %+v", apply)
+	}
+	if first := apply[0]; first.Total != 4 || first.Completed != 0 || first.Done {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+	last := apply[len(apply)-1]
+	if !last.Done || last.Completed != 3 || last.Skipped != 1 || last.ETA != 0 {
+		t.Errorf("Unexpected final event: %+v", last)
+	}
+	if fraction, ok := last.Fraction(); !ok || fraction != 1 {
+		t.Errorf("Expected the final event to be complete, got %v", fraction)
+	}
+}
+
+func TestReporter_FormatProgress(t *testing.T) {
+	reporter := NewReporter()
+
+	line := reporter.FormatProgress(ProgressEvent{Phase: PhaseApply, Total: 2000, Completed: 117, Failed: 2, Skipped: 1, ETA: 90 * time.Second})
+	if line != "Applying: 120 of 2000 operations done (2 failed, 1 skipped), about 1m30s left" {
+		t.Errorf("Unexpected apply line: %q", line)
+	}
+
+	line = reporter.FormatProgress(ProgressEvent{Phase: PhaseScan, FilesScanned: 42, FoldersScanned: 7, Elapsed: 2400 * time.Millisecond, Done: true})
+	if line != "Scanning: 42 files in 7 folders, finished in 2s" {
+		t.Errorf("Unexpected scan line: %q", line)
+	}
+
+	if line := reporter.FormatProgress(ProgressEvent{Phase: PhaseHash, BytesHashed: 1536, BytesToHash: 4096}); !strings.HasPrefix(line, "Hashing: 1.5 KB of 4.0 KB") {
+		t.Errorf("Unexpected hash line: %q", line)
+	}
+}
@@ -0,0 +1,244 @@
+package curator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Recover finishes the operations that were logged to the WAL but never marked
+// complete, typically because the process crashed mid-execution. Each operation
+// is first checked against the filesystem: if it already took effect it is only
+// recorded, otherwise it is executed again. Outcomes are added to the execution
+// log that owns the operation. Operations that fail with a real error stay
+// pending so a later recovery can retry them.
+func (e *ExecutionEngine) Recover(ctx context.Context) (*RecoveryReport, error) {
+	pending, err := e.store.GetPendingOperations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending operations: %w", err)
+	}
+
+	report := &RecoveryReport{
+		Operations: make([]RecoveredOperation, 0, len(pending)),
+		Executions: make([]*ExecutionLog, 0),
+	}
+	logs := make(map[string]*ExecutionLog)
+
+	for _, op := range pending {
+		recovered := RecoveredOperation{
+			OperationID: op.ID,
+			ExecutionID: op.ExecutionID,
+			StepID:      op.StepID,
+			Type:        op.Type,
+		}
+
+		outcome, err := e.recoverOperation(ctx, op)
+		recovered.Outcome = outcome
+		if err != nil {
+			recovered.Detail = err.Error()
+		}
+		report.Operations = append(report.Operations, recovered)
+
+		if outcome == RecoveryFailed {
+			continue
+		}
+
+		if op.ExecutionID != "" {
+			execLog, err := e.owningExecutionLog(ctx, logs, op)
+			if err != nil {
+				return nil, err
+			}
+			if execLog != nil {
+				recordRecoveredOutcome(execLog, op.StepID, outcome, recovered.Detail)
+			}
+		}
+
+		if err := e.store.MarkOperationComplete(ctx, op.ID); err != nil {
+			return nil, fmt.Errorf("failed to mark operation complete: %w", err)
+		}
+	}
+
+	executionIDs := make([]string, 0, len(logs))
+	for id := range logs {
+		executionIDs = append(executionIDs, id)
+	}
+	sort.Strings(executionIDs)
+
+	for _, id := range executionIDs {
+		execLog := logs[id]
+
+		// The execution that was interrupted is over; settle its status
+		if execLog.Status == StatusInProgress {
+			totalSteps := len(execLog.Completed) + len(execLog.Failed) + len(execLog.Skipped)
+			if steps, err := e.planSteps(ctx, execLog.PlanID); err == nil {
+				totalSteps = len(steps)
+			}
+			execLog.Status = finalStatus(execLog, totalSteps)
+			execLog.EndTime = time.Now()
+		}
+
+		if err := e.store.SaveExecutionLog(ctx, execLog); err != nil {
+			return nil, fmt.Errorf("failed to update execution log: %w", err)
+		}
+		report.Executions = append(report.Executions, execLog)
+	}
+
+	return report, nil
+}
+
+// ResumePendingOperations recovers pending operations from the WAL after a
+// crash, discarding the report
+func (e *ExecutionEngine) ResumePendingOperations(ctx context.Context) error {
+	_, err := e.Recover(ctx)
+	return err
+}
+
+// recoverOperation brings one pending operation to a final outcome. The error
+// explains skipped and failed outcomes.
+func (e *ExecutionEngine) recoverOperation(ctx context.Context, op *Operation) (RecoveryOutcome, error) {
+	var applied bool
+	var run func() error
+	var err error
+
+	switch op.Type {
+	case "move":
+		var move Move
+		if err := json.Unmarshal(op.Data, &move); err != nil {
+			return RecoveryFailed, fmt.Errorf("failed to unmarshal move data: %w", err)
+		}
+		applied, err = e.moveApplied(ctx, move)
+		run = func() error { return e.executeMove(ctx, move) }
+
+	case "rename":
+		var rename Rename
+		if err := json.Unmarshal(op.Data, &rename); err != nil {
+			return RecoveryFailed, fmt.Errorf("failed to unmarshal rename data: %w", err)
+		}
+		applied, err = e.moveApplied(ctx, Move{Source: rename.OldPath, Destination: rename.NewPath, Type: FileMove})
+		run = func() error { return e.executeRename(ctx, rename) }
+
+	case "delete":
+		var deletion Deletion
+		if err := json.Unmarshal(op.Data, &deletion); err != nil {
+			return RecoveryFailed, fmt.Errorf("failed to unmarshal deletion data: %w", err)
+		}
+		applied, err = e.pathsApplied(ctx, []string{deletion.Path}, nil)
+		run = func() error { return e.executeDeletion(ctx, deletion) }
+
+	case "dedup":
+		var removal DuplicateRemoval
+		if err := json.Unmarshal(op.Data, &removal); err != nil {
+			return RecoveryFailed, fmt.Errorf("failed to unmarshal removal data: %w", err)
+		}
+		present := []string{removal.KeeperPath}
+		if removal.Destination != "" {
+			present = append(present, removal.Destination)
+		}
+		applied, err = e.pathsApplied(ctx, []string{removal.Path}, present)
+		run = func() error { return e.executeDuplicateRemoval(ctx, removal) }
+
+	default:
+		return RecoveryFailed, fmt.Errorf("unknown operation type: %s", op.Type)
+	}
+
+	if err != nil {
+		return RecoveryFailed, fmt.Errorf("failed to check whether the operation was applied: %w", err)
+	}
+	if applied {
+		return RecoveryAlreadyApplied, nil
+	}
+
+	if err := run(); err != nil {
+		if isConflictError(err) {
+			return RecoverySkipped, err
+		}
+		return RecoveryFailed, err
+	}
+	return RecoveryCompleted, nil
+}
+
+// moveApplied reports whether a move already took effect
+func (e *ExecutionEngine) moveApplied(ctx context.Context, move Move) (bool, error) {
+	switch move.Type {
+	case CreateFolder:
+		return e.fs.Exists(ctx, move.Destination)
+	case RemoveFolder:
+		return e.pathsApplied(ctx, []string{move.Destination}, nil)
+	default:
+		return e.pathsApplied(ctx, []string{move.Source}, []string{move.Destination})
+	}
+}
+
+// pathsApplied reports whether every path in gone is missing and every path in
+// present exists
+func (e *ExecutionEngine) pathsApplied(ctx context.Context, gone, present []string) (bool, error) {
+	for _, path := range gone {
+		exists, err := e.fs.Exists(ctx, path)
+		if err != nil || exists {
+			return false, err
+		}
+	}
+	for _, path := range present {
+		exists, err := e.fs.Exists(ctx, path)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// owningExecutionLog loads the execution log an operation belongs to, caching
+// it in logs. It returns nil if the log no longer exists.
+func (e *ExecutionEngine) owningExecutionLog(ctx context.Context, logs map[string]*ExecutionLog, op *Operation) (*ExecutionLog, error) {
+	if execLog, ok := logs[op.ExecutionID]; ok {
+		return execLog, nil
+	}
+
+	attempts, err := e.store.GetExecutionLogs(ctx, op.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution logs: %w", err)
+	}
+	for _, execLog := range attempts {
+		if execLog.ID == op.ExecutionID {
+			logs[op.ExecutionID] = execLog
+			return execLog, nil
+		}
+	}
+	return nil, nil
+}
+
+// recordRecoveredOutcome adds a recovered step to an execution log, unless the
+// log already recorded an outcome for it before the crash
+func recordRecoveredOutcome(execLog *ExecutionLog, stepID string, outcome RecoveryOutcome, detail string) {
+	for _, completed := range execLog.Completed {
+		if completed.MoveID == stepID {
+			return
+		}
+	}
+	for _, skipped := range execLog.Skipped {
+		if skipped.MoveID == stepID {
+			return
+		}
+	}
+	for _, failed := range execLog.Failed {
+		if failed.MoveID == stepID {
+			return
+		}
+	}
+
+	if outcome == RecoverySkipped {
+		execLog.Skipped = append(execLog.Skipped, SkippedMove{
+			MoveID:    stepID,
+			Timestamp: time.Now(),
+			Reason:    fmt.Sprintf("Conflict: %s", detail),
+		})
+		return
+	}
+
+	execLog.Completed = append(execLog.Completed, CompletedMove{
+		MoveID:    stepID,
+		Timestamp: time.Now(),
+	})
+}
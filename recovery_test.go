@@ -0,0 +1,187 @@
+package curator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// simulateCrash saves plan and an in-progress execution of it that completed
+// completedSteps, and logs the given moves to the WAL without completing them
+func simulateCrash(t *testing.T, store OperationStore, plan *ReorganizationPlan, completedSteps []string, pendingMoves []Move) *ExecutionLog {
+	t.Helper()
+
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	execLog := &ExecutionLog{ID: plan.ID + "-exec-1", PlanID: plan.ID, Timestamp: time.Now(), Status: StatusInProgress}
+	for _, stepID := range completedSteps {
+		execLog.Completed = append(execLog.Completed, CompletedMove{MoveID: stepID, Timestamp: time.Now()})
+	}
+	if err := store.SaveExecutionLog(context.Background(), execLog); err != nil {
+		t.Fatalf("Failed to save execution log: %v", err)
+	}
+
+	for _, move := range pendingMoves {
+		data, err := json.Marshal(move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		op := &Operation{
+			ID:          execLog.ID + "-" + move.ID,
+			Type:        "move",
+			Data:        data,
+			Timestamp:   time.Now(),
+			PlanID:      plan.ID,
+			ExecutionID: execLog.ID,
+			StepID:      move.ID,
+		}
+		if err := store.LogOperation(context.Background(), op); err != nil {
+			t.Fatalf("Failed to log operation: %v", err)
+		}
+	}
+
+	return execLog
+}
+
+func TestExecutionEngine_Recover(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	// move-1 finished and was logged; move-2 finished but the crash hit before
+	// it was marked complete; move-3 was logged but never ran
+	fs.AddFile("/Docs/a.txt", []byte("a"), "text/plain")
+	fs.AddFile("/Docs/b.txt", []byte("b"), "text/plain")
+	fs.AddFile("/c.txt", []byte("c"), "text/plain")
+	fs.AddFile("/d.txt", []byte("d"), "text/plain")
+
+	plan := &ReorganizationPlan{
+		ID:        "crashed-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Docs/b.txt", Type: FileMove},
+			{ID: "move-3", Source: "/c.txt", Destination: "/Docs/c.txt", Type: FileMove},
+			{ID: "move-4", Source: "/d.txt", Destination: "/Docs/d.txt", Type: FileMove},
+		},
+	}
+	execLog := simulateCrash(t, store, plan, []string{"move-1"}, plan.Moves[1:3])
+
+	report, err := engine.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+
+	if len(report.Operations) != 2 {
+		t.Fatalf("Expected 2 recovered operations, got %+v", report.Operations)
+	}
+	outcomes := map[string]RecoveryOutcome{}
+	for _, op := range report.Operations {
+		outcomes[op.StepID] = op.Outcome
+	}
+	if outcomes["move-2"] != RecoveryAlreadyApplied {
+		t.Errorf("Expected move-2 to be recognized as already applied, got %s", outcomes["move-2"])
+	}
+	if outcomes["move-3"] != RecoveryCompleted {
+		t.Errorf("Expected move-3 to be completed, got %s", outcomes["move-3"])
+	}
+	if exists, _ := fs.Exists(context.Background(), "/Docs/c.txt"); !exists {
+		t.Error("Expected move-3 to be executed")
+	}
+
+	pending, _ := store.GetPendingOperations(context.Background())
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending operations, got %d", len(pending))
+	}
+
+	// The interrupted execution records the recovered steps; move-4 never ran
+	attempts, err := engine.GetExecutionAttempts(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("Failed to get attempts: %v", err)
+	}
+	recovered := attempts[0]
+	if recovered.ID != execLog.ID || len(recovered.Completed) != 3 {
+		t.Fatalf("Expected the execution log to record 3 completed moves, got %+v", recovered)
+	}
+	if recovered.Status != StatusPartial || recovered.EndTime.IsZero() {
+		t.Errorf("Expected the interrupted execution to be settled as partial, got %s", recovered.Status)
+	}
+	if len(report.Executions) != 1 || report.Executions[0].ID != execLog.ID {
+		t.Errorf("Expected the report to list the updated execution, got %+v", report.Executions)
+	}
+}
+
+func TestExecutionEngine_RecoverKeepsFailedOperationsPending(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	fs := &flakyFileSystem{FileSystem: memFS, failMoves: map[string]bool{"/a.txt": true}}
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	memFS.AddFile("/a.txt", []byte("a"), "text/plain")
+	plan := &ReorganizationPlan{
+		ID:        "flaky-plan",
+		Timestamp: time.Now(),
+		Moves:     []Move{{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove}},
+	}
+	simulateCrash(t, store, plan, nil, plan.Moves)
+
+	report, err := engine.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if report.Operations[0].Outcome != RecoveryFailed || !strings.Contains(report.Operations[0].Detail, "transient") {
+		t.Fatalf("Expected a failed outcome, got %+v", report.Operations[0])
+	}
+	if pending, _ := store.GetPendingOperations(context.Background()); len(pending) != 1 {
+		t.Fatalf("Expected the failed operation to stay pending, got %d", len(pending))
+	}
+
+	// The failure was transient, so recovering again finishes the move
+	report, err = engine.Recover(context.Background())
+	if err != nil {
+		t.Fatalf("Second recover failed: %v", err)
+	}
+	if report.Operations[0].Outcome != RecoveryCompleted {
+		t.Errorf("Expected the retry to complete, got %+v", report.Operations[0])
+	}
+	if pending, _ := store.GetPendingOperations(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected no pending operations, got %d", len(pending))
+	}
+
+	status, err := engine.GetExecutionStatus(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("Failed to get status: %v", err)
+	}
+	if status.Status != StatusCompleted {
+		t.Errorf("Expected the execution to be completed, got %s", status.Status)
+	}
+}
+
+func TestExecuteApply_RefusesWithPendingOperations(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Analyzer: NewMockAIAnalyzer(), Reporter: NewReporter()}
+
+	fs.AddFile("/a.txt", []byte("a"), "text/plain")
+	plan := &ReorganizationPlan{
+		ID:        "blocked-plan",
+		Timestamp: time.Now(),
+		Moves:     []Move{{ID: "move-1", Source: "/a.txt", Destination: "/Docs/a.txt", Type: FileMove}},
+	}
+	simulateCrash(t, store, plan, nil, plan.Moves)
+
+	if _, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{}); err == nil || !strings.Contains(err.Error(), "curator recover") {
+		t.Fatalf("Expected apply to refuse until recovery, got %v", err)
+	}
+
+	if _, err := ExecuteRecover(context.Background(), opts); err != nil {
+		t.Fatalf("Recover failed: %v", err)
+	}
+	if _, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{Resume: true}); err != nil {
+		t.Errorf("Expected apply to work after recovery, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package curator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Naming patterns accepted by the rename command
+const (
+	// PatternConsistentNaming lets the AI analyzer choose names
+	PatternConsistentNaming = "consistent-naming"
+	PatternSnakeCase        = "snake_case"
+	PatternKebabCase        = "kebab-case"
+	PatternLowercase        = "lowercase"
+)
+
+// namingStrategy converts a file name into the name a pattern expects
+type namingStrategy struct {
+	normalize func(name string) string
+	separator string // Used before the numeric suffix when names collide
+}
+
+var namingStrategies = map[string]namingStrategy{
+	PatternSnakeCase: {normalize: func(name string) string { return joinNameWords(name, "_") }, separator: "_"},
+	PatternKebabCase: {normalize: func(name string) string { return joinNameWords(name, "-") }, separator: "-"},
+	PatternLowercase: {normalize: strings.ToLower, separator: "_"},
+}
+
+// NamingPatterns returns the naming patterns accepted by ExecuteRename
+func NamingPatterns() []string {
+	patterns := []string{PatternConsistentNaming}
+	for pattern := range namingStrategies {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns[1:])
+	return patterns
+}
+
+// buildLocalRenamingPlan creates a renaming plan by applying a naming strategy
+// to every file, without consulting the analyzer
+func buildLocalRenamingPlan(files []FileInfo, pattern string) (*RenamingPlan, error) {
+	strategy, ok := namingStrategies[pattern]
+	if !ok {
+		return nil, fmt.Errorf("unknown naming pattern: %s (expected one of %s)", pattern, strings.Join(NamingPatterns(), ", "))
+	}
+
+	var renames []Rename
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		newName := strategy.normalize(file.Name())
+		if newName == file.Name() {
+			continue
+		}
+
+		renames = append(renames, Rename{
+			ID:      fmt.Sprintf("rename-%d", len(renames)+1),
+			OldPath: file.Path(),
+			NewPath: filepath.Join(filepath.Dir(file.Path()), newName),
+			OldName: file.Name(),
+			NewName: newName,
+			Reason:  fmt.Sprintf("Rename to %s", pattern),
+		})
+	}
+
+	return &RenamingPlan{
+		ID:        fmt.Sprintf("rename-%d", time.Now().Unix()),
+		Timestamp: time.Now(),
+		Renames:   renames,
+		Summary: RenamingSummary{
+			FilesRenamed: len(renames),
+			Pattern:      pattern,
+		},
+	}, nil
+}
+
+// finalizeRenamingPlan makes a renaming plan safe to execute and save. The plan
+// gets a fresh ID unused in store, every rename gets full paths within the
+// original folder, renames of unknown files are dropped, and names that would
+// collide with an existing file or with another rename in the same folder get a
+// numeric suffix ("report_2.pdf").
+func finalizeRenamingPlan(ctx context.Context, store OperationStore, plan *RenamingPlan, files []FileInfo, separator string) error {
+	id, err := newPlanID(ctx, store, PlanTypeRenaming)
+	if err != nil {
+		return err
+	}
+	plan.ID = id
+
+	byPath := make(map[string]FileInfo, len(files))
+	byName := make(map[string][]FileInfo)
+	taken := make(map[string]map[string]bool) // folder -> names in use
+	for _, file := range files {
+		byPath[file.Path()] = file
+		byName[file.Name()] = append(byName[file.Name()], file)
+
+		dir := filepath.Dir(file.Path())
+		if taken[dir] == nil {
+			taken[dir] = make(map[string]bool)
+		}
+		taken[dir][file.Name()] = true
+	}
+
+	renames := make([]Rename, 0, len(plan.Renames))
+	for _, rename := range plan.Renames {
+		// Analyzers that only return names are resolved when the name is unambiguous
+		file, ok := byPath[rename.OldPath]
+		if !ok && rename.OldPath == "" && len(byName[rename.OldName]) == 1 {
+			file, ok = byName[rename.OldName][0], true
+		}
+		if !ok || file.IsDir() {
+			continue
+		}
+
+		newName := rename.NewName
+		if newName == "" {
+			newName = filepath.Base(rename.NewPath)
+		}
+		if newName == "" || newName == "." || strings.ContainsAny(newName, "/\\") || newName == file.Name() {
+			continue
+		}
+
+		dir := filepath.Dir(file.Path())
+		newName = uniqueName(newName, taken[dir], separator)
+		taken[dir][newName] = true
+
+		rename.OldPath = file.Path()
+		rename.OldName = file.Name()
+		rename.NewName = newName
+		rename.NewPath = filepath.Join(dir, newName)
+		renames = append(renames, rename)
+	}
+
+	plan.Renames = renames
+	plan.Summary.FilesRenamed = len(renames)
+	return nil
+}
+
+// uniqueName appends the smallest numeric suffix that makes name unused
+func uniqueName(name string, taken map[string]bool, separator string) string {
+	if !taken[name] {
+		return name
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s%s%d%s", base, separator, n, ext)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// joinNameWords lowercases the words of a file name and joins them with
+// separator, keeping the extension. Names with no words are left unchanged.
+func joinNameWords(name, separator string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	words := strings.FieldsFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return name
+	}
+
+	return strings.ToLower(strings.Join(words, separator) + ext)
+}
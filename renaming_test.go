@@ -0,0 +1,112 @@
+package curator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected string
+	}{
+		{PatternSnakeCase, "My Document (final).PDF", "my_document_final.pdf"},
+		{PatternSnakeCase, "already_good.txt", "already_good.txt"},
+		{PatternSnakeCase, "Quarterly-Report 2024.xlsx", "quarterly_report_2024.xlsx"},
+		{PatternKebabCase, "My Document (final).pdf", "my-document-final.pdf"},
+		{PatternKebabCase, "snake_case_name.go", "snake-case-name.go"},
+		{PatternLowercase, "My Document.PDF", "my document.pdf"},
+		{PatternSnakeCase, ".bashrc", ".bashrc"},
+		{PatternSnakeCase, "Café Menu.txt", "café_menu.txt"},
+	}
+
+	for _, tt := range tests {
+		if got := namingStrategies[tt.pattern].normalize(tt.name); got != tt.expected {
+			t.Errorf("%s(%q) = %q, expected %q", tt.pattern, tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestBuildLocalRenamingPlan_UnknownPattern(t *testing.T) {
+	if _, err := buildLocalRenamingPlan(nil, "SCREAMING_CASE"); err == nil {
+		t.Error("Expected error for unknown naming pattern")
+	}
+}
+
+func TestFinalizeRenamingPlan_ResolvesCollisions(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/Docs/My Report.pdf", []byte("a"), "application/pdf")
+	fs.AddFile("/Docs/my-report.pdf", []byte("b"), "application/pdf")
+	fs.AddFile("/Docs/MY REPORT.pdf", []byte("c"), "application/pdf")
+	fs.AddFile("/Docs/my_report.pdf", []byte("d"), "application/pdf")
+	fs.AddFile("/Other/My Report.pdf", []byte("e"), "application/pdf")
+
+	files, err := getAllFilesRecursively(context.Background(), fs, "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to list files: %v", err)
+	}
+
+	plan, err := buildLocalRenamingPlan(files, PatternSnakeCase)
+	if err != nil {
+		t.Fatalf("Failed to build renaming plan: %v", err)
+	}
+	if err := finalizeRenamingPlan(context.Background(), NewMemoryOperationStore(), plan, files, "_"); err != nil {
+		t.Fatalf("Failed to finalize renaming plan: %v", err)
+	}
+
+	targets := make(map[string]string)
+	for _, rename := range plan.Renames {
+		targets[rename.OldPath] = rename.NewPath
+	}
+
+	// my_report.pdf already exists, so every other file in /Docs gets a suffix
+	seen := make(map[string]bool)
+	for _, oldPath := range []string{"/Docs/My Report.pdf", "/Docs/my-report.pdf", "/Docs/MY REPORT.pdf"} {
+		newPath, ok := targets[oldPath]
+		if !ok {
+			t.Fatalf("Expected %s to be renamed", oldPath)
+		}
+		if newPath == "/Docs/my_report.pdf" || seen[newPath] {
+			t.Errorf("Rename of %s collides at %s", oldPath, newPath)
+		}
+		seen[newPath] = true
+	}
+
+	// Files in other folders do not collide
+	if targets["/Other/My Report.pdf"] != "/Other/my_report.pdf" {
+		t.Errorf("Expected /Other/my_report.pdf, got %s", targets["/Other/My Report.pdf"])
+	}
+
+	if plan.Summary.FilesRenamed != len(plan.Renames) {
+		t.Errorf("Expected summary to count %d renames, got %d", len(plan.Renames), plan.Summary.FilesRenamed)
+	}
+}
+
+func TestFinalizeRenamingPlan_NameOnlyRenames(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/a/Unique File.txt", []byte("a"), "text/plain")
+	fs.AddFile("/a/Same.txt", []byte("b"), "text/plain")
+	fs.AddFile("/b/Same.txt", []byte("c"), "text/plain")
+
+	files, _ := getAllFilesRecursively(context.Background(), fs, "/", nil, nil)
+
+	plan := &RenamingPlan{
+		Renames: []Rename{
+			{ID: "rename-1", OldName: "Unique File.txt", NewName: "unique_file.txt"},
+			{ID: "rename-2", OldName: "Same.txt", NewName: "same.txt"},
+			{ID: "rename-3", OldPath: "/a/Unique File.txt", NewName: "../escape.txt"},
+			{ID: "rename-4", OldPath: "/missing.txt", NewName: "gone.txt"},
+		},
+	}
+	if err := finalizeRenamingPlan(context.Background(), NewMemoryOperationStore(), plan, files, "_"); err != nil {
+		t.Fatalf("Failed to finalize renaming plan: %v", err)
+	}
+
+	if len(plan.Renames) != 1 {
+		t.Fatalf("Expected only the unambiguous rename to remain, got %+v", plan.Renames)
+	}
+	if plan.Renames[0].OldPath != "/a/Unique File.txt" || plan.Renames[0].NewPath != "/a/unique_file.txt" {
+		t.Errorf("Unexpected rename: %+v", plan.Renames[0])
+	}
+}
@@ -3,6 +3,7 @@ package curator
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Reporter handles generating text-based reports for plans and execution results
@@ -21,7 +22,20 @@ func (r *Reporter) FormatReorganizationPlan(plan *ReorganizationPlan) string {
 	b.WriteString("REORGANIZATION PLAN\n")
 	b.WriteString("==================\n")
 	b.WriteString(fmt.Sprintf("Plan ID: %s\n", plan.ID))
-	b.WriteString(fmt.Sprintf("Generated: %s\n\n", plan.Timestamp.Format("2006-01-02 15:04:05")))
+	b.WriteString(fmt.Sprintf("Generated: %s\n", plan.Timestamp.Format("2006-01-02 15:04:05")))
+	if plan.DerivedFrom != "" {
+		approved, rejected := 0, 0
+		for _, move := range plan.Moves {
+			switch move.Approval {
+			case ApprovalApproved:
+				approved++
+			case ApprovalRejected:
+				rejected++
+			}
+		}
+		b.WriteString(fmt.Sprintf("Reviewed from: %s (%d approved, %d rejected)\n", plan.DerivedFrom, approved, rejected))
+	}
+	b.WriteString("\n")
 	
 	// Summary
 	b.WriteString("SUMMARY\n")
@@ -43,6 +57,23 @@ func (r *Reporter) FormatReorganizationPlan(plan *ReorganizationPlan) string {
 		b.WriteString(fmt.Sprintf("%s\n\n", plan.Rationale))
 	}
 	
+	// Repairs made to the analyzer's plan
+	if len(plan.ValidationIssues) > 0 {
+		b.WriteString(fmt.Sprintf("REPAIRED ISSUES (%d)\n", len(plan.ValidationIssues)))
+		b.WriteString("---------------\n")
+		for _, issue := range plan.ValidationIssues {
+			action := "kept"
+			switch {
+			case issue.Severity == SeverityError:
+				action = "dropped"
+			case issue.Code == IssueFolderCreatedLate:
+				action = "moved earlier"
+			}
+			b.WriteString(fmt.Sprintf("⚠️  %s (%s, %s): %s\n", issue.MoveID, issue.Code, action, issue.Message))
+		}
+		b.WriteString("\n")
+	}
+
 	// Operations
 	b.WriteString(fmt.Sprintf("DETAILED OPERATIONS (%d total)\n", len(plan.Moves)))
 	b.WriteString(strings.Repeat("-", 40) + "\n")
@@ -56,12 +87,25 @@ func (r *Reporter) FormatReorganizationPlan(plan *ReorganizationPlan) string {
 			break
 		}
 		
+		if !plan.runsMove(move) {
+			label := "REJECTED"
+			if move.Approval != ApprovalRejected {
+				label = "NOT APPROVED"
+			}
+			b.WriteString(fmt.Sprintf("%d. [%s] %s\n\n", i+1, label, formatReviewMove(move)))
+			continue
+		}
+		
 		switch move.Type {
 		case CreateFolder:
 			createFolderCount++
 			b.WriteString(fmt.Sprintf("%d. CREATE FOLDER: %s\n", i+1, move.Destination))
 			b.WriteString(fmt.Sprintf("   → %s\n\n", move.Reason))
-			
+
+		case RemoveFolder:
+			b.WriteString(fmt.Sprintf("%d. REMOVE FOLDER (if empty): %s\n", i+1, move.Destination))
+			b.WriteString(fmt.Sprintf("   → %s\n\n", move.Reason))
+
 		case FileMove, FolderMove:
 			moveCount++
 			actionType := "MOVE"
@@ -79,11 +123,136 @@ func (r *Reporter) FormatReorganizationPlan(plan *ReorganizationPlan) string {
 	
 	// Instructions
 	b.WriteString(fmt.Sprintf("Type 'curator apply %s' to execute this plan\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator review %s' to approve or reject individual moves\n", plan.ID))
 	b.WriteString(fmt.Sprintf("Type 'curator show-plan %s' to view this plan again\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator export %s > plan.json' to save it as JSON\n", plan.ID))
 	
 	return b.String()
 }
 
+// FormatReviewGroup formats one destination folder of an interactive review
+func (r *Reporter) FormatReviewGroup(plan *ReorganizationPlan, group ReviewGroup, number, total int) string {
+	var b strings.Builder
+
+	folder := reviewFolder(plan.Moves[group.Moves[0]])
+	b.WriteString(fmt.Sprintf("Folder %d/%d: %s (%d moves)\n", number, total, folderLabel(folder), len(group.Moves)))
+	for _, i := range group.Moves {
+		b.WriteString(fmt.Sprintf("  • %s\n", formatReviewMove(plan.Moves[i])))
+	}
+
+	return b.String()
+}
+
+// FormatPlanValidation formats the issues found in a plan as text
+func (r *Reporter) FormatPlanValidation(validation *PlanValidation) string {
+	var b strings.Builder
+
+	b.WriteString("PLAN VALIDATION\n")
+	b.WriteString("===============\n")
+	b.WriteString(fmt.Sprintf("Plan ID: %s\n", validation.PlanID))
+
+	if len(validation.Issues) == 0 {
+		b.WriteString("✅ No issues found\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Errors: %d, Warnings: %d\n\n", len(validation.Errors()), len(validation.Issues)-len(validation.Errors())))
+	for _, issue := range validation.Issues {
+		icon := "⚠️ "
+		if issue.Severity == SeverityError {
+			icon = "❌"
+		}
+		b.WriteString(fmt.Sprintf("%s %s [%s] %s\n", icon, issue.MoveID, issue.Code, issue.Message))
+	}
+
+	return b.String()
+}
+
+// FormatPlanSimulation formats the folder trees before and after a simulated
+// plan. Only folders the plan changes, and their parents, are shown.
+func (r *Reporter) FormatPlanSimulation(sim *PlanSimulation) string {
+	var b strings.Builder
+
+	b.WriteString("FOLDER STRUCTURE CHANGES\n")
+	b.WriteString("------------------------\n")
+
+	if !sim.After.hasChanges() && !sim.Before.hasChanges() {
+		b.WriteString("No folders change\n\n")
+	} else {
+		b.WriteString("Before:\n")
+		writeTree(&b, sim.Before)
+		b.WriteString("\nAfter:\n")
+		writeTree(&b, sim.After)
+		b.WriteString("\n")
+	}
+
+	if skipped := len(sim.Log.Skipped) + len(sim.Log.Failed); skipped > 0 {
+		b.WriteString(fmt.Sprintf("⚠️  %d of the plan's moves would not apply to the current tree:\n", skipped))
+		for _, move := range sim.Log.Skipped {
+			b.WriteString(fmt.Sprintf("   %s: %s\n", move.MoveID, move.Reason))
+		}
+		for _, move := range sim.Log.Failed {
+			b.WriteString(fmt.Sprintf("   %s: %s\n", move.MoveID, move.Error))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// writeTree draws the changed part of a folder tree
+func writeTree(b *strings.Builder, root *TreeNode) {
+	b.WriteString(fmt.Sprintf("%s%s\n", folderLabel(root.Path), treeAnnotation(root)))
+
+	var walk func(node *TreeNode, indent string)
+	walk = func(node *TreeNode, indent string) {
+		var shown []*TreeNode
+		for _, child := range node.Children {
+			if child.hasChanges() {
+				shown = append(shown, child)
+			}
+		}
+		hidden := len(node.Children) - len(shown)
+
+		for i, child := range shown {
+			connector, childIndent := "├── ", "│   "
+			if i == len(shown)-1 && hidden == 0 {
+				connector, childIndent = "└── ", "    "
+			}
+
+			marker := ""
+			switch child.Change {
+			case TreeNew:
+				marker = "[NEW] "
+			case TreeEmptied:
+				marker = "[EMPTIED] "
+			}
+			b.WriteString(fmt.Sprintf("%s%s%s%s/%s\n", indent, connector, marker, child.Name, treeAnnotation(child)))
+			walk(child, indent+childIndent)
+		}
+
+		if hidden > 0 {
+			b.WriteString(fmt.Sprintf("%s└── ... %d unchanged folders\n", indent, hidden))
+		}
+	}
+	walk(root, "")
+}
+
+// treeAnnotation describes where a folder's contents come from or went to
+func treeAnnotation(node *TreeNode) string {
+	switch {
+	case node.MovedTo != "":
+		return fmt.Sprintf(" (→ moved to %s)", folderLabel(node.MovedTo))
+	case len(node.MovedFrom) == 1:
+		return fmt.Sprintf(" (← from %s)", folderLabel(node.MovedFrom[0]))
+	case len(node.MovedFrom) == 2:
+		return fmt.Sprintf(" (← from %s and %s)", folderLabel(node.MovedFrom[0]), folderLabel(node.MovedFrom[1]))
+	case len(node.MovedFrom) > 2:
+		return fmt.Sprintf(" (← consolidated from %d locations)", len(node.MovedFrom))
+	}
+	return ""
+}
+
 // FormatExecutionLog formats an execution log as text
 func (r *Reporter) FormatExecutionLog(log *ExecutionLog) string {
 	var b strings.Builder
@@ -92,7 +261,16 @@ func (r *Reporter) FormatExecutionLog(log *ExecutionLog) string {
 	b.WriteString("EXECUTION REPORT\n")
 	b.WriteString("================\n")
 	b.WriteString(fmt.Sprintf("Plan ID: %s\n", log.PlanID))
+	if log.ID != "" {
+		b.WriteString(fmt.Sprintf("Execution ID: %s\n", log.ID))
+	}
+	if log.ResumedFrom != "" {
+		b.WriteString(fmt.Sprintf("Resumed from: %s\n", log.ResumedFrom))
+	}
 	b.WriteString(fmt.Sprintf("Executed: %s\n", log.Timestamp.Format("2006-01-02 15:04:05")))
+	if !log.EndTime.IsZero() {
+		b.WriteString(fmt.Sprintf("Finished: %s (%s)\n", log.EndTime.Format("2006-01-02 15:04:05"), formatDuration(log)))
+	}
 	b.WriteString(fmt.Sprintf("Status: %s\n\n", formatStatus(log.Status)))
 	
 	// Summary
@@ -112,8 +290,15 @@ func (r *Reporter) FormatExecutionLog(log *ExecutionLog) string {
 	if len(log.Failed) > 0 {
 		b.WriteString("FAILED OPERATIONS\n")
 		b.WriteString("-----------------\n")
+		outOfStorage := 0
 		for _, failed := range log.Failed {
 			b.WriteString(fmt.Sprintf("• %s: %s\n", failed.MoveID, failed.Error))
+			if failed.Kind == FailureStorageQuota {
+				outOfStorage++
+			}
+		}
+		if outOfStorage > 0 {
+			b.WriteString(fmt.Sprintf("💾 %d operations failed because storage is full; free up space before retrying them.\n", outOfStorage))
 		}
 		b.WriteString("\n")
 	}
@@ -135,9 +320,13 @@ func (r *Reporter) FormatExecutionLog(log *ExecutionLog) string {
 	case StatusPartial:
 		b.WriteString("⚠️  Plan partially executed. Some operations failed or were skipped.\n")
 		b.WriteString("You may want to review the failed operations and retry manually.\n")
+		b.WriteString(fmt.Sprintf("Use 'curator apply %s --resume' to retry failed operations without repeating completed ones.\n", log.PlanID))
 	case StatusFailed:
 		b.WriteString("❌ Plan execution failed. No operations were completed successfully.\n")
 		b.WriteString("Please review the errors and fix any issues before retrying.\n")
+	case StatusInterrupted:
+		b.WriteString("⏸️  Plan execution was interrupted before every operation ran.\n")
+		b.WriteString(fmt.Sprintf("Use 'curator apply %s --resume' to continue where it stopped.\n", log.PlanID))
 	case StatusInProgress:
 		b.WriteString("🔄 Plan execution is still in progress.\n")
 	}
@@ -158,6 +347,9 @@ func (r *Reporter) FormatPlanSummaries(summaries []*PlanSummary) string {
 	
 	for _, summary := range summaries {
 		b.WriteString(fmt.Sprintf("Plan ID: %s\n", summary.ID))
+		if summary.Type != "" {
+			b.WriteString(fmt.Sprintf("Type:    %s\n", summary.Type))
+		}
 		b.WriteString(fmt.Sprintf("Created: %s\n", summary.Timestamp.Format("2006-01-02 15:04:05")))
 		b.WriteString(fmt.Sprintf("Status:  %s\n", summary.Status))
 		b.WriteString(fmt.Sprintf("Files:   %d files, %d operations\n", summary.FileCount, summary.MoveCount))
@@ -183,7 +375,13 @@ func (r *Reporter) FormatExecutionHistory(logs []*ExecutionLog) string {
 	
 	for _, log := range logs {
 		b.WriteString(fmt.Sprintf("Plan: %s\n", log.PlanID))
+		if log.ID != "" {
+			b.WriteString(fmt.Sprintf("Execution: %s\n", log.ID))
+		}
 		b.WriteString(fmt.Sprintf("Executed: %s\n", log.Timestamp.Format("2006-01-02 15:04:05")))
+		if !log.EndTime.IsZero() {
+			b.WriteString(fmt.Sprintf("Duration: %s\n", formatDuration(log)))
+		}
 		b.WriteString(fmt.Sprintf("Status: %s\n", formatStatus(log.Status)))
 		
 		total := len(log.Completed) + len(log.Failed) + len(log.Skipped)
@@ -196,6 +394,104 @@ func (r *Reporter) FormatExecutionHistory(logs []*ExecutionLog) string {
 	return b.String()
 }
 
+// FormatRecoveryReport formats the result of recovering pending operations
+func (r *Reporter) FormatRecoveryReport(report *RecoveryReport) string {
+	var b strings.Builder
+
+	if len(report.Operations) == 0 {
+		return "No pending operations to recover.\n"
+	}
+
+	b.WriteString("RECOVERY REPORT\n")
+	b.WriteString("===============\n\n")
+
+	stillPending := 0
+	for _, op := range report.Operations {
+		var symbol string
+		switch op.Outcome {
+		case RecoveryAlreadyApplied, RecoveryCompleted:
+			symbol = "✓"
+		case RecoverySkipped:
+			symbol = "⚠"
+		default:
+			symbol = "✗"
+			stillPending++
+		}
+
+		b.WriteString(fmt.Sprintf("%s %s (%s): %s\n", symbol, op.OperationID, op.Type, formatRecoveryOutcome(op.Outcome)))
+		if op.Detail != "" {
+			b.WriteString(fmt.Sprintf("  %s\n", op.Detail))
+		}
+	}
+	b.WriteString("\n")
+
+	if len(report.Executions) > 0 {
+		b.WriteString("UPDATED EXECUTIONS\n")
+		b.WriteString("------------------\n")
+		for _, log := range report.Executions {
+			b.WriteString(fmt.Sprintf("• %s (plan %s): %s\n", log.Key(), log.PlanID, formatStatus(log.Status)))
+		}
+		b.WriteString("\n")
+	}
+
+	if stillPending > 0 {
+		b.WriteString(fmt.Sprintf("%d operations failed and are still pending. Fix the errors above and run 'curator recover' again.\n", stillPending))
+	} else {
+		b.WriteString("All pending operations recovered.\n")
+	}
+
+	return b.String()
+}
+
+// formatRecoveryOutcome describes a recovery outcome
+func formatRecoveryOutcome(outcome RecoveryOutcome) string {
+	switch outcome {
+	case RecoveryAlreadyApplied:
+		return "already applied before the interruption"
+	case RecoveryCompleted:
+		return "completed"
+	case RecoverySkipped:
+		return "skipped (conflict)"
+	case RecoveryFailed:
+		return "failed, still pending"
+	default:
+		return string(outcome)
+	}
+}
+
+// FormatStoreReport formats the result of verifying or repairing the operation store
+func (r *Reporter) FormatStoreReport(report *StoreReport) string {
+	var b strings.Builder
+
+	b.WriteString("STORE VERIFICATION\n")
+	b.WriteString("==================\n\n")
+	b.WriteString(fmt.Sprintf("Records checked: %d\n", report.Checked))
+
+	if len(report.Issues) == 0 {
+		b.WriteString("No corrupt records found.\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Corrupt records: %d\n\n", len(report.Issues)))
+	quarantined := false
+	for _, issue := range report.Issues {
+		b.WriteString(fmt.Sprintf("✗ %s\n", issue.Path))
+		b.WriteString(fmt.Sprintf("  Kind: %s\n", issue.Kind))
+		b.WriteString(fmt.Sprintf("  Problem: %s\n", issue.Problem))
+		if issue.Quarantined != "" {
+			b.WriteString(fmt.Sprintf("  Quarantined to: %s\n", issue.Quarantined))
+			quarantined = true
+		}
+		b.WriteString("\n")
+	}
+
+	if !quarantined {
+		b.WriteString("Use 'curator store repair' to quarantine corrupt records\n")
+	}
+
+	return b.String()
+}
+
 // FormatDuplicationReport formats a duplication report
 func (r *Reporter) FormatDuplicationReport(report *DuplicationReport) string {
 	var b strings.Builder
@@ -227,6 +523,9 @@ func (r *Reporter) FormatDuplicationReport(report *DuplicationReport) string {
 		}
 		
 		b.WriteString(fmt.Sprintf("Group %d (Size: %s each):\n", i+1, formatBytes(group.Size)))
+		if group.Explanation != "" {
+			b.WriteString(fmt.Sprintf("  → %s\n", group.Explanation))
+		}
 		for _, file := range group.Files {
 			b.WriteString(fmt.Sprintf("  • %s\n", file))
 		}
@@ -236,6 +535,52 @@ func (r *Reporter) FormatDuplicationReport(report *DuplicationReport) string {
 	return b.String()
 }
 
+// FormatDeduplicationPlan formats a deduplication plan: the duplicate groups,
+// with the keeper of each group listed first, followed by the planned removals
+func (r *Reporter) FormatDeduplicationPlan(plan *DeduplicationPlan) string {
+	var b strings.Builder
+
+	b.WriteString(r.FormatDuplicationReport(&DuplicationReport{
+		ID:         plan.ID,
+		Timestamp:  plan.Timestamp,
+		Duplicates: plan.Groups,
+		Summary:    plan.Summary,
+	}))
+
+	if len(plan.Removals) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("PLANNED ACTIONS\n")
+	b.WriteString("---------------\n")
+	b.WriteString(fmt.Sprintf("Keeper policy: %s (first file of each group is kept)\n", plan.Policy))
+	if plan.Action == DuplicateQuarantine {
+		b.WriteString(fmt.Sprintf("Redundant copies are moved to %s\n\n", plan.QuarantineDir))
+	} else {
+		b.WriteString("Redundant copies are deleted\n\n")
+	}
+
+	for i, removal := range plan.Removals {
+		if i >= 20 {
+			b.WriteString(fmt.Sprintf("\n[... %d more copies ...]\n", len(plan.Removals)-20))
+			break
+		}
+
+		if removal.Destination != "" {
+			b.WriteString(fmt.Sprintf("• QUARANTINE: %s → %s\n", removal.Path, removal.Destination))
+		} else {
+			b.WriteString(fmt.Sprintf("• DELETE: %s (copy of %s)\n", removal.Path, removal.KeeperPath))
+		}
+	}
+
+	// Instructions
+	b.WriteString(fmt.Sprintf("\nType 'curator apply %s' to free %s\n", plan.ID, formatBytes(plan.Summary.SpaceSaved)))
+	b.WriteString(fmt.Sprintf("Type 'curator show-plan %s' to view this plan again\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator export %s > plan.json' to save it as JSON\n", plan.ID))
+
+	return b.String()
+}
+
 // FormatCleanupPlan formats a cleanup plan
 func (r *Reporter) FormatCleanupPlan(plan *CleanupPlan) string {
 	var b strings.Builder
@@ -268,10 +613,74 @@ func (r *Reporter) FormatCleanupPlan(plan *CleanupPlan) string {
 		
 		b.WriteString(fmt.Sprintf("• %s (%s) - %s\n", deletion.Path, formatBytes(deletion.Size), deletion.Reason))
 	}
+
+	// Instructions
+	b.WriteString(fmt.Sprintf("\nType 'curator apply %s' to delete these files\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator show-plan %s' to view this plan again\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator export %s > plan.json' to save it as JSON\n", plan.ID))
 	
 	return b.String()
 }
 
+// FormatRenamingPlan formats a renaming plan
+func (r *Reporter) FormatRenamingPlan(plan *RenamingPlan) string {
+	var b strings.Builder
+
+	b.WriteString("RENAMING PLAN\n")
+	b.WriteString("=============\n")
+	b.WriteString(fmt.Sprintf("Plan ID: %s\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Generated: %s\n\n", plan.Timestamp.Format("2006-01-02 15:04:05")))
+
+	if len(plan.Renames) == 0 {
+		b.WriteString("🎉 All files already follow consistent naming conventions!\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("Files to rename: %d\n", len(plan.Renames)))
+	b.WriteString(fmt.Sprintf("Pattern: %s\n\n", plan.Summary.Pattern))
+
+	for i, rename := range plan.Renames {
+		if i >= 10 {
+			b.WriteString(fmt.Sprintf("\n[... %d more files to rename ...]\n", len(plan.Renames)-10))
+			break
+		}
+		b.WriteString(fmt.Sprintf("• %s → %s\n", rename.OldPath, rename.NewName))
+	}
+
+	// Instructions
+	b.WriteString(fmt.Sprintf("\nType 'curator apply %s' to rename these files\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator show-plan %s' to view this plan again\n", plan.ID))
+	b.WriteString(fmt.Sprintf("Type 'curator export %s > plan.json' to save it as JSON\n", plan.ID))
+
+	return b.String()
+}
+
+// FormatProgress formats a progress event as one line, without a newline
+func (r *Reporter) FormatProgress(event ProgressEvent) string {
+	var line string
+	switch event.Phase {
+	case PhaseScan:
+		line = fmt.Sprintf("Scanning: %d files in %d folders", event.FilesScanned, event.FoldersScanned)
+	case PhaseHash:
+		line = fmt.Sprintf("Hashing: %s of %s", formatBytes(event.BytesHashed), formatBytes(event.BytesToHash))
+	case PhaseApply:
+		line = fmt.Sprintf("Applying: %d of %d operations done", event.Completed+event.Failed+event.Skipped, event.Total)
+		if event.Failed > 0 || event.Skipped > 0 {
+			line += fmt.Sprintf(" (%d failed, %d skipped)", event.Failed, event.Skipped)
+		}
+	default:
+		line = string(event.Phase)
+	}
+
+	if event.Done {
+		return line + fmt.Sprintf(", finished in %s", roundDuration(event.Elapsed))
+	}
+	if event.ETA >= time.Second {
+		line += fmt.Sprintf(", about %s left", roundDuration(event.ETA))
+	}
+	return line
+}
+
 // Helper functions
 
 func formatStatus(status ExecutionStatus) string {
@@ -284,11 +693,26 @@ func formatStatus(status ExecutionStatus) string {
 		return "⚠️ PARTIALLY COMPLETED"
 	case StatusInProgress:
 		return "🔄 IN PROGRESS"
+	case StatusInterrupted:
+		return "⏸️ INTERRUPTED"
 	default:
 		return string(status)
 	}
 }
 
+// formatDuration returns how long a finished execution took
+func formatDuration(log *ExecutionLog) string {
+	return log.EndTime.Sub(log.Timestamp).Round(time.Millisecond).String()
+}
+
+// roundDuration drops the precision nobody reads from a progress duration
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Second)
+}
+
 func formatBytes(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -306,6 +306,37 @@ func TestReporter_FormatDuplicationReport_NoDuplicates(t *testing.T) {
 	}
 }
 
+func TestReporter_FormatPlanValidation(t *testing.T) {
+	reporter := NewReporter()
+
+	validation := &PlanValidation{
+		PlanID: "plan-1",
+		Issues: []PlanIssue{
+			{MoveID: "move-2", Index: 1, Code: IssueUnknownSource, Severity: SeverityError, Message: "/ghost.txt was not found in the scan"},
+			{MoveID: "move-3", Index: 2, Code: IssueFolderCreatedLate, Severity: SeverityWarning, Message: "/Docs is created after move-1 uses it"},
+		},
+	}
+
+	output := reporter.FormatPlanValidation(validation)
+
+	expectedStrings := []string{
+		"Plan ID: plan-1",
+		"Errors: 1, Warnings: 1",
+		"❌ move-2 [UNKNOWN_SOURCE] /ghost.txt was not found in the scan",
+		"move-3 [FOLDER_CREATED_AFTER_USE]",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain '%s'", expected)
+		}
+	}
+
+	plan := &ReorganizationPlan{ID: "plan-1", ValidationIssues: validation.Issues}
+	if output := reporter.FormatReorganizationPlan(plan); !strings.Contains(output, "move-2 (UNKNOWN_SOURCE, dropped)") {
+		t.Error("Plan output should list the dropped move")
+	}
+}
+
 func TestReporter_FormatBytes(t *testing.T) {
 	tests := []struct {
 		bytes    int64
@@ -0,0 +1,327 @@
+package curator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ReviewGroup is a set of moves into the same destination folder, which are
+// reviewed together
+type ReviewGroup struct {
+	Folder string
+	Moves  []int // Indexes into the plan's moves, in plan order
+}
+
+// GroupMovesByDestination groups the moves of a plan by the folder they put
+// things in. Folder creations and removals belong to the folder they act on.
+func GroupMovesByDestination(plan *ReorganizationPlan) []ReviewGroup {
+	indexes := make(map[string]int)
+	var groups []ReviewGroup
+	for i, move := range plan.Moves {
+		folder := reviewFolder(move)
+		at, ok := indexes[folder]
+		if !ok {
+			at = len(groups)
+			indexes[folder] = at
+			groups = append(groups, ReviewGroup{Folder: folder})
+		}
+		groups[at].Moves = append(groups[at].Moves, i)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Folder < groups[j].Folder
+	})
+	return groups
+}
+
+// reviewFolder returns the folder a move is reviewed under
+func reviewFolder(move Move) string {
+	dest := normalizePlanPath(move.Destination)
+	if move.Type == CreateFolder || move.Type == RemoveFolder {
+		return dest
+	}
+	return path.Dir(dest)
+}
+
+// ExecuteReview walks through a saved reorganization plan one destination
+// folder at a time, reading decisions from in and writing prompts to out.
+// Each group, or each move in it, can be accepted, rejected or given a new
+// destination; an edit that would make a move invalid is refused on the spot.
+// The decisions are saved as a new plan derived from the original, which is
+// left as it was; only its approved moves run when applied.
+func ExecuteReview(ctx context.Context, opts CommandOptions, planID string, in io.Reader, out io.Writer) (*ReorganizationPlan, error) {
+	plan, err := opts.Store.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	if len(plan.Moves) == 0 {
+		return nil, fmt.Errorf("plan %s has no moves to review", planID)
+	}
+
+	reviewed := *plan
+	reviewed.Moves = make([]Move, len(plan.Moves))
+	for i, move := range plan.Moves {
+		move.Approval = ApprovalPending
+		reviewed.Moves[i] = move
+	}
+
+	// Edited destinations are checked like a freshly generated plan, as they
+	// are entered and once more when every move is decided
+	files, err := getAllFilesRecursively(ctx, opts.FileSystem, "/", nil, opts.Progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan filesystem: %w", err)
+	}
+
+	session := &reviewSession{
+		plan:     &reviewed,
+		files:    files,
+		input:    bufio.NewScanner(in),
+		out:      out,
+		reporter: opts.Reporter,
+	}
+	if err := session.run(); err != nil {
+		return nil, err
+	}
+
+	validation := ValidatePlan(&reviewed, files)
+	if validation.HasErrors() {
+		return nil, &PlanValidationError{Validation: validation}
+	}
+
+	if reviewed.ID, err = newPlanID(ctx, opts.Store, PlanTypeReorganization); err != nil {
+		return nil, err
+	}
+	reviewed.DerivedFrom = plan.ID
+	reviewed.ValidationIssues = nil
+	recountSummary(&reviewed)
+
+	if err := opts.Store.SavePlan(ctx, &reviewed); err != nil {
+		return nil, fmt.Errorf("failed to save reviewed plan: %w", err)
+	}
+
+	return &reviewed, nil
+}
+
+// reviewSession holds the state of an interactive review
+type reviewSession struct {
+	plan     *ReorganizationPlan
+	files    []FileInfo // What the plan is validated against
+	input    *bufio.Scanner
+	out      io.Writer
+	reporter *Reporter
+}
+
+// run asks for a decision on every group until each move has one
+func (s *reviewSession) run() error {
+	groups := GroupMovesByDestination(s.plan)
+	fmt.Fprintf(s.out, "Reviewing plan %s: %d moves in %d folders\n", s.plan.ID, len(s.plan.Moves), len(groups))
+	fmt.Fprintln(s.out, "Answers: [a]ccept, [r]eject, [e]dit destination, [s]tep through moves, [A]ccept all remaining, [q]uit without saving")
+
+	for n, group := range groups {
+		fmt.Fprint(s.out, "\n"+s.reporter.FormatReviewGroup(s.plan, group, n+1, len(groups)))
+
+	prompt:
+		for {
+			answer, err := s.ask("Accept, reject, edit or step through this folder? [a/r/e/s/A/q]: ")
+			if err != nil {
+				return err
+			}
+
+			switch answer {
+			case "a":
+				s.decide(group.Moves, ApprovalApproved)
+			case "r":
+				s.decide(group.Moves, ApprovalRejected)
+			case "A":
+				s.acceptRemaining()
+				return nil
+			case "e":
+				folder := s.folderOf(group)
+				for {
+					answer, err := s.ask(fmt.Sprintf("New destination folder for %s: ", folderLabel(folder)))
+					if err != nil {
+						return err
+					}
+					if answer == "" {
+						break
+					}
+					if s.edit(func() { s.renameFolder(group, folder, normalizePlanPath(answer)) }) {
+						fmt.Fprint(s.out, "\n"+s.reporter.FormatReviewGroup(s.plan, group, n+1, len(groups)))
+						break
+					}
+				}
+				continue
+			case "s":
+				if err := s.stepThrough(group); err != nil {
+					return err
+				}
+			default:
+				fmt.Fprintln(s.out, "Please answer a, r, e, s, A or q")
+				continue
+			}
+			break prompt
+		}
+	}
+
+	return nil
+}
+
+// stepThrough asks for a decision on each move of a group in turn
+func (s *reviewSession) stepThrough(group ReviewGroup) error {
+	for n, i := range group.Moves {
+	prompt:
+		for {
+			move := s.plan.Moves[i]
+			fmt.Fprintf(s.out, "  Move %d/%d: %s\n", n+1, len(group.Moves), formatReviewMove(move))
+			if move.Reason != "" {
+				fmt.Fprintf(s.out, "     → %s\n", move.Reason)
+			}
+
+			answer, err := s.ask("  Accept, reject or edit this move? [a/r/e/q]: ")
+			if err != nil {
+				return err
+			}
+			switch answer {
+			case "a":
+				s.decide([]int{i}, ApprovalApproved)
+			case "r":
+				s.decide([]int{i}, ApprovalRejected)
+			case "e":
+				for {
+					answer, err := s.ask(fmt.Sprintf("  New destination for %s: ", move.Destination))
+					if err != nil {
+						return err
+					}
+					if answer == "" || s.edit(func() { s.plan.Moves[i].Destination = normalizePlanPath(answer) }) {
+						break
+					}
+				}
+				continue
+			default:
+				fmt.Fprintln(s.out, "  Please answer a, r, e or q")
+				continue
+			}
+			break prompt
+		}
+	}
+	return nil
+}
+
+// ask prints a prompt and reads one answer. Quitting, or running out of
+// input before every move is decided, cancels the review.
+func (s *reviewSession) ask(prompt string) (string, error) {
+	fmt.Fprint(s.out, prompt)
+	if !s.input.Scan() {
+		if err := s.input.Err(); err != nil {
+			return "", fmt.Errorf("failed to read answer: %w", err)
+		}
+		fmt.Fprintln(s.out)
+		return "", fmt.Errorf("review ended before every move was decided: no plan was saved")
+	}
+
+	answer := strings.TrimSpace(s.input.Text())
+	if answer == "q" {
+		return "", fmt.Errorf("review cancelled: no plan was saved")
+	}
+	return answer, nil
+}
+
+// edit changes destinations with change and validates the plan. If that
+// finds errors the plan did not have before, the change is undone and the
+// errors are shown, so a new destination can be entered.
+func (s *reviewSession) edit(change func()) bool {
+	type problem struct {
+		index int
+		code  IssueCode
+	}
+	before := make(map[problem]bool)
+	for _, issue := range ValidatePlan(s.plan, s.files).Errors() {
+		before[problem{issue.Index, issue.Code}] = true
+	}
+	destinations := make([]string, len(s.plan.Moves))
+	for i, move := range s.plan.Moves {
+		destinations[i] = move.Destination
+	}
+
+	change()
+
+	var introduced []PlanIssue
+	for _, issue := range ValidatePlan(s.plan, s.files).Errors() {
+		if !before[problem{issue.Index, issue.Code}] {
+			introduced = append(introduced, issue)
+		}
+	}
+	if len(introduced) == 0 {
+		return true
+	}
+
+	for i := range s.plan.Moves {
+		s.plan.Moves[i].Destination = destinations[i]
+	}
+	fmt.Fprintln(s.out, "That destination would not work:")
+	for _, issue := range introduced {
+		fmt.Fprintf(s.out, "  ❌ %s [%s] %s\n", issue.MoveID, issue.Code, issue.Message)
+	}
+	return false
+}
+
+// decide records the same decision for several moves
+func (s *reviewSession) decide(indexes []int, state ApprovalState) {
+	for _, i := range indexes {
+		s.plan.Moves[i].Approval = state
+	}
+}
+
+// acceptRemaining approves every move that has no decision yet
+func (s *reviewSession) acceptRemaining() {
+	for i := range s.plan.Moves {
+		if s.plan.Moves[i].Approval == ApprovalPending {
+			s.plan.Moves[i].Approval = ApprovalApproved
+		}
+	}
+}
+
+// folderOf returns the current folder of a group, which earlier edits may
+// have changed
+func (s *reviewSession) folderOf(group ReviewGroup) string {
+	return reviewFolder(s.plan.Moves[group.Moves[0]])
+}
+
+// renameFolder points the moves of a group at newFolder instead of oldFolder.
+// Undecided moves below oldFolder follow too, so later groups match the edit;
+// for the root folder only the group itself changes.
+func (s *reviewSession) renameFolder(group ReviewGroup, oldFolder, newFolder string) {
+	inGroup := make(map[int]bool)
+	for _, i := range group.Moves {
+		inGroup[i] = true
+	}
+
+	for i, move := range s.plan.Moves {
+		if move.Approval != ApprovalPending {
+			continue
+		}
+		dest := normalizePlanPath(move.Destination)
+		if inGroup[i] || (oldFolder != "/" && isWithin(dest, oldFolder)) {
+			s.plan.Moves[i].Destination = path.Join(newFolder, strings.TrimPrefix(dest, oldFolder))
+		}
+	}
+}
+
+// formatReviewMove describes a single move on one line
+func formatReviewMove(move Move) string {
+	switch move.Type {
+	case CreateFolder:
+		return fmt.Sprintf("create folder %s", folderLabel(move.Destination))
+	case RemoveFolder:
+		return fmt.Sprintf("remove folder %s (if empty)", folderLabel(move.Destination))
+	case FolderMove:
+		return fmt.Sprintf("move folder %s → %s", folderLabel(move.Source), folderLabel(move.Destination))
+	default:
+		return fmt.Sprintf("move %s → %s", move.Source, move.Destination)
+	}
+}
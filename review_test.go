@@ -0,0 +1,247 @@
+package curator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newReviewPlan() *ReorganizationPlan {
+	return &ReorganizationPlan{
+		ID:        "reorg-1",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Destination: "/Text", Type: CreateFolder},
+			{ID: "move-2", Source: "/a.txt", Destination: "/Text/a.txt", Type: FileMove},
+			{ID: "move-3", Source: "/b.txt", Destination: "/Text/b.txt", Type: FileMove},
+			{ID: "move-4", Destination: "/Web", Type: CreateFolder},
+			{ID: "move-5", Source: "/Projects/site", Destination: "/Web/site", Type: FolderMove},
+			{ID: "move-6", Source: "/Docs/existing.txt", Destination: "/Archive/existing.txt", Type: FileMove},
+		},
+		Summary: Summary{FoldersCreated: 2, FilesMoved: 3},
+	}
+}
+
+func TestGroupMovesByDestination(t *testing.T) {
+	groups := GroupMovesByDestination(newReviewPlan())
+
+	want := []ReviewGroup{
+		{Folder: "/Archive", Moves: []int{5}},
+		{Folder: "/Text", Moves: []int{0, 1, 2}},
+		{Folder: "/Web", Moves: []int{3, 4}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %+v", len(want), groups)
+	}
+	for i := range want {
+		if groups[i].Folder != want[i].Folder || len(groups[i].Moves) != len(want[i].Moves) {
+			t.Errorf("Group %d: expected %+v, got %+v", i, want[i], groups[i])
+		}
+	}
+}
+
+func TestExecuteReview(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Reporter: NewReporter()}
+	if err := store.SavePlan(context.Background(), newReviewPlan()); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	answers := strings.Join([]string{
+		"r",                                          // Archive/: reject
+		"s", "a", "x", "r", "e", "/Notes/b.txt", "a", // Text/: step through
+		"e", "Sites", "a", // Web/: rename the folder, then accept
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	reviewed, err := ExecuteReview(context.Background(), opts, "reorg-1", strings.NewReader(answers), &out)
+	if err != nil {
+		t.Fatalf("ExecuteReview failed: %v\n%s", err, out.String())
+	}
+
+	if reviewed.ID == "reorg-1" || reviewed.DerivedFrom != "reorg-1" {
+		t.Errorf("Expected a new plan derived from reorg-1, got %s from %q", reviewed.ID, reviewed.DerivedFrom)
+	}
+	want := map[string]struct {
+		approval    ApprovalState
+		destination string
+	}{
+		"move-1": {ApprovalApproved, "/Text"},
+		"move-2": {ApprovalRejected, "/Text/a.txt"},
+		"move-3": {ApprovalApproved, "/Notes/b.txt"},
+		"move-4": {ApprovalApproved, "/Sites"},
+		"move-5": {ApprovalApproved, "/Sites/site"},
+		"move-6": {ApprovalRejected, "/Archive/existing.txt"},
+	}
+	for _, move := range reviewed.Moves {
+		if w := want[move.ID]; move.Approval != w.approval || move.Destination != w.destination {
+			t.Errorf("%s: expected %s to %s, got %s to %s", move.ID, w.approval, w.destination, move.Approval, move.Destination)
+		}
+	}
+	if reviewed.Summary.FoldersCreated != 2 || reviewed.Summary.FilesMoved != 1 {
+		t.Errorf("Summary should only count approved moves, got %+v", reviewed.Summary)
+	}
+	if !strings.Contains(out.String(), "Folder 2/3: /Text/ (3 moves)") || !strings.Contains(out.String(), "Please answer a, r, e or q") {
+		t.Errorf("Unexpected review output:\n%s", out.String())
+	}
+
+	// The original plan is unchanged
+	original, err := store.GetPlan(context.Background(), "reorg-1")
+	if err != nil {
+		t.Fatalf("Failed to get original plan: %v", err)
+	}
+	if original.Moves[2].Destination != "/Text/b.txt" || original.Moves[1].Approval != ApprovalPending {
+		t.Errorf("Reviewing must not change the original plan: %+v", original.Moves)
+	}
+
+	// Only the approved moves run
+	execLog, err := NewExecutionEngine(fs, store).ExecutePlan(context.Background(), reviewed.ID, false)
+	if err != nil {
+		t.Fatalf("ExecutePlan failed: %v", err)
+	}
+	if len(execLog.Completed) != 4 || len(execLog.Skipped) != 0 || len(execLog.Failed) != 0 {
+		t.Errorf("Expected the 4 approved moves to complete, got %+v", execLog)
+	}
+	for p, exists := range map[string]bool{
+		"/a.txt":                 true,
+		"/Text/a.txt":            false,
+		"/Notes/b.txt":           true,
+		"/Sites/site/index.html": true,
+		"/Docs/existing.txt":     true,
+		"/Archive/existing.txt":  false,
+	} {
+		if got, _ := fs.Exists(context.Background(), p); got != exists {
+			t.Errorf("Expected %s to exist: %v, got %v", p, exists, got)
+		}
+	}
+
+	output := NewReporter().FormatReorganizationPlan(reviewed)
+	for _, expected := range []string{"Reviewed from: reorg-1 (4 approved, 2 rejected)", "[REJECTED] move /a.txt → /Text/a.txt"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected plan report to contain %q:\n%s", expected, output)
+		}
+	}
+}
+
+func TestExecuteReview_SavesNothingUnlessFinished(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Reporter: NewReporter()}
+	if err := store.SavePlan(context.Background(), newReviewPlan()); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	tests := map[string]string{
+		"quit":       "a\nq\n",
+		"input ends": "a\n",
+	}
+	for name, answers := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ExecuteReview(context.Background(), opts, "reorg-1", strings.NewReader(answers), &bytes.Buffer{})
+			if err == nil {
+				t.Fatal("Expected the review to fail")
+			}
+		})
+	}
+
+	if plans, _ := store.ListPlans(context.Background()); len(plans) != 1 {
+		t.Errorf("Expected only the original plan, got %+v", plans)
+	}
+
+	// Accepting everything that is left finishes early
+	reviewed, err := ExecuteReview(context.Background(), opts, "reorg-1", strings.NewReader("r\nA\n"), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("ExecuteReview failed: %v", err)
+	}
+	for _, move := range reviewed.Moves {
+		want := ApprovalApproved
+		if move.ID == "move-6" {
+			want = ApprovalRejected
+		}
+		if move.Approval != want {
+			t.Errorf("%s: expected %s, got %s", move.ID, want, move.Approval)
+		}
+	}
+}
+
+func TestExecuteReview_RefusesInvalidEdits(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Reporter: NewReporter()}
+	if err := store.SavePlan(context.Background(), newReviewPlan()); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	// An existing file as destination is refused and asked for again,
+	// keeping the decisions made so far
+	answers := strings.Join([]string{
+		"a",                                 // Archive/: accept
+		"s", "a", "e", "/Docs/existing.txt", // Text/: edit onto an existing file
+		"/Notes/a.txt", "a", "a", // then a valid destination
+		"e", "/Docs/existing.txt", "", "A", // Web/: a folder over a file, then give up editing
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	reviewed, err := ExecuteReview(context.Background(), opts, "reorg-1", strings.NewReader(answers), &out)
+	if err != nil {
+		t.Fatalf("ExecuteReview failed: %v\n%s", err, out.String())
+	}
+	if strings.Count(out.String(), "would not work") != 2 {
+		t.Errorf("Expected both invalid edits to be refused:\n%s", out.String())
+	}
+
+	want := map[string]string{"move-2": "/Notes/a.txt", "move-4": "/Web", "move-6": "/Archive/existing.txt"}
+	for _, move := range reviewed.Moves {
+		if dest, ok := want[move.ID]; ok && move.Destination != dest {
+			t.Errorf("%s: expected destination %s, got %s", move.ID, dest, move.Destination)
+		}
+		if move.Approval != ApprovalApproved {
+			t.Errorf("%s: expected the decision to be kept, got %q", move.ID, move.Approval)
+		}
+	}
+}
+
+func TestExecutePlan_DerivedPlanRunsOnlyApprovedMoves(t *testing.T) {
+	fs, files := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+
+	// A reviewed plan saved with a move nobody decided on, e.g. by an import
+	plan := &ReorganizationPlan{
+		ID:          "reorg-2",
+		Timestamp:   time.Now(),
+		DerivedFrom: "reorg-1",
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Text/a.txt", Type: FileMove, Approval: ApprovalApproved},
+			// Would fail, as the destination exists, if it ran
+			{ID: "move-2", Source: "/b.txt", Destination: "/Docs/existing.txt", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	execLog, err := NewExecutionEngine(fs, store).ExecutePlan(context.Background(), plan.ID, false)
+	if err != nil {
+		t.Fatalf("ExecutePlan failed: %v", err)
+	}
+	if len(execLog.Completed) != 1 || execLog.Completed[0].MoveID != "move-1" {
+		t.Errorf("Expected only the approved move to run, got %+v", execLog.Completed)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/b.txt"); !exists {
+		t.Error("Expected the pending move not to run")
+	}
+
+	recountSummary(plan)
+	if plan.Summary.FilesMoved != 1 {
+		t.Errorf("Expected the summary to count only the approved move, got %+v", plan.Summary)
+	}
+	if validation := ValidatePlan(plan, files); len(validation.Issues) != 0 {
+		t.Errorf("Expected the pending move not to be validated, got %+v", validation.Issues)
+	}
+	if output := NewReporter().FormatReorganizationPlan(plan); !strings.Contains(output, "(1 approved, 0 rejected)") || !strings.Contains(output, "[NOT APPROVED]") {
+		t.Errorf("Expected the report to set the pending move apart:\n%s", output)
+	}
+}
@@ -0,0 +1,219 @@
+package curator
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// TreeChange says how a folder is affected by a simulated plan
+type TreeChange string
+
+const (
+	TreeUnchanged TreeChange = ""
+	// TreeNew marks a folder the plan creates
+	TreeNew TreeChange = "NEW"
+	// TreeMoved marks a folder that arrives through a folder move
+	TreeMoved TreeChange = "MOVED"
+	// TreeMovedAway marks a folder that a folder move takes elsewhere
+	TreeMovedAway TreeChange = "MOVED_AWAY"
+	// TreeEmptied marks a folder that held files and holds none afterwards
+	TreeEmptied TreeChange = "EMPTIED"
+)
+
+// TreeNode is a folder in a before or after tree of a plan simulation
+type TreeNode struct {
+	Name      string      `json:"name"`
+	Path      string      `json:"path"`
+	Files     int         `json:"files"` // Files inside the folder, at any depth
+	Change    TreeChange  `json:"change"`
+	MovedFrom []string    `json:"movedFrom"` // Folders the plan moves files or folders in from
+	MovedTo   string      `json:"movedTo"`   // Where a folder moved away went
+	Children  []*TreeNode `json:"children"`
+}
+
+// PlanSimulation is the result of applying a plan to a virtual copy of the
+// scanned tree
+type PlanSimulation struct {
+	PlanID string        `json:"planId"`
+	Before *TreeNode     `json:"before"`
+	After  *TreeNode     `json:"after"`
+	Log    *ExecutionLog `json:"log"` // How each move fared against the virtual tree
+}
+
+// SimulatePlan applies plan to an in-memory copy of the scanned files with
+// the regular execution engine, and describes the folder trees before and
+// after. Nothing outside the copy is touched.
+func SimulatePlan(ctx context.Context, plan *ReorganizationPlan, files []FileInfo) (*PlanSimulation, error) {
+	fs := NewMemoryFileSystemFromFiles(files)
+	store := NewMemoryOperationStore()
+	engine := NewExecutionEngine(fs, store)
+
+	// The memory filesystem does not resolve paths against a root, so give it
+	// the absolute paths the real filesystems would use
+	virtual := *plan
+	virtual.Moves = make([]Move, len(plan.Moves))
+	for i, move := range plan.Moves {
+		if move.Source != "" {
+			move.Source = normalizePlanPath(move.Source)
+		}
+		move.Destination = normalizePlanPath(move.Destination)
+		virtual.Moves[i] = move
+	}
+
+	if err := store.SavePlan(ctx, &virtual); err != nil {
+		return nil, fmt.Errorf("failed to save plan: %w", err)
+	}
+	execLog, err := engine.ExecutePlan(ctx, virtual.ID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate plan: %w", err)
+	}
+
+	afterFiles, err := getAllFilesRecursively(ctx, fs, "/", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list simulated files: %w", err)
+	}
+
+	before := buildFolderTree(files)
+	after := buildFolderTree(afterFiles)
+
+	completed := make(map[string]bool)
+	for _, move := range execLog.Completed {
+		completed[move.MoveID] = true
+	}
+
+	// Folders that moved whole; what is inside them is neither new nor emptied
+	var arrived, departed []string
+	for _, move := range virtual.Moves {
+		if !completed[move.ID] {
+			continue
+		}
+		switch move.Type {
+		case FolderMove:
+			arrived = append(arrived, move.Destination)
+			departed = append(departed, move.Source)
+			if node, ok := after[move.Destination]; ok {
+				node.Change = TreeMoved
+				node.MovedFrom = appendUnique(node.MovedFrom, move.Source)
+			}
+			if node, ok := before[move.Source]; ok {
+				node.Change = TreeMovedAway
+				node.MovedTo = move.Destination
+			}
+		case FileMove:
+			if node, ok := after[path.Dir(move.Destination)]; ok {
+				node.MovedFrom = appendUnique(node.MovedFrom, path.Dir(move.Source))
+			}
+		}
+	}
+
+	for p, node := range after {
+		if node.Change != TreeUnchanged {
+			continue
+		}
+		old, existed := before[p]
+		switch {
+		case !existed && !withinAny(p, arrived):
+			node.Change = TreeNew
+		case existed && old.Files > 0 && node.Files == 0:
+			node.Change = TreeEmptied
+		}
+	}
+	for p, node := range before {
+		if node.Change != TreeUnchanged || node.Files == 0 || withinAny(p, departed) {
+			continue
+		}
+		if now, ok := after[p]; !ok || now.Files == 0 {
+			node.Change = TreeEmptied
+		}
+	}
+
+	return &PlanSimulation{
+		PlanID: plan.ID,
+		Before: before["/"],
+		After:  after["/"],
+		Log:    execLog,
+	}, nil
+}
+
+// buildFolderTree arranges the folders of files into a tree rooted at "/" and
+// returns every folder by path
+func buildFolderTree(files []FileInfo) map[string]*TreeNode {
+	folders := map[string]*TreeNode{"/": {Name: "/", Path: "/"}}
+
+	var folder func(p string) *TreeNode
+	folder = func(p string) *TreeNode {
+		if node, ok := folders[p]; ok {
+			return node
+		}
+		node := &TreeNode{Name: path.Base(p), Path: p}
+		folders[p] = node
+		parent := folder(path.Dir(p))
+		parent.Children = append(parent.Children, node)
+		return node
+	}
+
+	for _, file := range files {
+		p := normalizePlanPath(file.Path())
+		if file.IsDir() {
+			folder(p)
+			continue
+		}
+		for dir := path.Dir(p); ; dir = path.Dir(dir) {
+			folder(dir).Files++
+			if dir == "/" {
+				break
+			}
+		}
+	}
+
+	for _, node := range folders {
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].Name < node.Children[j].Name
+		})
+	}
+	return folders
+}
+
+// withinAny reports whether p lies inside, or is, one of dirs
+func withinAny(p string, dirs []string) bool {
+	for _, dir := range dirs {
+		if isWithin(p, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique appends value to values unless it is already there
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// hasChanges reports whether the plan changes this folder or anything below it
+func (n *TreeNode) hasChanges() bool {
+	if n.Change != TreeUnchanged || len(n.MovedFrom) > 0 {
+		return true
+	}
+	for _, child := range n.Children {
+		if child.hasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
+// folderLabel writes a folder path the way trees show it, with a trailing slash
+func folderLabel(p string) string {
+	if p == "/" {
+		return p
+	}
+	return strings.TrimSuffix(p, "/") + "/"
+}
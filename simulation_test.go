@@ -0,0 +1,116 @@
+package curator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimulatePlan(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/Desktop/report.pdf", []byte("r"), "application/pdf")
+	fs.AddFile("/Desktop/notes.txt", []byte("n"), "text/plain")
+	fs.AddFile("/ideas/todo.txt", []byte("t"), "text/plain")
+	fs.AddFile("/Random/ProjectStuff/main.go", []byte("package main"), "text/x-go")
+	fs.AddFile("/Random/ProjectStuff/lib/util.go", []byte("package lib"), "text/x-go")
+	fs.AddFile("/Docs/taken.txt", []byte("x"), "text/plain")
+
+	files, err := getAllFilesRecursively(context.Background(), fs, "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to scan files: %v", err)
+	}
+
+	plan := &ReorganizationPlan{
+		ID:        "preview-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Destination: "Work", Type: CreateFolder},
+			{ID: "move-2", Source: "/Random/ProjectStuff", Destination: "Work/Projects", Type: FolderMove},
+			{ID: "move-3", Source: "/Desktop/notes.txt", Destination: "/Notes/notes.txt", Type: FileMove},
+			{ID: "move-4", Source: "/ideas/todo.txt", Destination: "/Notes/todo.txt", Type: FileMove},
+			{ID: "move-5", Source: "/Desktop/report.pdf", Destination: "/Docs/taken.txt", Type: FileMove},
+		},
+	}
+
+	sim, err := SimulatePlan(context.Background(), plan, files)
+	if err != nil {
+		t.Fatalf("SimulatePlan failed: %v", err)
+	}
+
+	// The real filesystem is untouched
+	if exists, _ := fs.Exists(context.Background(), "/Notes/notes.txt"); exists {
+		t.Fatal("Simulation must not change the scanned filesystem")
+	}
+
+	after := collectTree(sim.After)
+	if after["/Work"].Change != TreeNew {
+		t.Errorf("Expected /Work to be new, got %q", after["/Work"].Change)
+	}
+	projects := after["/Work/Projects"]
+	if projects.Change != TreeMoved || projects.Files != 2 || projects.MovedFrom[0] != "/Random/ProjectStuff" {
+		t.Errorf("Expected /Work/Projects to arrive with 2 files, got %+v", projects)
+	}
+	if after["/Work/Projects/lib"].Change != TreeUnchanged {
+		t.Errorf("Folders inside a moved folder are not new, got %q", after["/Work/Projects/lib"].Change)
+	}
+	notes := after["/Notes"]
+	if notes.Change != TreeNew || len(notes.MovedFrom) != 2 {
+		t.Errorf("Expected /Notes to gather files from 2 folders, got %+v", notes)
+	}
+	if after["/ideas"].Change != TreeEmptied {
+		t.Errorf("Expected /ideas to be emptied, got %q", after["/ideas"].Change)
+	}
+	if after["/Desktop"].Change != TreeUnchanged {
+		t.Errorf("Expected /Desktop to keep report.pdf, got %q", after["/Desktop"].Change)
+	}
+
+	before := collectTree(sim.Before)
+	if moved := before["/Random/ProjectStuff"]; moved.Change != TreeMovedAway || moved.MovedTo != "/Work/Projects" {
+		t.Errorf("Expected /Random/ProjectStuff to be moved away, got %+v", moved)
+	}
+	if before["/Random/ProjectStuff/lib"].Change != TreeUnchanged {
+		t.Errorf("Folders inside a moved folder are not emptied, got %q", before["/Random/ProjectStuff/lib"].Change)
+	}
+	if before["/Random"].Change != TreeEmptied {
+		t.Errorf("Expected /Random to be emptied, got %q", before["/Random"].Change)
+	}
+
+	// The conflicting move is reported rather than applied
+	if len(sim.Log.Skipped) != 1 || sim.Log.Skipped[0].MoveID != "move-5" {
+		t.Errorf("Expected move-5 to be skipped, got %+v", sim.Log.Skipped)
+	}
+
+	output := NewReporter().FormatPlanSimulation(sim)
+	expected := []string{
+		"FOLDER STRUCTURE CHANGES",
+		"[NEW] Work/",
+		"Projects/ (← from /Random/ProjectStuff/)",
+		"[NEW] Notes/ (← from /Desktop/ and /ideas/)",
+		"[EMPTIED] ideas/",
+		"ProjectStuff/ (→ moved to /Work/Projects/)",
+		"move-5: Conflict",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "── Docs/") {
+		t.Errorf("Unchanged folders should be collapsed:\n%s", output)
+	}
+}
+
+// collectTree indexes a simulated tree by folder path
+func collectTree(root *TreeNode) map[string]*TreeNode {
+	nodes := make(map[string]*TreeNode)
+	var walk func(node *TreeNode)
+	walk = func(node *TreeNode) {
+		nodes[node.Path] = node
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return nodes
+}
@@ -0,0 +1,602 @@
+package curator
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDatabaseName is the database file created inside the store directory
+const SQLiteDatabaseName = "curator.db"
+
+// sqliteMigrations are applied in order; each entry is one schema version.
+// Never edit an entry that has shipped - append a new one instead.
+var sqliteMigrations = []string{
+	// 1: initial schema
+	`CREATE TABLE plans (
+		id         TEXT PRIMARY KEY,
+		type       TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		item_count INTEGER NOT NULL,
+		data       BLOB NOT NULL
+	);
+	CREATE INDEX idx_plans_type ON plans(type);
+	CREATE INDEX idx_plans_created_at ON plans(created_at);
+
+	CREATE TABLE operations (
+		id           TEXT PRIMARY KEY,
+		type         TEXT NOT NULL,
+		data         BLOB,
+		created_at   INTEGER NOT NULL,
+		completed_at INTEGER
+	);
+	CREATE INDEX idx_operations_pending ON operations(completed_at, created_at);
+
+	CREATE TABLE execution_logs (
+		plan_id    TEXT PRIMARY KEY,
+		status     TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		data       BLOB NOT NULL
+	);
+	CREATE INDEX idx_execution_logs_status ON execution_logs(status);
+	CREATE INDEX idx_execution_logs_started_at ON execution_logs(started_at);`,
+
+	// 2: key execution logs by execution ID so every attempt is kept
+	`CREATE TABLE execution_attempts (
+		id         TEXT PRIMARY KEY,
+		plan_id    TEXT NOT NULL,
+		status     TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		data       BLOB NOT NULL
+	);
+	INSERT INTO execution_attempts (id, plan_id, status, started_at, data)
+		SELECT plan_id, plan_id, status, started_at, data FROM execution_logs;
+	DROP TABLE execution_logs;
+	ALTER TABLE execution_attempts RENAME TO execution_logs;
+	CREATE INDEX idx_execution_logs_plan_id ON execution_logs(plan_id, started_at);
+	CREATE INDEX idx_execution_logs_status ON execution_logs(status);
+	CREATE INDEX idx_execution_logs_started_at ON execution_logs(started_at);`,
+
+	// 3: record which execution and step logged each operation
+	`ALTER TABLE operations ADD COLUMN plan_id TEXT NOT NULL DEFAULT '';
+	ALTER TABLE operations ADD COLUMN execution_id TEXT NOT NULL DEFAULT '';
+	ALTER TABLE operations ADD COLUMN step_id TEXT NOT NULL DEFAULT '';`,
+
+	// 4: keep the records store repair takes out of use
+	`CREATE TABLE corrupt_records (
+		source_table   TEXT NOT NULL,
+		id             TEXT NOT NULL,
+		data           BLOB,
+		problem        TEXT NOT NULL,
+		quarantined_at INTEGER NOT NULL
+	);`,
+}
+
+// sqliteCorruptTable is where Repair moves records that cannot be decoded
+const sqliteCorruptTable = "corrupt_records"
+
+// SQLiteOperationStore implements OperationStore on top of an SQLite database.
+// Plans and execution logs are stored as JSON alongside indexed columns, so
+// lookups by plan ID, type and status never scan the whole store.
+type SQLiteOperationStore struct {
+	db   *sql.DB
+	path string
+}
+
+// NewSQLiteOperationStore opens (or creates) the database at dbPath and migrates
+// it to the latest schema
+func NewSQLiteOperationStore(dbPath string) (*SQLiteOperationStore, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_synchronous=FULL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// A single connection serializes writers and keeps transactions simple
+	db.SetMaxOpenConns(1)
+
+	store := &SQLiteOperationStore{db: db, path: dbPath}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteOperationStore) Close() error {
+	return s.db.Close()
+}
+
+// SchemaVersion returns the schema version the database is at
+func (s *SQLiteOperationStore) SchemaVersion() (int, error) {
+	var version int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// migrate applies every migration newer than the database's schema version
+func (s *SQLiteOperationStore) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if current > len(sqliteMigrations) {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d)", current, len(sqliteMigrations))
+	}
+
+	for version := current + 1; version <= len(sqliteMigrations); version++ {
+		err := s.inTx(context.Background(), func(tx *sql.Tx) error {
+			if _, err := tx.Exec(sqliteMigrations[version-1]); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().UnixNano())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// inTx runs fn in a transaction, committing on success and rolling back on error
+func (s *SQLiteOperationStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// savePlanRecord upserts a plan of any type
+func (s *SQLiteOperationStore) savePlanRecord(ctx context.Context, id string, planType PlanType, timestamp time.Time, itemCount int, plan interface{}) error {
+	if err := validatePlanID(id); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s plan: %w", planType, err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO plans (id, type, created_at, item_count, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET type = excluded.type, created_at = excluded.created_at,
+			item_count = excluded.item_count, data = excluded.data`,
+		id, string(planType), timestamp.UnixNano(), itemCount, data)
+	if err != nil {
+		return fmt.Errorf("failed to save %s plan: %w", planType, err)
+	}
+
+	return nil
+}
+
+// getPlanRecord loads a plan of the given type into plan
+func (s *SQLiteOperationStore) getPlanRecord(ctx context.Context, id string, planType PlanType, plan interface{}) error {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM plans WHERE id = ? AND type = ?`, id, string(planType)).Scan(&data)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s %w: %s", planType, ErrPlanNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s plan: %w", planType, err)
+	}
+
+	if err := json.Unmarshal(data, plan); err != nil {
+		return corruptRecordError(string(planType)+" plan", id, err)
+	}
+	return nil
+}
+
+// SavePlan implements OperationStore.SavePlan
+func (s *SQLiteOperationStore) SavePlan(ctx context.Context, plan *ReorganizationPlan) error {
+	return s.savePlanRecord(ctx, plan.ID, PlanTypeReorganization, plan.Timestamp, len(plan.Moves), plan)
+}
+
+// GetPlan implements OperationStore.GetPlan
+func (s *SQLiteOperationStore) GetPlan(ctx context.Context, id string) (*ReorganizationPlan, error) {
+	var plan ReorganizationPlan
+	if err := s.getPlanRecord(ctx, id, PlanTypeReorganization, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// SaveCleanupPlan implements OperationStore.SaveCleanupPlan
+func (s *SQLiteOperationStore) SaveCleanupPlan(ctx context.Context, plan *CleanupPlan) error {
+	return s.savePlanRecord(ctx, plan.ID, PlanTypeCleanup, plan.Timestamp, len(plan.Deletions), plan)
+}
+
+// GetCleanupPlan implements OperationStore.GetCleanupPlan
+func (s *SQLiteOperationStore) GetCleanupPlan(ctx context.Context, id string) (*CleanupPlan, error) {
+	var plan CleanupPlan
+	if err := s.getPlanRecord(ctx, id, PlanTypeCleanup, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// SaveRenamingPlan implements OperationStore.SaveRenamingPlan
+func (s *SQLiteOperationStore) SaveRenamingPlan(ctx context.Context, plan *RenamingPlan) error {
+	return s.savePlanRecord(ctx, plan.ID, PlanTypeRenaming, plan.Timestamp, len(plan.Renames), plan)
+}
+
+// GetRenamingPlan implements OperationStore.GetRenamingPlan
+func (s *SQLiteOperationStore) GetRenamingPlan(ctx context.Context, id string) (*RenamingPlan, error) {
+	var plan RenamingPlan
+	if err := s.getPlanRecord(ctx, id, PlanTypeRenaming, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// SaveDeduplicationPlan implements OperationStore.SaveDeduplicationPlan
+func (s *SQLiteOperationStore) SaveDeduplicationPlan(ctx context.Context, plan *DeduplicationPlan) error {
+	return s.savePlanRecord(ctx, plan.ID, PlanTypeDeduplication, plan.Timestamp, len(plan.Removals), plan)
+}
+
+// GetDeduplicationPlan implements OperationStore.GetDeduplicationPlan
+func (s *SQLiteOperationStore) GetDeduplicationPlan(ctx context.Context, id string) (*DeduplicationPlan, error) {
+	var plan DeduplicationPlan
+	if err := s.getPlanRecord(ctx, id, PlanTypeDeduplication, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ListPlans implements OperationStore.ListPlans
+func (s *SQLiteOperationStore) ListPlans(ctx context.Context) ([]*PlanSummary, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, type, created_at, item_count FROM plans ORDER BY created_at DESC, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make([]*PlanSummary, 0)
+	for rows.Next() {
+		var id, planType string
+		var createdAt int64
+		var itemCount int
+		if err := rows.Scan(&id, &planType, &createdAt, &itemCount); err != nil {
+			return nil, fmt.Errorf("failed to read plan row: %w", err)
+		}
+
+		summaries = append(summaries, &PlanSummary{
+			ID:        id,
+			Type:      PlanType(planType),
+			Timestamp: time.Unix(0, createdAt),
+			Status:    "pending",
+			FileCount: itemCount,
+			MoveCount: itemCount,
+		})
+	}
+
+	return summaries, rows.Err()
+}
+
+// LogOperation implements OperationStore.LogOperation. Logging an ID again
+// replaces the operation and makes it pending again.
+func (s *SQLiteOperationStore) LogOperation(ctx context.Context, op *Operation) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO operations (id, type, data, created_at, completed_at, plan_id, execution_id, step_id)
+			VALUES (?, ?, ?, ?, NULL, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET type = excluded.type, data = excluded.data,
+				created_at = excluded.created_at, completed_at = NULL, plan_id = excluded.plan_id,
+				execution_id = excluded.execution_id, step_id = excluded.step_id`,
+			op.ID, op.Type, op.Data, op.Timestamp.UnixNano(), op.PlanID, op.ExecutionID, op.StepID)
+		if err != nil {
+			return fmt.Errorf("failed to log operation: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPendingOperations implements OperationStore.GetPendingOperations
+func (s *SQLiteOperationStore) GetPendingOperations(ctx context.Context) ([]*Operation, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, type, data, created_at, plan_id, execution_id, step_id FROM operations
+		WHERE completed_at IS NULL ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending operations: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []*Operation
+	for rows.Next() {
+		var op Operation
+		var createdAt int64
+		if err := rows.Scan(&op.ID, &op.Type, &op.Data, &createdAt, &op.PlanID, &op.ExecutionID, &op.StepID); err != nil {
+			return nil, fmt.Errorf("failed to read operation row: %w", err)
+		}
+		op.Timestamp = time.Unix(0, createdAt)
+		pending = append(pending, &op)
+	}
+
+	return pending, rows.Err()
+}
+
+// MarkOperationComplete implements OperationStore.MarkOperationComplete
+func (s *SQLiteOperationStore) MarkOperationComplete(ctx context.Context, id string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE operations SET completed_at = ? WHERE id = ?`, time.Now().UnixNano(), id)
+		if err != nil {
+			return fmt.Errorf("failed to mark operation complete: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to mark operation complete: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("operation not found: %s", id)
+		}
+		return nil
+	})
+}
+
+// SaveExecutionLog implements OperationStore.SaveExecutionLog
+func (s *SQLiteOperationStore) SaveExecutionLog(ctx context.Context, log *ExecutionLog) error {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution log: %w", err)
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO execution_logs (id, plan_id, status, started_at, data) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET plan_id = excluded.plan_id, status = excluded.status,
+				started_at = excluded.started_at, data = excluded.data`,
+			log.Key(), log.PlanID, string(log.Status), log.Timestamp.UnixNano(), data)
+		if err != nil {
+			return fmt.Errorf("failed to save execution log: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetExecutionHistory implements OperationStore.GetExecutionHistory. Logs that
+// cannot be decoded are skipped with a warning.
+func (s *SQLiteOperationStore) GetExecutionHistory(ctx context.Context) ([]*ExecutionLog, error) {
+	return s.queryExecutionLogs(ctx, true, `SELECT id, data FROM execution_logs ORDER BY started_at DESC`)
+}
+
+// GetExecutionLogs implements OperationStore.GetExecutionLogs. A corrupt log of
+// the plan is reported rather than skipped, since resuming or rolling back from
+// an older log would redo or keep the wrong moves.
+func (s *SQLiteOperationStore) GetExecutionLogs(ctx context.Context, planID string) ([]*ExecutionLog, error) {
+	return s.queryExecutionLogs(ctx, false, `SELECT id, data FROM execution_logs WHERE plan_id = ? ORDER BY started_at DESC`, planID)
+}
+
+// GetExecutionLogsByStatus returns the execution logs with the given status,
+// newest first. Logs that cannot be decoded are skipped with a warning.
+func (s *SQLiteOperationStore) GetExecutionLogsByStatus(ctx context.Context, status ExecutionStatus) ([]*ExecutionLog, error) {
+	return s.queryExecutionLogs(ctx, true, `SELECT id, data FROM execution_logs WHERE status = ? ORDER BY started_at DESC`, string(status))
+}
+
+// queryExecutionLogs decodes the execution logs selected by query, which
+// selects their ID and data. Corrupt logs are skipped if skipCorrupt is set and
+// reported otherwise.
+func (s *SQLiteOperationStore) queryExecutionLogs(ctx context.Context, skipCorrupt bool, query string, args ...interface{}) ([]*ExecutionLog, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := make([]*ExecutionLog, 0)
+	for rows.Next() {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to read execution log row: %w", err)
+		}
+
+		var log ExecutionLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			if skipCorrupt {
+				warnCorruptRecord("execution log", id, err)
+				continue
+			}
+			return nil, corruptRecordError("execution log", id, err)
+		}
+		logs = append(logs, &log)
+	}
+
+	return logs, rows.Err()
+}
+
+// Verify implements VerifiableStore.Verify. It runs SQLite's integrity check
+// and decodes every plan and execution log, without changing anything.
+func (s *SQLiteOperationStore) Verify() (*StoreReport, error) {
+	report, _, err := s.scan(context.Background())
+	return report, err
+}
+
+// Repair implements VerifiableStore.Repair. Plans and execution logs that
+// cannot be decoded are moved to the corrupt_records table, so they stop
+// affecting the store but can still be inspected. Damage found by the
+// integrity check is only reported; restore the database from a backup.
+func (s *SQLiteOperationStore) Repair() (*StoreReport, error) {
+	ctx := context.Background()
+	report, corrupt, err := s.scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, record := range corrupt {
+			issue := &report.Issues[record.issue]
+			_, err := tx.ExecContext(ctx, `INSERT INTO `+sqliteCorruptTable+` (source_table, id, data, problem, quarantined_at)
+				SELECT ?, id, data, ?, ? FROM `+record.table+` WHERE id = ?`,
+				record.table, issue.Problem, time.Now().UnixNano(), record.id)
+			if err != nil {
+				return fmt.Errorf("failed to quarantine %s: %w", issue.Path, err)
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM `+record.table+` WHERE id = ?`, record.id); err != nil {
+				return fmt.Errorf("failed to quarantine %s: %w", issue.Path, err)
+			}
+			issue.Quarantined = sqliteCorruptTable
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// sqliteCorruptRecord is a row scan found corrupt, and its issue in the report
+type sqliteCorruptRecord struct {
+	table string
+	id    string
+	issue int
+}
+
+// scan checks the database file and every plan and execution log
+func (s *SQLiteOperationStore) scan(ctx context.Context) (*StoreReport, []sqliteCorruptRecord, error) {
+	report := &StoreReport{Issues: make([]StoreIssue, 0)}
+
+	rows, err := s.db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check database integrity: %w", err)
+	}
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to read integrity check: %w", err)
+		}
+		if result != "ok" {
+			report.Issues = append(report.Issues, StoreIssue{Path: s.path, Kind: "database", Problem: result})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to check database integrity: %w", err)
+	}
+
+	var corrupt []sqliteCorruptRecord
+	for _, table := range []string{"plans", "execution_logs"} {
+		problems, checked, err := s.checkRecords(ctx, table)
+		if err != nil {
+			return nil, nil, err
+		}
+		report.Checked += checked
+
+		for _, problem := range problems {
+			corrupt = append(corrupt, sqliteCorruptRecord{table: table, id: problem.id, issue: len(report.Issues)})
+			report.Issues = append(report.Issues, StoreIssue{
+				Path:    table + "/" + problem.id,
+				Kind:    table,
+				Problem: problem.problem,
+			})
+		}
+	}
+
+	return report, corrupt, nil
+}
+
+// sqliteRecordProblem is what is wrong with one row
+type sqliteRecordProblem struct {
+	id      string
+	problem string
+}
+
+// checkRecords decodes every row of table and returns the ones that cannot be
+// used, along with how many rows were read. The ID inside each record must
+// match its row, since lookups go by the row's ID.
+func (s *SQLiteOperationStore) checkRecords(ctx context.Context, table string) ([]sqliteRecordProblem, int, error) {
+	typeColumn := "'' AS type"
+	if table == "plans" {
+		typeColumn = "type"
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT id, `+typeColumn+`, data FROM `+table)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var problems []sqliteRecordProblem
+	checked := 0
+	for rows.Next() {
+		var id, recordType string
+		var data []byte
+		if err := rows.Scan(&id, &recordType, &data); err != nil {
+			return nil, 0, fmt.Errorf("failed to read %s row: %w", table, err)
+		}
+		checked++
+
+		var record interface{}
+		var recordID func() string
+		switch PlanType(recordType) {
+		case PlanTypeReorganization:
+			plan := &ReorganizationPlan{}
+			record, recordID = plan, func() string { return plan.ID }
+		case PlanTypeCleanup:
+			plan := &CleanupPlan{}
+			record, recordID = plan, func() string { return plan.ID }
+		case PlanTypeRenaming:
+			plan := &RenamingPlan{}
+			record, recordID = plan, func() string { return plan.ID }
+		case PlanTypeDeduplication:
+			plan := &DeduplicationPlan{}
+			record, recordID = plan, func() string { return plan.ID }
+		case "":
+			log := &ExecutionLog{}
+			record, recordID = log, func() string { return log.Key() }
+		default:
+			problems = append(problems, sqliteRecordProblem{id, fmt.Sprintf("unknown plan type %q", recordType)})
+			continue
+		}
+
+		if err := json.Unmarshal(data, record); err != nil {
+			problems = append(problems, sqliteRecordProblem{id, fmt.Sprintf("invalid JSON: %v", err)})
+			continue
+		}
+		if recordID() != id {
+			problems = append(problems, sqliteRecordProblem{id, fmt.Sprintf("record ID %q does not match its row", recordID())})
+		}
+	}
+
+	return problems, checked, rows.Err()
+}
+
+// Clear removes all stored data (useful for testing)
+func (s *SQLiteOperationStore) Clear() error {
+	return s.inTx(context.Background(), func(tx *sql.Tx) error {
+		for _, table := range []string{"plans", "operations", "execution_logs"} {
+			if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+				return fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
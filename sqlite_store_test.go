@@ -0,0 +1,300 @@
+package curator
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteOperationStore {
+	t.Helper()
+	store, err := NewSQLiteOperationStore(filepath.Join(t.TempDir(), SQLiteDatabaseName))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteOperationStore_Plans(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	plan := &ReorganizationPlan{
+		ID:        "plan-1",
+		Timestamp: time.Now().Add(-time.Hour),
+		Moves:     []Move{{ID: "move-1", Source: "/a.txt", Destination: "/docs/a.txt", Type: FileMove}},
+		Rationale: "Test",
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	cleanup := &CleanupPlan{
+		ID:        "cleanup-1",
+		Timestamp: time.Now(),
+		Deletions: []Deletion{{ID: "delete-1", Path: "/tmp.tmp", Size: 3, Hash: "abc"}},
+	}
+	if err := store.SaveCleanupPlan(context.Background(), cleanup); err != nil {
+		t.Fatalf("Failed to save cleanup plan: %v", err)
+	}
+
+	retrieved, err := store.GetPlan(context.Background(), "plan-1")
+	if err != nil {
+		t.Fatalf("Failed to get plan: %v", err)
+	}
+	if len(retrieved.Moves) != 1 || retrieved.Moves[0].Destination != "/docs/a.txt" || retrieved.Rationale != "Test" {
+		t.Errorf("Unexpected plan: %+v", retrieved)
+	}
+
+	retrievedCleanup, err := store.GetCleanupPlan(context.Background(), "cleanup-1")
+	if err != nil {
+		t.Fatalf("Failed to get cleanup plan: %v", err)
+	}
+	if retrievedCleanup.Deletions[0].Hash != "abc" {
+		t.Errorf("Expected deletion hash to round-trip, got %+v", retrievedCleanup.Deletions[0])
+	}
+
+	// Lookups are typed: a cleanup plan is not a reorganization plan
+	if _, err := store.GetPlan(context.Background(), "cleanup-1"); err == nil {
+		t.Error("Expected error getting a cleanup plan as a reorganization plan")
+	}
+	if _, err := store.GetPlan(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for missing plan")
+	}
+
+	summaries, err := store.ListPlans(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list plans: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 plans, got %d", len(summaries))
+	}
+	if summaries[0].ID != "cleanup-1" || summaries[0].Type != PlanTypeCleanup {
+		t.Errorf("Expected newest plan first, got %+v", summaries[0])
+	}
+	if summaries[1].Type != PlanTypeReorganization || summaries[1].MoveCount != 1 {
+		t.Errorf("Unexpected summary: %+v", summaries[1])
+	}
+}
+
+func TestSQLiteOperationStore_Operations(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	now := time.Now()
+	for i, id := range []string{"op-1", "op-2"} {
+		op := &Operation{ID: id, Type: "move", Data: []byte(`{"id":"` + id + `"}`), Timestamp: now.Add(time.Duration(i) * time.Second)}
+		if err := store.LogOperation(context.Background(), op); err != nil {
+			t.Fatalf("Failed to log operation: %v", err)
+		}
+	}
+
+	if err := store.MarkOperationComplete(context.Background(), "op-1"); err != nil {
+		t.Fatalf("Failed to mark operation complete: %v", err)
+	}
+	if err := store.MarkOperationComplete(context.Background(), "missing"); err == nil {
+		t.Error("Expected error marking a missing operation complete")
+	}
+
+	pending, err := store.GetPendingOperations(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get pending operations: %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != "op-2" || string(pending[0].Data) != `{"id":"op-2"}` {
+		t.Errorf("Expected only op-2 to be pending, got %+v", pending)
+	}
+}
+
+func TestSQLiteOperationStore_ExecutionLogs(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	older := &ExecutionLog{PlanID: "plan-1", Timestamp: time.Now().Add(-time.Minute), Status: StatusCompleted}
+	newer := &ExecutionLog{
+		PlanID:    "plan-2",
+		Timestamp: time.Now(),
+		Status:    StatusPartial,
+		Failed:    []FailedMove{{MoveID: "move-1", Error: "boom"}},
+	}
+	for _, log := range []*ExecutionLog{older, newer} {
+		if err := store.SaveExecutionLog(context.Background(), log); err != nil {
+			t.Fatalf("Failed to save execution log: %v", err)
+		}
+	}
+
+	history, err := store.GetExecutionHistory(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get execution history: %v", err)
+	}
+	if len(history) != 2 || history[0].PlanID != "plan-2" || history[0].Failed[0].Error != "boom" {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+
+	partial, err := store.GetExecutionLogsByStatus(context.Background(), StatusPartial)
+	if err != nil {
+		t.Fatalf("Failed to query logs by status: %v", err)
+	}
+	if len(partial) != 1 || partial[0].PlanID != "plan-2" {
+		t.Errorf("Expected only plan-2 to be partial, got %+v", partial)
+	}
+}
+
+func TestSQLiteOperationStore_VerifyAndRepair(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+
+	if err := store.SavePlan(ctx, &ReorganizationPlan{ID: "reorg-1", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+	for _, id := range []string{"reorg-1-exec-1", "reorg-2-exec-1"} {
+		if err := store.SaveExecutionLog(ctx, &ExecutionLog{ID: id, PlanID: id[:7], Timestamp: time.Now()}); err != nil {
+			t.Fatalf("Failed to save execution log: %v", err)
+		}
+	}
+
+	// A truncated plan and a truncated log of another plan
+	if _, err := store.db.Exec(`INSERT INTO plans (id, type, created_at, item_count, data) VALUES ('cleanup-1', 'cleanup', 0, 0, '{"id": "cle')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.db.Exec(`UPDATE execution_logs SET data = '{"id": "reo' WHERE id = 'reorg-2-exec-1'`); err != nil {
+		t.Fatal(err)
+	}
+
+	if history, err := store.GetExecutionHistory(ctx); err != nil || len(history) != 1 {
+		t.Errorf("Expected the execution history to skip the corrupt log, got %v, %v", history, err)
+	}
+	if _, err := store.GetExecutionLogs(ctx, "reorg-2"); err == nil || !strings.Contains(err.Error(), "store repair") {
+		t.Errorf("Expected the plan's own logs to report the corrupt log, got %v", err)
+	}
+
+	report, err := ExecuteStoreVerify(CommandOptions{Store: store})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if report.Checked != 4 || len(report.Issues) != 2 {
+		t.Fatalf("Expected 2 issues in 4 records, got %d checked, %+v", report.Checked, report.Issues)
+	}
+
+	report, err = ExecuteStoreRepair(CommandOptions{Store: store})
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	for _, issue := range report.Issues {
+		if issue.Quarantined == "" {
+			t.Errorf("Expected %s to be quarantined", issue.Path)
+		}
+	}
+	var quarantined int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM corrupt_records`).Scan(&quarantined); err != nil || quarantined != 2 {
+		t.Errorf("Expected 2 quarantined records, got %d, %v", quarantined, err)
+	}
+
+	if report, err := store.Verify(); err != nil || len(report.Issues) != 0 {
+		t.Errorf("Expected a clean store after repair, got %+v, %v", report, err)
+	}
+	if logs, err := store.GetExecutionLogs(ctx, "reorg-2"); err != nil || len(logs) != 0 {
+		t.Errorf("Expected the corrupt log to be gone after repair, got %v, %v", logs, err)
+	}
+}
+
+func TestSQLiteOperationStore_ReopenKeepsDataAndSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), SQLiteDatabaseName)
+
+	store, err := NewSQLiteOperationStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create SQLite store: %v", err)
+	}
+	if err := store.LogOperation(context.Background(), &Operation{ID: "op-1", Type: "move", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Failed to log operation: %v", err)
+	}
+	store.Close()
+
+	reopened, err := NewSQLiteOperationStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen SQLite store: %v", err)
+	}
+	defer reopened.Close()
+
+	version, err := reopened.SchemaVersion()
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if version != len(sqliteMigrations) {
+		t.Errorf("Expected schema version %d, got %d", len(sqliteMigrations), version)
+	}
+
+	pending, err := reopened.GetPendingOperations(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get pending operations: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("Expected pending operation to survive reopening, got %d", len(pending))
+	}
+}
+
+func TestCreateCommandOptions_SQLiteStore(t *testing.T) {
+	config := Configuration{
+		AI:         AIConfig{Provider: "mock"},
+		FileSystem: FileSystemConfig{Type: "memory"},
+		StoreDir:   t.TempDir(),
+		StoreType:  StoreTypeSQLite,
+	}
+
+	opts, err := CreateCommandOptions(config)
+	if err != nil {
+		t.Fatalf("Failed to create command options: %v", err)
+	}
+	store, ok := opts.Store.(*SQLiteOperationStore)
+	if !ok {
+		t.Fatalf("Expected SQLite store, got %T", opts.Store)
+	}
+	defer store.Close()
+
+	config.StoreType = "bogus"
+	if _, err := CreateCommandOptions(config); err == nil {
+		t.Error("Expected error for unknown store type")
+	}
+}
+
+func TestSQLiteOperationStore_MigratesExecutionLogsToAttempts(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), SQLiteDatabaseName)
+
+	// Build a version 1 database holding a log keyed by plan ID
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup := []string{
+		`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at INTEGER NOT NULL)`,
+		sqliteMigrations[0],
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (1, 0)`,
+		`INSERT INTO execution_logs (plan_id, status, started_at, data)
+			VALUES ('plan-1', 'COMPLETED', 1, '{"PlanID": "plan-1", "Status": "COMPLETED"}')`,
+	}
+	for _, stmt := range setup {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to set up version 1 database: %v", err)
+		}
+	}
+	db.Close()
+
+	store, err := NewSQLiteOperationStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	defer store.Close()
+
+	newer := &ExecutionLog{ID: "plan-1-exec-2", PlanID: "plan-1", Timestamp: time.Now(), Status: StatusPartial}
+	if err := store.SaveExecutionLog(context.Background(), newer); err != nil {
+		t.Fatalf("Failed to save execution log: %v", err)
+	}
+
+	logs, err := store.GetExecutionLogs(context.Background(), "plan-1")
+	if err != nil {
+		t.Fatalf("Failed to get execution logs: %v", err)
+	}
+	if len(logs) != 2 || logs[0].ID != "plan-1-exec-2" || logs[1].Key() != "plan-1" {
+		t.Errorf("Expected the migrated log and the new attempt, got %+v", logs)
+	}
+}
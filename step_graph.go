@@ -0,0 +1,101 @@
+package curator
+
+import (
+	"path"
+	"sort"
+)
+
+// stepGraph orders the steps of an execution. A step depends on every earlier
+// step that touches the same path, a folder above one of its paths or a path
+// below one of them: a folder is created before files move into it, a folder
+// move finishes before its children are moved, and a folder is removed only
+// after everything has moved out of it.
+type stepGraph struct {
+	dependents [][]int // Steps waiting for each step
+	waiting    []int   // Unfinished dependencies of each step
+	ready      []int   // Steps free to start, in plan order
+}
+
+// newStepGraph builds the graph of the queued steps, given as increasing
+// indexes into steps. Steps that are not queued never hold anything up.
+func newStepGraph(steps []executionStep, queued []int) *stepGraph {
+	g := &stepGraph{
+		dependents: make([][]int, len(steps)),
+		waiting:    make([]int, len(steps)),
+	}
+
+	touched := make(map[string][]int) // Steps by the paths they touch
+	below := make(map[string][]int)   // Steps by the folders above their paths
+	for _, i := range queued {
+		var paths []string
+		for _, p := range steps[i].paths {
+			if p != "" {
+				paths = append(paths, normalizePlanPath(p))
+			}
+		}
+
+		before := make(map[int]bool)
+		for _, p := range paths {
+			for _, j := range touched[p] {
+				before[j] = true
+			}
+			for _, j := range below[p] {
+				before[j] = true
+			}
+			for _, dir := range ancestors(p) {
+				for _, j := range touched[dir] {
+					before[j] = true
+				}
+			}
+		}
+		for j := range before {
+			g.dependents[j] = append(g.dependents[j], i)
+		}
+		g.waiting[i] = len(before)
+		if len(before) == 0 {
+			g.ready = append(g.ready, i)
+		}
+
+		for _, p := range paths {
+			touched[p] = append(touched[p], i)
+			for _, dir := range ancestors(p) {
+				below[dir] = append(below[dir], i)
+			}
+		}
+	}
+
+	return g
+}
+
+// next takes the first step that is free to start
+func (g *stepGraph) next() (int, bool) {
+	if len(g.ready) == 0 {
+		return 0, false
+	}
+	i := g.ready[0]
+	g.ready = g.ready[1:]
+	return i, true
+}
+
+// done records that step i finished, freeing the steps that waited only for it
+func (g *stepGraph) done(i int) {
+	for _, d := range g.dependents[i] {
+		g.waiting[d]--
+		if g.waiting[d] == 0 {
+			at := sort.SearchInts(g.ready, d)
+			g.ready = append(g.ready, 0)
+			copy(g.ready[at+1:], g.ready[at:])
+			g.ready[at] = d
+		}
+	}
+}
+
+// ancestors returns the folders above a normalized plan path, nearest first
+func ancestors(p string) []string {
+	var dirs []string
+	for p != "/" {
+		p = path.Dir(p)
+		dirs = append(dirs, p)
+	}
+	return dirs
+}
@@ -0,0 +1,55 @@
+package curator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStepGraph_Order(t *testing.T) {
+	moves := []Move{
+		{ID: "move-1", Destination: "/Docs", Type: CreateFolder},
+		{ID: "move-2", Source: "/a.txt", Destination: "Docs/a.txt", Type: FileMove},
+		{ID: "move-3", Source: "/b.txt", Destination: "/Docs/b.txt", Type: FileMove},
+		{ID: "move-4", Source: "/Old", Destination: "/Archive/Old", Type: FolderMove},
+		{ID: "move-5", Source: "/Archive/Old/c.txt", Destination: "/Docs/c.txt", Type: FileMove},
+		{ID: "move-6", Destination: "/Empty", Type: RemoveFolder},
+		{ID: "move-7", Destination: "/Old", Type: CreateFolder},
+	}
+	steps := make([]executionStep, len(moves))
+	queued := make([]int, len(moves))
+	for i, move := range moves {
+		steps[i] = executionStep{id: move.ID, paths: []string{move.Source, move.Destination}}
+		queued[i] = i
+	}
+	graph := newStepGraph(steps, queued)
+
+	// Independent steps are free at once, in plan order
+	var started []string
+	take := func() {
+		for {
+			i, ok := graph.next()
+			if !ok {
+				return
+			}
+			started = append(started, steps[i].id)
+		}
+	}
+	take()
+	if fmt.Sprint(started) != "[move-1 move-4 move-6]" {
+		t.Fatalf("Expected the folder creation, folder move and removal to start first, got %v", started)
+	}
+
+	// Files wait for the folder they move into, and for the folder move above them
+	started = nil
+	graph.done(0)
+	take()
+	if fmt.Sprint(started) != "[move-2 move-3]" {
+		t.Fatalf("Expected moves into /Docs after creating it, got %v", started)
+	}
+	started = nil
+	graph.done(3)
+	take()
+	if fmt.Sprint(started) != "[move-5 move-7]" {
+		t.Errorf("Expected moves below /Old after the folder move, got %v", started)
+	}
+}
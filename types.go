@@ -1,18 +1,35 @@
 package curator
 
 import (
+	"context"
+	"errors"
 	"io"
 	"time"
 )
 
 // FileSystem abstraction for any filesystem-like backend
 type FileSystem interface {
-	List(path string) ([]FileInfo, error)
-	Read(path string) (io.ReadCloser, error)
-	Move(source, destination string) error
-	CreateFolder(path string) error
-	Delete(path string) error
-	Exists(path string) (bool, error)
+	List(ctx context.Context, path string) ([]FileInfo, error)
+	Read(ctx context.Context, path string) (io.ReadCloser, error)
+	Move(ctx context.Context, source, destination string) error
+	CreateFolder(ctx context.Context, path string) error
+	Delete(ctx context.Context, path string) error
+	Exists(ctx context.Context, path string) (bool, error)
+}
+
+// TreeLister is implemented by filesystems that can list a whole tree more
+// cheaply than folder by folder. ListTree returns everything below path, each
+// folder before its contents; ok is false when the tree should be walked with
+// List instead.
+type TreeLister interface {
+	ListTree(ctx context.Context, path string) (files []FileInfo, ok bool, err error)
+}
+
+// FileStater is implemented by filesystems that can look up a single path
+// directly, including paths List would not return as they are. Stat returns
+// nil when nothing is at path.
+type FileStater interface {
+	Stat(ctx context.Context, path string) (FileInfo, error)
 }
 
 // FileInfo represents a file or folder
@@ -22,172 +39,351 @@ type FileInfo interface {
 	IsDir() bool
 	Size() int64
 	ModTime() time.Time
-	Hash() string   // For deduplication
+	Hash() string // For deduplication
 	MimeType() string
 }
 
 // AIAnalyzer for generating reorganization plans
 type AIAnalyzer interface {
-	AnalyzeForReorganization(files []FileInfo) (*ReorganizationPlan, error)
-	AnalyzeForDuplicates(files []FileInfo) (*DuplicationReport, error)
-	AnalyzeForCleanup(files []FileInfo) (*CleanupPlan, error)
-	AnalyzeForRenaming(files []FileInfo) (*RenamingPlan, error)
+	AnalyzeForReorganization(ctx context.Context, files []FileInfo) (*ReorganizationPlan, error)
+	AnalyzeForDuplicates(ctx context.Context, files []FileInfo) (*DuplicationReport, error)
+	AnalyzeForCleanup(ctx context.Context, files []FileInfo) (*CleanupPlan, error)
+	AnalyzeForRenaming(ctx context.Context, files []FileInfo) (*RenamingPlan, error)
 }
 
 // Core data structures
 type ReorganizationPlan struct {
-	ID        string
-	Timestamp time.Time
-	Moves     []Move
-	Summary   Summary
-	Rationale string
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	Moves     []Move    `json:"moves"`
+	Summary   Summary   `json:"summary"`
+	Rationale string    `json:"rationale"`
+	// ValidationIssues were found in the analyzer's plan and repaired before
+	// it was saved: moves with errors were dropped, late folders created first
+	ValidationIssues []PlanIssue `json:"validationIssues"`
+	// DerivedFrom is the plan this one was reviewed from; only its approved
+	// moves run
+	DerivedFrom string `json:"derivedFrom,omitempty"`
+}
+
+// runsMove reports whether move runs when the plan is applied. Rejected moves
+// never run, and a reviewed plan runs only the moves that were approved.
+func (p *ReorganizationPlan) runsMove(move Move) bool {
+	if p.DerivedFrom != "" {
+		return move.Approval == ApprovalApproved
+	}
+	return move.Approval != ApprovalRejected
 }
 
 type Move struct {
-	ID          string
-	Source      string
-	Destination string
-	Reason      string
-	Type        MoveType
-	FileCount   int // For folder moves
+	ID          string   `json:"id"`
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Reason      string   `json:"reason"`
+	Type        MoveType `json:"type"`
+	FileCount   int      `json:"fileCount"` // For folder moves
+	// Approval is set when the plan has been reviewed; rejected moves are
+	// never executed
+	Approval ApprovalState `json:"approval,omitempty"`
 }
 
+// ApprovalState records the review decision for a move
+type ApprovalState string
+
+const (
+	ApprovalPending  ApprovalState = ""
+	ApprovalApproved ApprovalState = "APPROVED"
+	ApprovalRejected ApprovalState = "REJECTED"
+)
+
 type MoveType string
 
 const (
 	FileMove     MoveType = "FILE_MOVE"
 	FolderMove   MoveType = "FOLDER_MOVE"
 	CreateFolder MoveType = "CREATE_FOLDER"
+	// RemoveFolder deletes Destination if it is empty; only rollback plans use it
+	RemoveFolder MoveType = "REMOVE_FOLDER"
 )
 
 type Summary struct {
-	FoldersCreated int
-	FilesMoved     int
-	FoldersMovedDeduplicated int
-	DepthReduction string
-	OrganizationImprovement string
+	FoldersCreated           int    `json:"foldersCreated"`
+	FilesMoved               int    `json:"filesMoved"`
+	FoldersMovedDeduplicated int    `json:"foldersMovedDeduplicated"`
+	DepthReduction           string `json:"depthReduction"`
+	OrganizationImprovement  string `json:"organizationImprovement"`
 }
 
-// Execution tracking
+// Execution tracking. Every attempt to execute a plan gets its own log, so
+// retrying a plan never overwrites the record of an earlier run.
 type ExecutionLog struct {
-	PlanID    string
-	Timestamp time.Time
-	Status    ExecutionStatus
-	Completed []CompletedMove
-	Failed    []FailedMove
-	Skipped   []SkippedMove
+	ID          string          `json:"id"` // Execution ID; empty for logs written before execution IDs existed
+	PlanID      string          `json:"planId"`
+	Timestamp   time.Time       `json:"timestamp"`        // When the execution started
+	EndTime     time.Time       `json:"endTime,omitzero"` // When the execution finished; zero while in progress
+	ResumedFrom string          `json:"resumedFrom"`      // Execution this one continued with --resume; carried-over steps keep their original timestamps
+	Status      ExecutionStatus `json:"status"`
+	Completed   []CompletedMove `json:"completed"`
+	Failed      []FailedMove    `json:"failed"`
+	Skipped     []SkippedMove   `json:"skipped"`
+}
+
+// Key returns the ID a log is stored under. Logs written before execution IDs
+// existed are keyed by their plan ID.
+func (l *ExecutionLog) Key() string {
+	if l.ID != "" {
+		return l.ID
+	}
+	return l.PlanID
 }
 
 type ExecutionStatus string
 
 const (
-	StatusInProgress ExecutionStatus = "IN_PROGRESS"
-	StatusCompleted  ExecutionStatus = "COMPLETED"
-	StatusPartial    ExecutionStatus = "PARTIAL"
-	StatusFailed     ExecutionStatus = "FAILED"
+	StatusInProgress  ExecutionStatus = "IN_PROGRESS"
+	StatusCompleted   ExecutionStatus = "COMPLETED"
+	StatusPartial     ExecutionStatus = "PARTIAL"
+	StatusFailed      ExecutionStatus = "FAILED"
+	StatusInterrupted ExecutionStatus = "INTERRUPTED" // Cancelled before every step ran; can be resumed
 )
 
 type CompletedMove struct {
-	MoveID    string
-	Timestamp time.Time
+	MoveID    string    `json:"moveId"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type FailedMove struct {
-	MoveID    string
-	Timestamp time.Time
-	Error     string
+	MoveID    string      `json:"moveId"`
+	Timestamp time.Time   `json:"timestamp"`
+	Error     string      `json:"error"`
+	Kind      FailureKind `json:"kind,omitempty"` // Set when the failure needs attention before a retry can succeed
 }
 
+// FailureKind classifies failed operations that will keep failing until
+// something outside curator changes
+type FailureKind string
+
+const (
+	FailureStorageQuota FailureKind = "STORAGE_QUOTA" // The filesystem is out of storage
+)
+
 type SkippedMove struct {
-	MoveID    string
-	Timestamp time.Time
-	Reason    string
+	MoveID    string    `json:"moveId"`
+	Timestamp time.Time `json:"timestamp"`
+	Reason    string    `json:"reason"`
+}
+
+// RecoveryReport describes what recovering the write-ahead log did
+type RecoveryReport struct {
+	Operations []RecoveredOperation `json:"operations"`
+	Executions []*ExecutionLog      `json:"executions"` // Execution logs updated with recovered outcomes
 }
 
+// RecoveredOperation is the outcome of recovering one pending operation
+type RecoveredOperation struct {
+	OperationID string          `json:"operationId"`
+	ExecutionID string          `json:"executionId"`
+	StepID      string          `json:"stepId"`
+	Type        string          `json:"type"`
+	Outcome     RecoveryOutcome `json:"outcome"`
+	Detail      string          `json:"detail"`
+}
+
+type RecoveryOutcome string
+
+const (
+	// RecoveryAlreadyApplied means the operation had finished before the crash
+	RecoveryAlreadyApplied RecoveryOutcome = "ALREADY_APPLIED"
+	// RecoveryCompleted means the operation was executed during recovery
+	RecoveryCompleted RecoveryOutcome = "COMPLETED"
+	// RecoverySkipped means the operation conflicts with the current state
+	RecoverySkipped RecoveryOutcome = "SKIPPED"
+	// RecoveryFailed means the operation failed again and is still pending
+	RecoveryFailed RecoveryOutcome = "FAILED"
+)
+
+// ErrPlanNotFound is wrapped by the errors OperationStore returns when no plan
+// of the requested type has the ID
+var ErrPlanNotFound = errors.New("plan not found")
+
 // OperationStore persists plans and execution logs
 type OperationStore interface {
-	SavePlan(plan *ReorganizationPlan) error
-	GetPlan(id string) (*ReorganizationPlan, error)
-	ListPlans() ([]*PlanSummary, error)
+	SavePlan(ctx context.Context, plan *ReorganizationPlan) error
+	GetPlan(ctx context.Context, id string) (*ReorganizationPlan, error)
+	ListPlans(ctx context.Context) ([]*PlanSummary, error)
+
+	// Cleanup plans
+	SaveCleanupPlan(ctx context.Context, plan *CleanupPlan) error
+	GetCleanupPlan(ctx context.Context, id string) (*CleanupPlan, error)
+
+	// Renaming plans
+	SaveRenamingPlan(ctx context.Context, plan *RenamingPlan) error
+	GetRenamingPlan(ctx context.Context, id string) (*RenamingPlan, error)
+
+	// Deduplication plans
+	SaveDeduplicationPlan(ctx context.Context, plan *DeduplicationPlan) error
+	GetDeduplicationPlan(ctx context.Context, id string) (*DeduplicationPlan, error)
 
 	// Write-ahead log
-	LogOperation(op *Operation) error
-	GetPendingOperations() ([]*Operation, error)
-	MarkOperationComplete(id string) error
+	LogOperation(ctx context.Context, op *Operation) error
+	GetPendingOperations(ctx context.Context) ([]*Operation, error)
+	MarkOperationComplete(ctx context.Context, id string) error
 
 	// Execution history
-	SaveExecutionLog(log *ExecutionLog) error
-	GetExecutionHistory() ([]*ExecutionLog, error)
+	SaveExecutionLog(ctx context.Context, log *ExecutionLog) error
+	GetExecutionHistory(ctx context.Context) ([]*ExecutionLog, error)
+	GetExecutionLogs(ctx context.Context, planID string) ([]*ExecutionLog, error) // Every attempt for a plan, newest first
+}
+
+// VerifiableStore is an OperationStore that can check its own records
+type VerifiableStore interface {
+	// Verify reports corrupt records without changing anything
+	Verify() (*StoreReport, error)
+	// Repair moves corrupt records out of the way and reports what it moved
+	Repair() (*StoreReport, error)
+}
+
+// StoreReport is the result of verifying or repairing a store
+type StoreReport struct {
+	Checked int          `json:"checked"` // Records read
+	Issues  []StoreIssue `json:"issues"`
+}
+
+// StoreIssue is a stored record that cannot be used
+type StoreIssue struct {
+	Path        string `json:"path"`
+	Kind        string `json:"kind"` // Which kind of record, e.g. "plans" or "operations"
+	Problem     string `json:"problem"`
+	Quarantined string `json:"quarantined"` // Where Repair moved the record; empty after Verify
 }
 
 type PlanSummary struct {
-	ID        string
-	Timestamp time.Time
-	Status    string
-	FileCount int
-	MoveCount int
+	ID        string    `json:"id"`
+	Type      PlanType  `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Status    string    `json:"status"`
+	FileCount int       `json:"fileCount"`
+	MoveCount int       `json:"moveCount"`
 }
 
+type PlanType string
+
+const (
+	PlanTypeReorganization PlanType = "reorganization"
+	PlanTypeCleanup        PlanType = "cleanup"
+	PlanTypeRenaming       PlanType = "renaming"
+	PlanTypeDeduplication  PlanType = "deduplication"
+)
+
 type Operation struct {
 	ID        string
 	Type      string
 	Data      []byte
 	Timestamp time.Time
+	// The step that logged the operation, so recovery can record its outcome.
+	// Empty for operations logged before executions had IDs.
+	PlanID      string
+	ExecutionID string
+	StepID      string
 }
 
 // Additional plan types for different operations
 type DuplicationReport struct {
-	ID         string
-	Timestamp  time.Time
-	Duplicates []DuplicateGroup
-	Summary    DuplicationSummary
+	ID         string             `json:"id"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Duplicates []DuplicateGroup   `json:"duplicates"`
+	Summary    DuplicationSummary `json:"summary"`
 }
 
 type DuplicateGroup struct {
-	Hash  string
-	Files []string
-	Size  int64
+	Hash        string   `json:"hash"`
+	Files       []string `json:"files"`
+	Size        int64    `json:"size"`
+	Explanation string   `json:"explanation"` // Optional note from the analyzer about why the copies exist
 }
 
 type DuplicationSummary struct {
-	TotalDuplicates int
-	SpaceSaved      int64
+	TotalDuplicates int   `json:"totalDuplicates"`
+	SpaceSaved      int64 `json:"spaceSaved"`
+}
+
+// DeduplicationPlan keeps one copy of each duplicate group and removes the rest
+type DeduplicationPlan struct {
+	ID            string             `json:"id"`
+	Timestamp     time.Time          `json:"timestamp"`
+	Policy        KeeperPolicy       `json:"policy"`
+	Action        DuplicateAction    `json:"action"`
+	QuarantineDir string             `json:"quarantineDir"` // Root of the quarantine folder when Action is DuplicateQuarantine
+	Groups        []DuplicateGroup   `json:"groups"`
+	Removals      []DuplicateRemoval `json:"removals"`
+	Summary       DuplicationSummary `json:"summary"`
 }
 
+// DuplicateRemoval removes one redundant copy of a file
+type DuplicateRemoval struct {
+	ID          string `json:"id"`
+	Path        string `json:"path"`
+	KeeperPath  string `json:"keeperPath"` // The copy that is kept; it must still match Hash when the removal runs
+	Hash        string `json:"hash"`
+	Size        int64  `json:"size"`
+	Destination string `json:"destination"` // Quarantine path; empty when the copy is deleted
+}
+
+// KeeperPolicy decides which copy of a duplicate group is kept
+type KeeperPolicy string
+
+const (
+	KeepOldest       KeeperPolicy = "oldest"
+	KeepNewest       KeeperPolicy = "newest"
+	KeepShortestPath KeeperPolicy = "shortest-path"
+	KeepPreferred    KeeperPolicy = "preferred" // First copy under a preferred root
+)
+
+// DuplicateAction is what happens to the copies that are not kept
+type DuplicateAction string
+
+const (
+	DuplicateDelete     DuplicateAction = "delete"
+	DuplicateQuarantine DuplicateAction = "quarantine"
+)
+
 type CleanupPlan struct {
-	ID        string
-	Timestamp time.Time
-	Deletions []Deletion
-	Summary   CleanupSummary
+	ID        string         `json:"id"`
+	Timestamp time.Time      `json:"timestamp"`
+	Deletions []Deletion     `json:"deletions"`
+	Summary   CleanupSummary `json:"summary"`
 }
 
 type Deletion struct {
-	ID     string
-	Path   string
-	Reason string
-	Size   int64
+	ID     string `json:"id"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	Size   int64  `json:"size"`
+	Hash   string `json:"hash"` // Content hash at analysis time, re-checked before deleting
 }
 
 type CleanupSummary struct {
-	FilesDeleted int
-	SpaceFreed   int64
+	FilesDeleted int   `json:"filesDeleted"`
+	SpaceFreed   int64 `json:"spaceFreed"`
 }
 
 type RenamingPlan struct {
-	ID        string
-	Timestamp time.Time
-	Renames   []Rename
-	Summary   RenamingSummary
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Renames   []Rename        `json:"renames"`
+	Summary   RenamingSummary `json:"summary"`
 }
 
 type Rename struct {
-	ID      string
-	OldName string
-	NewName string
-	Reason  string
+	ID      string `json:"id"`
+	OldPath string `json:"oldPath"` // Full path of the file before renaming
+	NewPath string `json:"newPath"` // Full path after renaming, always in the same folder as OldPath
+	OldName string `json:"oldName"`
+	NewName string `json:"newName"`
+	Reason  string `json:"reason"`
 }
 
 type RenamingSummary struct {
-	FilesRenamed int
-	Pattern      string
-}
\ No newline at end of file
+	FilesRenamed int    `json:"filesRenamed"`
+	Pattern      string `json:"pattern"`
+}
@@ -0,0 +1,417 @@
+package curator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// IssueSeverity says whether a plan issue makes a move invalid
+type IssueSeverity string
+
+const (
+	// SeverityError marks a move that cannot run as planned
+	SeverityError IssueSeverity = "ERROR"
+	// SeverityWarning marks a move that runs but deserves a look
+	SeverityWarning IssueSeverity = "WARNING"
+)
+
+// IssueCode identifies what is wrong with a move
+type IssueCode string
+
+const (
+	IssueInvalidMove             IssueCode = "INVALID_MOVE"
+	IssueDuplicateMoveID         IssueCode = "DUPLICATE_MOVE_ID"
+	IssuePathTraversal           IssueCode = "PATH_TRAVERSAL"
+	IssueUnknownSource           IssueCode = "UNKNOWN_SOURCE"
+	IssueSourceAlreadyMoved      IssueCode = "SOURCE_ALREADY_MOVED"
+	IssueWrongMoveType           IssueCode = "WRONG_MOVE_TYPE"
+	IssueMoveToSelf              IssueCode = "MOVE_TO_SELF"
+	IssueDestinationInsideSource IssueCode = "DESTINATION_INSIDE_SOURCE"
+	IssueDuplicateDestination    IssueCode = "DUPLICATE_DESTINATION"
+	IssueDestinationExists       IssueCode = "DESTINATION_EXISTS"
+	IssueFolderExists            IssueCode = "FOLDER_EXISTS"
+	IssueParentNotFolder         IssueCode = "PARENT_NOT_FOLDER"
+	IssueFolderCreatedLate       IssueCode = "FOLDER_CREATED_AFTER_USE"
+	IssueUnknownApproval         IssueCode = "UNKNOWN_APPROVAL"
+)
+
+// PlanIssue is a problem ValidatePlan found with one move of a plan
+type PlanIssue struct {
+	MoveID   string        `json:"moveId"`
+	Index    int           `json:"index"` // Position of the move in the plan
+	Code     IssueCode     `json:"code"`
+	Severity IssueSeverity `json:"severity"`
+	Message  string        `json:"message"`
+}
+
+// PlanValidation lists the issues found in a plan, in move order
+type PlanValidation struct {
+	PlanID string      `json:"planId"`
+	Issues []PlanIssue `json:"issues"`
+}
+
+// HasErrors reports whether any move of the plan is invalid
+func (v *PlanValidation) HasErrors() bool {
+	return len(v.Errors()) > 0
+}
+
+// Errors returns the issues that make a move invalid
+func (v *PlanValidation) Errors() []PlanIssue {
+	var found []PlanIssue
+	for _, issue := range v.Issues {
+		if issue.Severity == SeverityError {
+			found = append(found, issue)
+		}
+	}
+	return found
+}
+
+// PlanValidationError is returned when a plan is refused because some of its
+// moves are invalid
+type PlanValidationError struct {
+	Validation *PlanValidation
+}
+
+func (e *PlanValidationError) Error() string {
+	invalid := e.Validation.Errors()
+
+	details := make([]string, 0, 4)
+	for i, issue := range invalid {
+		if i == 3 {
+			details = append(details, fmt.Sprintf("and %d more", len(invalid)-3))
+			break
+		}
+		details = append(details, fmt.Sprintf("%s: %s", issue.MoveID, issue.Message))
+	}
+	return fmt.Sprintf("plan %s has %d invalid moves: %s", e.Validation.PlanID, len(invalid), strings.Join(details, "; "))
+}
+
+// ValidatePlan simulates plan against the scanned files and reports, per move,
+// everything that would make the move fail or be skipped when the plan is
+// applied. The simulation follows the moves in order, so a move sees the tree
+// as the earlier moves left it.
+func ValidatePlan(plan *ReorganizationPlan, files []FileInfo) *PlanValidation {
+	validation := &PlanValidation{PlanID: plan.ID, Issues: make([]PlanIssue, 0)}
+	tree := newPlanTree(files)
+
+	report := func(index int, code IssueCode, severity IssueSeverity, format string, args ...interface{}) {
+		validation.Issues = append(validation.Issues, PlanIssue{
+			MoveID:   plan.Moves[index].ID,
+			Index:    index,
+			Code:     code,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// Where each folder is first created, to catch moves that use it earlier
+	createdAt := make(map[string]int)
+	for i, move := range plan.Moves {
+		if move.Type != CreateFolder || !plan.runsMove(move) || hasTraversal(move.Destination) {
+			continue
+		}
+		dest := normalizePlanPath(move.Destination)
+		if _, seen := createdAt[dest]; !seen {
+			createdAt[dest] = i
+		}
+	}
+	lateFolders := make(map[string]bool)
+
+	moveIDs := make(map[string]bool)
+	claimed := make(map[string]string) // Destination -> ID of the move that claimed it
+
+	for i, move := range plan.Moves {
+		if move.ID == "" {
+			report(i, IssueInvalidMove, SeverityError, "move %d has no ID", i+1)
+			continue
+		}
+		if moveIDs[move.ID] {
+			report(i, IssueDuplicateMoveID, SeverityError, "move ID is used more than once")
+			continue
+		}
+		moveIDs[move.ID] = true
+
+		// Anything but an explicit rejection would run, so a misspelled or
+		// unknown decision must not pass for an approval
+		switch move.Approval {
+		case ApprovalPending, ApprovalApproved, ApprovalRejected:
+		default:
+			report(i, IssueUnknownApproval, SeverityError, "unknown approval state %q", move.Approval)
+			continue
+		}
+
+		// Moves that never run cannot conflict with anything
+		if !plan.runsMove(move) {
+			continue
+		}
+		if hasTraversal(move.Source) || hasTraversal(move.Destination) {
+			report(i, IssuePathTraversal, SeverityError, "path contains '..': %s → %s", move.Source, move.Destination)
+			continue
+		}
+		if move.Destination == "" {
+			report(i, IssueInvalidMove, SeverityError, "move has no destination")
+			continue
+		}
+		dest := normalizePlanPath(move.Destination)
+
+		switch move.Type {
+		case CreateFolder:
+			isDir, exists := tree.entries[dest]
+			if exists && !isDir {
+				report(i, IssueDestinationExists, SeverityError, "a file already exists at %s", dest)
+				continue
+			}
+			// A folder that is only there because a move used it early is
+			// reported as created late instead
+			if exists && !lateFolders[dest] {
+				report(i, IssueFolderExists, SeverityWarning, "%s already exists", dest)
+				continue
+			}
+			if parent, ok := tree.fileAncestor(dest); ok {
+				report(i, IssueParentNotFolder, SeverityError, "%s is a file, not a folder", parent)
+				continue
+			}
+			tree.addFolder(dest)
+
+		case RemoveFolder:
+			if isDir, exists := tree.entries[dest]; exists && !isDir {
+				report(i, IssueWrongMoveType, SeverityError, "%s is a file, not a folder", dest)
+				continue
+			}
+			tree.remove(dest)
+
+		case FileMove, FolderMove:
+			if move.Source == "" {
+				report(i, IssueInvalidMove, SeverityError, "move has no source")
+				continue
+			}
+			source := normalizePlanPath(move.Source)
+
+			isDir, exists := tree.entries[source]
+			if !exists {
+				if _, scanned := tree.scanned[source]; scanned {
+					report(i, IssueSourceAlreadyMoved, SeverityError, "%s was already moved by an earlier move", source)
+				} else {
+					report(i, IssueUnknownSource, SeverityError, "%s was not found in the scan", source)
+				}
+				continue
+			}
+			if move.Type == FileMove && isDir {
+				report(i, IssueWrongMoveType, SeverityError, "%s is a folder; use a folder move", source)
+				continue
+			}
+			if move.Type == FolderMove && !isDir {
+				report(i, IssueWrongMoveType, SeverityError, "%s is a file; use a file move", source)
+				continue
+			}
+			if dest == source {
+				report(i, IssueMoveToSelf, SeverityError, "source and destination are the same: %s", source)
+				continue
+			}
+			if isDir && isWithin(dest, source) {
+				report(i, IssueDestinationInsideSource, SeverityError, "%s cannot be moved into itself (%s)", source, dest)
+				continue
+			}
+			if _, exists := tree.entries[dest]; exists {
+				if other, ok := claimed[dest]; ok {
+					report(i, IssueDuplicateDestination, SeverityError, "%s is also the destination of %s", dest, other)
+				} else {
+					report(i, IssueDestinationExists, SeverityError, "%s already exists", dest)
+				}
+				continue
+			}
+			if parent, ok := tree.fileAncestor(dest); ok {
+				report(i, IssueParentNotFolder, SeverityError, "%s is a file, not a folder", parent)
+				continue
+			}
+
+			// The executor creates missing parents itself, but a CreateFolder
+			// that comes later in the plan was meant to run first
+			for dir := path.Dir(dest); dir != "/"; dir = path.Dir(dir) {
+				if at, ok := createdAt[dir]; ok && at > i && !tree.entries[dir] && !lateFolders[dir] {
+					lateFolders[dir] = true
+					report(at, IssueFolderCreatedLate, SeverityWarning, "%s is created after %s uses it", dir, move.ID)
+				}
+			}
+
+			tree.move(source, dest)
+			claimed[dest] = move.ID
+
+		default:
+			report(i, IssueInvalidMove, SeverityError, "unknown move type %q", move.Type)
+		}
+	}
+
+	// Report issues in plan order, however they were found
+	sort.SliceStable(validation.Issues, func(i, j int) bool {
+		return validation.Issues[i].Index < validation.Issues[j].Index
+	})
+
+	return validation
+}
+
+// RepairPlan returns a copy of plan that validates cleanly: folders created
+// after a move that uses them are created first instead, folders that already
+// exist are not created again, and invalid moves are dropped. It also returns
+// the issues of the original plan, which say what was repaired.
+func RepairPlan(plan *ReorganizationPlan, files []FileInfo) (*ReorganizationPlan, *PlanValidation) {
+	original := ValidatePlan(plan, files)
+
+	late := make(map[int]bool)
+	existing := make(map[int]bool)
+	for _, issue := range original.Issues {
+		switch issue.Code {
+		case IssueFolderCreatedLate:
+			late[issue.Index] = true
+		case IssueFolderExists:
+			existing[issue.Index] = true
+		}
+	}
+
+	repaired := *plan
+	repaired.Moves = make([]Move, 0, len(plan.Moves))
+	for i, move := range plan.Moves {
+		if late[i] {
+			repaired.Moves = append(repaired.Moves, move)
+		}
+	}
+	// Parents first, so nested folders are created in order
+	sort.SliceStable(repaired.Moves, func(i, j int) bool {
+		return strings.Count(normalizePlanPath(repaired.Moves[i].Destination), "/") <
+			strings.Count(normalizePlanPath(repaired.Moves[j].Destination), "/")
+	})
+	for i, move := range plan.Moves {
+		if !late[i] && !existing[i] {
+			repaired.Moves = append(repaired.Moves, move)
+		}
+	}
+
+	// Dropping a move can invalidate the moves that depended on it, so repeat
+	// until nothing is left to drop
+	for {
+		invalid := make(map[int]bool)
+		for _, issue := range ValidatePlan(&repaired, files).Errors() {
+			invalid[issue.Index] = true
+		}
+		if len(invalid) == 0 {
+			break
+		}
+
+		kept := make([]Move, 0, len(repaired.Moves)-len(invalid))
+		for i, move := range repaired.Moves {
+			if !invalid[i] {
+				kept = append(kept, move)
+			}
+		}
+		repaired.Moves = kept
+	}
+
+	if len(repaired.Moves) != len(plan.Moves) {
+		recountSummary(&repaired)
+	}
+
+	return &repaired, original
+}
+
+// recountSummary updates the folder and file counts of a plan's summary after
+// moves were dropped or rejected
+func recountSummary(plan *ReorganizationPlan) {
+	plan.Summary.FoldersCreated = 0
+	plan.Summary.FilesMoved = 0
+	for _, move := range plan.Moves {
+		if !plan.runsMove(move) {
+			continue
+		}
+		switch move.Type {
+		case CreateFolder:
+			plan.Summary.FoldersCreated++
+		case FileMove:
+			plan.Summary.FilesMoved++
+		}
+	}
+}
+
+// normalizePlanPath turns a plan path into a clean absolute path. Analyzers
+// sometimes return paths relative to the root ("Documents/a.pdf").
+func normalizePlanPath(p string) string {
+	return path.Clean("/" + strings.ReplaceAll(p, "\\", "/"))
+}
+
+// hasTraversal reports whether a plan path climbs out of a folder with ".."
+func hasTraversal(p string) bool {
+	for _, segment := range strings.Split(strings.ReplaceAll(p, "\\", "/"), "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether p is dir or lies inside it
+func isWithin(p, dir string) bool {
+	return p == dir || dir == "/" || strings.HasPrefix(p, dir+"/")
+}
+
+// planTree is the simulated filesystem ValidatePlan runs a plan against. It
+// maps clean absolute paths to whether they are folders.
+type planTree struct {
+	entries map[string]bool
+	scanned map[string]bool
+}
+
+func newPlanTree(files []FileInfo) *planTree {
+	tree := &planTree{entries: map[string]bool{"/": true}}
+	for _, file := range files {
+		p := normalizePlanPath(file.Path())
+		tree.addFolder(path.Dir(p))
+		tree.entries[p] = file.IsDir()
+	}
+
+	tree.scanned = make(map[string]bool, len(tree.entries))
+	for p, isDir := range tree.entries {
+		tree.scanned[p] = isDir
+	}
+	return tree
+}
+
+// addFolder adds dir and any missing parents
+func (t *planTree) addFolder(dir string) {
+	for ; ; dir = path.Dir(dir) {
+		if _, exists := t.entries[dir]; exists {
+			return
+		}
+		t.entries[dir] = true
+	}
+}
+
+// fileAncestor returns the closest ancestor of p that is a file, if any
+func (t *planTree) fileAncestor(p string) (string, bool) {
+	for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+		if isDir, exists := t.entries[dir]; exists && !isDir {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// move moves source, and everything inside it, to destination
+func (t *planTree) move(source, destination string) {
+	t.addFolder(path.Dir(destination))
+	for p, isDir := range t.entries {
+		if isWithin(p, source) {
+			delete(t.entries, p)
+			t.entries[destination+strings.TrimPrefix(p, source)] = isDir
+		}
+	}
+}
+
+// remove removes p and everything inside it
+func (t *planTree) remove(p string) {
+	for entry := range t.entries {
+		if isWithin(entry, p) && entry != "/" {
+			delete(t.entries, entry)
+		}
+	}
+}
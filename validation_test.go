@@ -0,0 +1,313 @@
+package curator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedPlanAnalyzer returns the same reorganization plan whatever it is given
+type fixedPlanAnalyzer struct {
+	*MockAIAnalyzer
+	plan *ReorganizationPlan
+}
+
+func (a *fixedPlanAnalyzer) AnalyzeForReorganization(ctx context.Context, files []FileInfo) (*ReorganizationPlan, error) {
+	plan := *a.plan
+	return &plan, nil
+}
+
+func newValidationFileSystem(t *testing.T) (*MemoryFileSystem, []FileInfo) {
+	t.Helper()
+	fs := NewMemoryFileSystem()
+	fs.AddFile("/a.txt", []byte("a"), "text/plain")
+	fs.AddFile("/b.txt", []byte("b"), "text/plain")
+	fs.AddFile("/Projects/site/index.html", []byte("<html>"), "text/html")
+	fs.AddFile("/Docs/existing.txt", []byte("e"), "text/plain")
+
+	files, err := getAllFilesRecursively(context.Background(), fs, "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to scan files: %v", err)
+	}
+	return fs, files
+}
+
+func TestValidatePlan(t *testing.T) {
+	_, files := newValidationFileSystem(t)
+
+	plan := &ReorganizationPlan{
+		ID: "bad-plan",
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "Text/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Text/a.txt", Type: FileMove},
+			{ID: "move-3", Source: "/missing.txt", Destination: "/Text/missing.txt", Type: FileMove},
+			{ID: "move-4", Source: "/Projects", Destination: "/Projects/Archive/Projects", Type: FolderMove},
+			{ID: "move-5", Source: "/Projects/site", Destination: "/Web/site", Type: FileMove},
+			{ID: "move-6", Source: "/b.txt", Destination: "/../b.txt", Type: FileMove},
+			{ID: "move-7", Source: "/a.txt", Destination: "/Other/a.txt", Type: FileMove},
+			{ID: "move-8", Source: "/b.txt", Destination: "/Docs/existing.txt", Type: FileMove},
+			{ID: "move-9", Destination: "Text", Type: CreateFolder},
+			{ID: "move-10", Source: "/Projects/site/index.html", Destination: "/Web/index.html", Type: FileMove, Approval: "approved"},
+			{ID: "move-11", Destination: "/Docs", Type: CreateFolder},
+		},
+	}
+
+	validation := ValidatePlan(plan, files)
+
+	want := map[string]IssueCode{
+		"move-2":  IssueDuplicateDestination,
+		"move-3":  IssueUnknownSource,
+		"move-4":  IssueDestinationInsideSource,
+		"move-5":  IssueWrongMoveType,
+		"move-6":  IssuePathTraversal,
+		"move-7":  IssueSourceAlreadyMoved,
+		"move-8":  IssueDestinationExists,
+		"move-9":  IssueFolderCreatedLate,
+		"move-10": IssueUnknownApproval,
+		"move-11": IssueFolderExists,
+	}
+	if len(validation.Issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %+v", len(want), validation.Issues)
+	}
+	for i, issue := range validation.Issues {
+		if want[issue.MoveID] != issue.Code {
+			t.Errorf("Expected %s for %s, got %s (%s)", want[issue.MoveID], issue.MoveID, issue.Code, issue.Message)
+		}
+		if i > 0 && validation.Issues[i-1].Index > issue.Index {
+			t.Errorf("Expected issues in plan order, got %s after %s", issue.MoveID, validation.Issues[i-1].MoveID)
+		}
+	}
+
+	if errs := validation.Errors(); len(errs) != 8 {
+		t.Errorf("Expected 8 errors, got %d", len(errs))
+	}
+	for _, issue := range validation.Issues {
+		if (issue.Code == IssueFolderCreatedLate || issue.Code == IssueFolderExists) && issue.Severity != SeverityWarning {
+			t.Errorf("Expected %s to be a warning, got %s", issue.Code, issue.Severity)
+		}
+	}
+}
+
+func TestRepairPlan(t *testing.T) {
+	_, files := newValidationFileSystem(t)
+
+	plan := &ReorganizationPlan{
+		ID: "repairable-plan",
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Text/Notes/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/Projects", Destination: "/Projects/Old", Type: FolderMove},
+			// Only valid if move-2 could run
+			{ID: "move-3", Source: "/Projects/Old/site", Destination: "/Web/site", Type: FolderMove},
+			{ID: "move-4", Source: "/b.txt", Destination: "/Text/b.txt", Type: FileMove},
+			{ID: "move-5", Destination: "/Text/Notes", Type: CreateFolder},
+			{ID: "move-6", Destination: "/Text", Type: CreateFolder},
+			// Already exists, so there is nothing to create
+			{ID: "move-7", Destination: "/Docs", Type: CreateFolder},
+		},
+		Summary: Summary{FoldersCreated: 3, FilesMoved: 2},
+	}
+
+	repaired, original := RepairPlan(plan, files)
+
+	if !original.HasErrors() {
+		t.Fatal("Expected the original plan to have errors")
+	}
+	if validation := ValidatePlan(repaired, files); len(validation.Issues) != 0 {
+		t.Fatalf("Expected the repaired plan to validate cleanly, got %+v", validation.Issues)
+	}
+
+	var ids []string
+	for _, move := range repaired.Moves {
+		ids = append(ids, move.ID)
+	}
+	want := []string{"move-6", "move-5", "move-1", "move-4"}
+	if len(ids) != len(want) {
+		t.Fatalf("Expected moves %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("Expected moves %v, got %v", want, ids)
+		}
+	}
+
+	if len(plan.Moves) != 7 {
+		t.Error("RepairPlan must not modify the original plan")
+	}
+	if repaired.Summary.FoldersCreated != 2 || repaired.Summary.FilesMoved != 2 {
+		t.Errorf("Unexpected summary after repair: %+v", repaired.Summary)
+	}
+}
+
+func TestExecuteReorganize_RepairsOrRejectsInvalidPlans(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+	analyzer := &fixedPlanAnalyzer{
+		MockAIAnalyzer: NewMockAIAnalyzer(),
+		plan: &ReorganizationPlan{
+			ID:        "generated-plan",
+			Timestamp: time.Now(),
+			Moves: []Move{
+				{ID: "move-1", Source: "/a.txt", Destination: "/Text/a.txt", Type: FileMove},
+				{ID: "move-2", Source: "/ghost.txt", Destination: "/Text/ghost.txt", Type: FileMove},
+			},
+		},
+	}
+	opts := CommandOptions{FileSystem: fs, Store: store, Analyzer: analyzer, Reporter: NewReporter()}
+
+	_, err := ExecuteReorganize(context.Background(), opts, ReorganizeOptions{Strict: true})
+	var validationErr *PlanValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a strict reorganize to reject the plan, got %v", err)
+	}
+	if summaries, _ := store.ListPlans(context.Background()); len(summaries) != 0 {
+		t.Error("A rejected plan must not be saved")
+	}
+
+	plan, err := ExecuteReorganize(context.Background(), opts, ReorganizeOptions{})
+	if err != nil {
+		t.Fatalf("Reorganize failed: %v", err)
+	}
+	if len(plan.Moves) != 1 || plan.Moves[0].ID != "move-1" {
+		t.Errorf("Expected the invalid move to be dropped, got %+v", plan.Moves)
+	}
+	if !strings.HasPrefix(plan.ID, "reorg-") {
+		t.Errorf("Expected the plan to get its own ID instead of the analyzer's, got %q", plan.ID)
+	}
+	if len(plan.ValidationIssues) != 1 || plan.ValidationIssues[0].Code != IssueUnknownSource {
+		t.Errorf("Expected the repair to be recorded, got %+v", plan.ValidationIssues)
+	}
+
+	saved, err := store.GetPlan(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("Failed to get saved plan: %v", err)
+	}
+	if len(saved.Moves) != 1 {
+		t.Errorf("Expected the repaired plan to be saved, got %d moves", len(saved.Moves))
+	}
+}
+
+func TestExecuteApply_RefusesInvalidPlans(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Analyzer: NewMockAIAnalyzer(), Reporter: NewReporter()}
+
+	plan := &ReorganizationPlan{
+		ID:        "stale-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Text/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Text/b.txt", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	// b.txt disappeared after the plan was generated
+	if err := fs.Delete(context.Background(), "/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{})
+	var validationErr *PlanValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected apply to refuse the plan, got %v", err)
+	}
+	if exists, _ := fs.Exists(context.Background(), "/Text/a.txt"); exists {
+		t.Error("No move should run when the plan is refused")
+	}
+	if logs, _ := store.GetExecutionLogs(context.Background(), plan.ID); len(logs) != 0 {
+		t.Error("A refused plan must not start an execution")
+	}
+}
+
+func TestExecuteApply_ResumeValidatesRemainingMoves(t *testing.T) {
+	fs, _ := newValidationFileSystem(t)
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Analyzer: NewMockAIAnalyzer(), Reporter: NewReporter()}
+
+	plan := &ReorganizationPlan{
+		ID:        "resumable-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/a.txt", Destination: "/Text/a.txt", Type: FileMove},
+			{ID: "move-2", Source: "/b.txt", Destination: "/Text/b.txt", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	// An earlier execution moved a.txt before it stopped
+	if err := fs.Move(context.Background(), "/a.txt", "/Text/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	execLog := &ExecutionLog{
+		ID:        plan.ID + "-exec-1",
+		PlanID:    plan.ID,
+		Timestamp: time.Now(),
+		EndTime:   time.Now(),
+		Status:    StatusPartial,
+		Completed: []CompletedMove{{MoveID: "move-1", Timestamp: time.Now()}},
+	}
+	if err := store.SaveExecutionLog(context.Background(), execLog); err != nil {
+		t.Fatalf("Failed to save execution log: %v", err)
+	}
+
+	if _, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{}); err == nil {
+		t.Error("Expected starting over to be refused, since a.txt already moved")
+	}
+
+	result, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{Resume: true})
+	if err != nil {
+		t.Fatalf("Expected resume to validate only the remaining moves, got %v", err)
+	}
+	if result.Status != StatusCompleted {
+		t.Errorf("Expected the resumed execution to complete, got %s", result.Status)
+	}
+}
+
+// listCountingFileSystem records which folders were listed
+type listCountingFileSystem struct {
+	*MemoryFileSystem
+	listed map[string]bool
+}
+
+func (f *listCountingFileSystem) List(ctx context.Context, path string) ([]FileInfo, error) {
+	f.listed[path] = true
+	return f.MemoryFileSystem.List(ctx, path)
+}
+
+func TestExecuteApply_ValidatesOnlyPlanPaths(t *testing.T) {
+	memFS, _ := newValidationFileSystem(t)
+	memFS.AddFile("/Unrelated/deep/file.txt", []byte("u"), "text/plain")
+	fs := &listCountingFileSystem{MemoryFileSystem: memFS, listed: make(map[string]bool)}
+	store := NewMemoryOperationStore()
+	opts := CommandOptions{FileSystem: fs, Store: store, Analyzer: NewMockAIAnalyzer(), Reporter: NewReporter()}
+
+	// move-2 refers to a file where move-1 puts it
+	plan := &ReorganizationPlan{
+		ID:        "nested-plan",
+		Timestamp: time.Now(),
+		Moves: []Move{
+			{ID: "move-1", Source: "/Projects", Destination: "/Archive/Projects", Type: FolderMove},
+			{ID: "move-2", Source: "/Archive/Projects/site/index.html", Destination: "/Web/index.html", Type: FileMove},
+		},
+	}
+	if err := store.SavePlan(context.Background(), plan); err != nil {
+		t.Fatalf("Failed to save plan: %v", err)
+	}
+
+	result, err := ExecuteApply(context.Background(), opts, plan.ID, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("Expected the plan to validate, got %v", err)
+	}
+	if result.Status != StatusCompleted {
+		t.Errorf("Expected the plan to complete, got %s", result.Status)
+	}
+	if fs.listed["/Unrelated"] || fs.listed["/Unrelated/deep"] {
+		t.Error("Expected apply to look up only the paths the plan touches")
+	}
+}